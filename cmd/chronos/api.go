@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/samuelstranges/chronos/internal/api"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/pkg/views"
+	"github.com/jroimartin/gocui"
+)
+
+// runAPI implements `chronos api`, serving the JSON-RPC API without the TUI
+func runAPI(args []string) {
+	fs := flag.NewFlagSet("api", flag.ExitOnError)
+	var dbPath string
+	var socketPath string
+	var debugMode bool
+	fs.StringVar(&dbPath, "db", "", "Custom database file path (default: ~/.local/share/chronos/data.db)")
+	fs.StringVar(&socketPath, "socket", "", "Unix socket path (overrides api_socket in config)")
+	fs.BoolVar(&debugMode, "debug", false, "Enable debug logging")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: chronos api [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Serves the JSON-RPC API at %s and schemas at %s on a Unix socket\n\n", api.RPCPath, api.SchemaPath)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg := loadConfig()
+	if socketPath != "" {
+		cfg.APISocket = socketPath
+	}
+
+	database := openDatabase(resolveDatabasePath(cfg, dbPath), debugMode)
+	defer database.CloseDatabase()
+
+	socket := config.GetAPISocketPath(cfg)
+	if err := os.MkdirAll(filepath.Dir(socket), 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	apiServer := api.NewServer(newEventManager(database, cfg))
+	if err := apiServer.ListenUnix(socket); err != nil {
+		log.Fatal("API server failed: ", err)
+	}
+	defer apiServer.Close()
+	log.Printf("Serving JSON-RPC API on %s", socket)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+}
+
+// startTUIAPIServer serves the API while the TUI runs. Calls are executed on the
+// gocui main loop, which then redraws every view with the changed data.
+func startTUIAPIServer(g *gocui.Gui, av *views.AppView, cfg *config.Config) *api.Server {
+	apiServer := api.NewServer(av.EventManager)
+	apiServer.SetRunner(func(fn func()) {
+		done := make(chan struct{})
+		g.Update(func(g *gocui.Gui) error {
+			defer close(done)
+			fn()
+			return nil
+		})
+		<-done
+	})
+
+	socket := config.GetAPISocketPath(cfg)
+	err := os.MkdirAll(filepath.Dir(socket), 0755)
+	if err == nil {
+		err = apiServer.ListenUnix(socket)
+	}
+	if err != nil {
+		g.Update(func(g *gocui.Gui) error {
+			av.ShowErrorMessage(g, "API Disabled", err.Error())
+			return nil
+		})
+	}
+
+	return apiServer
+}
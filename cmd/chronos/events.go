@@ -0,0 +1,591 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/api"
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/quickadd"
+)
+
+// Exit codes for the event subcommands
+const (
+	exitError    = 1
+	exitUsage    = 2
+	exitConflict = 3
+	exitNotFound = 4
+)
+
+// cliContext holds the state shared by the event subcommands
+type cliContext struct {
+	fs           *flag.FlagSet
+	config       *config.Config
+	database     *database.Database
+	eventManager *eventmanager.EventManager
+
+	dbPath     string
+	debugMode  bool
+	jsonOutput bool
+	format     string
+	tmpl       *template.Template
+}
+
+// newCLIContext creates a flag set with the flags shared by every event subcommand
+func newCLIContext(name, usage string) *cliContext {
+	ctx := &cliContext{fs: flag.NewFlagSet(name, flag.ExitOnError)}
+	ctx.fs.StringVar(&ctx.dbPath, "db", "", "Custom database file path (default: ~/.local/share/chronos/data.db)")
+	ctx.fs.BoolVar(&ctx.debugMode, "debug", false, "Enable debug logging")
+	ctx.fs.BoolVar(&ctx.jsonOutput, "json", false, "Print events as JSON")
+	ctx.fs.StringVar(&ctx.format, "format", "", "Print each event with a Go template, e.g. '{{.ID}} {{.Name}} {{.Start.Format \"15:04\"}}'")
+	ctx.fs.Usage = func() {
+		fmt.Fprintf(ctx.fs.Output(), "Usage: chronos %s\n\n", usage)
+		ctx.fs.PrintDefaults()
+		fmt.Fprintf(ctx.fs.Output(), "\nTemplate fields: .ID .Name .Description .Location .Start .End .DurationHours .FrequencyDays .Occurrences .Color\n")
+		fmt.Fprintf(ctx.fs.Output(), "Exit codes: %d error, %d usage, %d conflict, %d not found\n", exitError, exitUsage, exitConflict, exitNotFound)
+	}
+	return ctx
+}
+
+// parse parses the flags (interspersed with positional arguments) and opens the database
+func (ctx *cliContext) parse(args []string) []string {
+	var positional []string
+	for {
+		ctx.fs.Parse(args)
+		args = ctx.fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if ctx.jsonOutput && ctx.format != "" {
+		ctx.fail(exitUsage, "--json and --format cannot be used together")
+	}
+	if ctx.format != "" {
+		tmpl, err := template.New("format").Parse(ctx.format)
+		if err != nil {
+			ctx.fail(exitUsage, "invalid --format template: %v", err)
+		}
+		ctx.tmpl = tmpl
+	}
+
+	ctx.config = loadConfig()
+	ctx.database = openDatabase(resolveDatabasePath(ctx.config, ctx.dbPath), ctx.debugMode)
+	ctx.eventManager = newEventManager(ctx.database, ctx.config)
+
+	return positional
+}
+
+// fail prints an error and exits with the given code
+func (ctx *cliContext) fail(code int, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "chronos %s: %s\n", ctx.fs.Name(), fmt.Sprintf(format, args...))
+	if ctx.database != nil {
+		ctx.database.CloseDatabase()
+	}
+	os.Exit(code)
+}
+
+// printEvents prints events as text, JSON or through the --format template
+func (ctx *cliContext) printEvents(events []*calendar.Event) {
+	apiEvents := api.FromCalendarEvents(events)
+
+	switch {
+	case ctx.jsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(apiEvents); err != nil {
+			ctx.fail(exitError, "%v", err)
+		}
+	case ctx.tmpl != nil:
+		for _, event := range apiEvents {
+			if err := ctx.tmpl.Execute(os.Stdout, event); err != nil {
+				ctx.fail(exitError, "template: %v", err)
+			}
+			fmt.Println()
+		}
+	default:
+		for _, event := range apiEvents {
+			line := fmt.Sprintf("%5d  %s %s-%s  %s", event.ID,
+				event.Start.Format("Mon 2006-01-02"), event.Start.Format("15:04"), event.End.Format("15:04"), event.Name)
+			if event.Location != "" {
+				line += " @ " + event.Location
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// checkOverlaps applies the overlap policy: under forbid it exits with exitConflict if the event
+// overlaps anything other than excludeIds, under warn it prints a warning
+func (ctx *cliContext) checkOverlaps(event *calendar.Event, excludeIds ...int) {
+	policy := ctx.eventManager.GetOverlapPolicy()
+	if policy == eventmanager.OverlapAllow {
+		return
+	}
+
+	overlapping, err := ctx.eventManager.FindOverlappingEvents(event, excludeIds...)
+	if err != nil {
+		ctx.fail(exitError, "failed to check for overlapping events: %v", err)
+	}
+	if len(overlapping) == 0 {
+		return
+	}
+
+	var names []string
+	for _, other := range overlapping {
+		start := other.Time.In(time.Local)
+		names = append(names, fmt.Sprintf("%q (#%d, %s)", other.Name, other.Id, start.Format("2006-01-02 15:04")))
+	}
+	message := fmt.Sprintf("%q at %s overlaps %s", event.Name, event.Time.Format("2006-01-02 15:04"), strings.Join(names, ", "))
+	if policy == eventmanager.OverlapWarn {
+		fmt.Fprintf(os.Stderr, "chronos %s: warning: %s\n", ctx.fs.Name(), message)
+		return
+	}
+	ctx.fail(exitConflict, "%s", message)
+}
+
+// lookupEvent returns the event for an ID argument, exiting with exitNotFound if it does not exist
+func (ctx *cliContext) lookupEvent(arg string) *calendar.Event {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		ctx.fail(exitUsage, "invalid event id %q", arg)
+	}
+	event, err := ctx.eventManager.GetEventById(id)
+	if err != nil {
+		ctx.fail(exitError, "%v", err)
+	}
+	if event == nil {
+		ctx.fail(exitNotFound, "event %d not found", id)
+	}
+	return event
+}
+
+// eventFlags are the event fields accepted by add and edit
+type eventFlags struct {
+	name        string
+	start       string
+	duration    float64
+	end         string
+	location    string
+	description string
+	color       string
+	frequency   int
+	occurrences int
+}
+
+func (ef *eventFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&ef.name, "name", "", "Event name")
+	fs.StringVar(&ef.start, "start", "", "Start time (YYYY-MM-DD HH:MM, RFC 3339, or relative like 'tomorrow 9:00')")
+	fs.Float64Var(&ef.duration, "duration", 0, "Duration in hours (default from config)")
+	fs.StringVar(&ef.end, "end", "", "End time, instead of --duration")
+	fs.StringVar(&ef.location, "location", "", "Event location")
+	fs.StringVar(&ef.description, "description", "", "Event description")
+	fs.StringVar(&ef.color, "color", "", "Colour name ("+strings.Join(calendar.GetColorNames(), ", ")+")")
+	fs.IntVar(&ef.frequency, "frequency", 7, "Days between occurrences when adding a recurring event (-1 for weekdays)")
+	fs.IntVar(&ef.occurrences, "occurrences", 1, "Number of occurrences to add")
+}
+
+// runAdd implements `chronos add`, either from flags or a quick-add phrase such as
+// `chronos add "Lunch with Sam tomorrow 12:30 for 90m at Cafe Roma"`
+func runAdd(args []string) {
+	ctx := newCLIContext("add", "add --name NAME --start TIME [flags]\n       chronos add \"PHRASE\" [flags]")
+	var ef eventFlags
+	var yes, dryRun bool
+	ef.register(ctx.fs)
+	ctx.fs.BoolVar(&yes, "yes", false, "Add a quick-add phrase without asking for confirmation")
+	ctx.fs.BoolVar(&dryRun, "dry-run", false, "Print the events that would be added without saving them")
+	positional := ctx.parse(args)
+	defer ctx.database.CloseDatabase()
+
+	phrase := strings.Join(positional, " ")
+	var apiEvent api.Event
+	if phrase != "" {
+		apiEvent = ctx.quickAddEvent(phrase, &ef)
+	} else {
+		apiEvent = ctx.flagEvent(&ef)
+	}
+
+	event, err := apiEvent.ToCalendarEvent()
+	if err != nil {
+		ctx.fail(exitUsage, "%v", err)
+	}
+
+	occurrences := event.GetReccuringEvents()
+	for i := range occurrences {
+		ctx.checkOverlaps(&occurrences[i])
+	}
+
+	if dryRun {
+		preview := make([]*calendar.Event, len(occurrences))
+		for i := range occurrences {
+			preview[i] = &occurrences[i]
+		}
+		ctx.printEvents(preview)
+		return
+	}
+
+	if phrase != "" && !yes && !confirmQuickAdd(event) {
+		ctx.fail(exitError, "cancelled")
+	}
+
+	// The occurrences are added as a single change, or none of them when one is rejected
+	var added []*calendar.Event
+	var ok bool
+	title, message := ctx.eventManager.CaptureErrors(func() {
+		added, ok = ctx.eventManager.AddEvents(occurrences, fmt.Sprintf("add %d events", len(occurrences)))
+	})
+	if !ok {
+		ctx.fail(exitError, "%s: %s", title, message)
+	}
+
+	ctx.printEvents(added)
+}
+
+// flagEvent builds the event to add from --name, --start and the other flags
+func (ctx *cliContext) flagEvent(ef *eventFlags) api.Event {
+	if ef.name == "" || ef.start == "" {
+		ctx.fail(exitUsage, "--name and --start (or a quick-add phrase) are required")
+	}
+
+	apiEvent := api.Event{
+		Name:          ef.name,
+		Description:   ef.description,
+		Location:      ef.location,
+		DurationHours: ef.duration,
+		FrequencyDays: ef.frequency,
+		Occurrences:   ef.occurrences,
+		Color:         ef.color,
+	}
+	if apiEvent.Color == "" {
+		apiEvent.Color = config.GetDefaultColor(ctx.config)
+	}
+
+	var err error
+	if apiEvent.Start, err = parseCLITime(ef.start, time.Now()); err != nil {
+		ctx.fail(exitUsage, "--start: %v", err)
+	}
+	if ef.end != "" {
+		if apiEvent.End, err = parseCLITime(ef.end, apiEvent.Start); err != nil {
+			ctx.fail(exitUsage, "--end: %v", err)
+		}
+	} else if apiEvent.DurationHours == 0 {
+		apiEvent.DurationHours = config.GetDefaultEventLength(ctx.config)
+	}
+
+	return apiEvent
+}
+
+// quickAddEvent parses a quick-add phrase; any event flags given explicitly override the parsed fields
+func (ctx *cliContext) quickAddEvent(phrase string, ef *eventFlags) api.Event {
+	// Without a time the event starts at the next full hour
+	now := time.Now()
+	defaultStart := now.Truncate(time.Hour).Add(time.Hour)
+
+	parser := quickadd.NewParser(now, defaultStart, config.GetDefaultEventLength(ctx.config))
+	result, err := parser.Parse(phrase)
+	if err != nil {
+		ctx.fail(exitUsage, "%v", err)
+	}
+
+	apiEvent := api.FromCalendarEvent(result.Event())
+	apiEvent.End = time.Time{}
+	if result.Color == 0 && config.GetDefaultColor(ctx.config) != "" {
+		apiEvent.Color = config.GetDefaultColor(ctx.config)
+	}
+
+	ctx.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			apiEvent.Name = ef.name
+		case "location":
+			apiEvent.Location = ef.location
+		case "description":
+			apiEvent.Description = ef.description
+		case "color":
+			apiEvent.Color = ef.color
+		case "duration":
+			apiEvent.DurationHours = ef.duration
+		case "frequency":
+			apiEvent.FrequencyDays = ef.frequency
+		case "occurrences":
+			apiEvent.Occurrences = ef.occurrences
+		case "start":
+			if apiEvent.Start, err = parseCLITime(ef.start, now); err != nil {
+				ctx.fail(exitUsage, "--start: %v", err)
+			}
+		case "end":
+			if apiEvent.End, err = parseCLITime(ef.end, apiEvent.Start); err != nil {
+				ctx.fail(exitUsage, "--end: %v", err)
+			}
+			apiEvent.DurationHours = 0
+		}
+	})
+
+	return apiEvent
+}
+
+// confirmQuickAdd shows what a quick-add phrase was understood as and asks before saving
+func confirmQuickAdd(event *calendar.Event) bool {
+	preview := quickadd.Result{
+		Name:          event.Name,
+		Location:      event.Location,
+		Start:         event.Time,
+		DurationHours: event.DurationHour,
+		FrequencyDays: event.FrequencyDay,
+		Occurrences:   event.Occurence,
+	}
+	fmt.Fprintf(os.Stderr, "%s\nAdd this event? [Y/n] ", preview.Describe())
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		// Nothing to read, e.g. stdin is closed
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
+
+// runEdit implements `chronos edit <id>`; only the given flags are changed
+func runEdit(args []string) {
+	ctx := newCLIContext("edit", "edit <id> [flags]")
+	var ef eventFlags
+	ef.register(ctx.fs)
+	positional := ctx.parse(args)
+	defer ctx.database.CloseDatabase()
+
+	if len(positional) != 1 {
+		ctx.fail(exitUsage, "expected exactly one event id")
+	}
+	existing := ctx.lookupEvent(positional[0])
+
+	apiEvent := api.FromCalendarEvent(existing)
+	apiEvent.End = time.Time{}
+	var err error
+	ctx.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			apiEvent.Name = ef.name
+		case "location":
+			apiEvent.Location = ef.location
+		case "description":
+			apiEvent.Description = ef.description
+		case "color":
+			apiEvent.Color = ef.color
+		case "duration":
+			apiEvent.DurationHours = ef.duration
+		case "start":
+			if apiEvent.Start, err = parseCLITime(ef.start, apiEvent.Start); err != nil {
+				ctx.fail(exitUsage, "--start: %v", err)
+			}
+		case "frequency", "occurrences":
+			ctx.fail(exitUsage, "--%s can only be used with add", f.Name)
+		}
+	})
+	if ef.end != "" {
+		if apiEvent.End, err = parseCLITime(ef.end, apiEvent.Start); err != nil {
+			ctx.fail(exitUsage, "--end: %v", err)
+		}
+		apiEvent.DurationHours = 0
+	}
+
+	event, err := apiEvent.ToCalendarEvent()
+	if err != nil {
+		ctx.fail(exitUsage, "%v", err)
+	}
+	event.Id = existing.Id
+	event.FrequencyDay = existing.FrequencyDay
+	event.Occurence = existing.Occurence
+	if apiEvent.Color == "" {
+		event.Color = existing.Color
+	}
+
+	ctx.checkOverlaps(event, existing.Id)
+
+	var ok bool
+	title, message := ctx.eventManager.CaptureErrors(func() {
+		ok = ctx.eventManager.UpdateEvent(existing.Id, event)
+	})
+	if !ok {
+		ctx.fail(exitError, "%s: %s", title, message)
+	}
+
+	ctx.printEvents([]*calendar.Event{event})
+}
+
+// runRemove implements `chronos rm <id>...`
+func runRemove(args []string) {
+	ctx := newCLIContext("rm", "rm <id>... [flags]")
+	positional := ctx.parse(args)
+	defer ctx.database.CloseDatabase()
+
+	if len(positional) == 0 {
+		ctx.fail(exitUsage, "expected at least one event id")
+	}
+
+	// Resolve every ID first, then delete them as a single change so nothing is deleted if
+	// any are missing
+	var events []*calendar.Event
+	var ids []int
+	for _, arg := range positional {
+		event := ctx.lookupEvent(arg)
+		events = append(events, event)
+		ids = append(ids, event.Id)
+	}
+	if err := ctx.eventManager.DeleteEvents(ids); err != nil {
+		ctx.fail(exitError, "failed to delete events: %v", err)
+	}
+
+	ctx.printEvents(events)
+}
+
+// runList implements `chronos list`
+func runList(args []string) {
+	ctx := newCLIContext("list", "list [--from TIME] [--to TIME] [flags]")
+	var from, to string
+	ctx.fs.StringVar(&from, "from", "today", "Start of range (inclusive)")
+	ctx.fs.StringVar(&to, "to", "+7d", "End of range (exclusive), absolute or relative to --from")
+	positional := ctx.parse(args)
+	defer ctx.database.CloseDatabase()
+
+	if len(positional) > 0 {
+		ctx.fail(exitUsage, "unexpected argument %q", positional[0])
+	}
+
+	start, end := ctx.parseRange(from, to)
+	events, err := ctx.eventManager.GetEventsByDateRange(start, end)
+	if err != nil {
+		ctx.fail(exitError, "%v", err)
+	}
+
+	ctx.printEvents(events)
+}
+
+// runSearch implements `chronos search <query>`
+func runSearch(args []string) {
+	ctx := newCLIContext("search", "search <query> [--from TIME] [--to TIME] [flags]")
+	var from, to string
+	ctx.fs.StringVar(&from, "from", "", "Only match events starting at or after this time")
+	ctx.fs.StringVar(&to, "to", "", "Only match events starting before this time")
+	positional := ctx.parse(args)
+	defer ctx.database.CloseDatabase()
+
+	query := strings.ToLower(strings.TrimSpace(strings.Join(positional, " ")))
+	if query == "" {
+		ctx.fail(exitUsage, "expected a search query")
+	}
+
+	var events []*calendar.Event
+	var err error
+	if from == "" && to == "" {
+		events, err = ctx.eventManager.SearchEventsWithFilters(database.SearchCriteria{Query: query})
+	} else {
+		if from == "" {
+			from = "1970-01-01"
+		}
+		if to == "" {
+			to = "9999-01-01"
+		}
+		start, end := ctx.parseRange(from, to)
+		events, err = ctx.eventManager.GetEventsByDateRange(start, end)
+		events = filterEventsByText(events, query)
+	}
+	if err != nil {
+		ctx.fail(exitError, "%v", err)
+	}
+
+	ctx.printEvents(events)
+	if len(events) == 0 {
+		ctx.fail(exitNotFound, "no events match %q", query)
+	}
+}
+
+// parseRange parses --from and --to, resolving --to relative to --from
+func (ctx *cliContext) parseRange(from, to string) (time.Time, time.Time) {
+	start, err := parseCLITime(from, time.Now())
+	if err != nil {
+		ctx.fail(exitUsage, "--from: %v", err)
+	}
+	end, err := parseCLITime(to, start)
+	if err != nil {
+		ctx.fail(exitUsage, "--to: %v", err)
+	}
+	if !end.After(start) {
+		ctx.fail(exitUsage, "--to must be after --from")
+	}
+	return start, end
+}
+
+// filterEventsByText keeps events whose name, description or location contain the query
+func filterEventsByText(events []*calendar.Event, query string) []*calendar.Event {
+	var matches []*calendar.Event
+	for _, event := range events {
+		if strings.Contains(strings.ToLower(event.Name), query) ||
+			strings.Contains(strings.ToLower(event.Description), query) ||
+			strings.Contains(strings.ToLower(event.Location), query) {
+			matches = append(matches, event)
+		}
+	}
+	return matches
+}
+
+// parseCLITime parses absolute times (see api.ParseTime) and the relative forms
+// "now", "today" (or "t"), "tomorrow", "yesterday" (optionally followed by HH:MM) and
+// "+Nd", "+Nh", "+Nw", "-Nd" which are offsets from base.
+func parseCLITime(value string, base time.Time) (time.Time, error) {
+	raw := strings.TrimSpace(value)
+	value = strings.ToLower(raw)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	if value == "now" {
+		return now, nil
+	}
+
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		unit := value[len(value)-1]
+		n, err := strconv.ParseFloat(value[:len(value)-1], 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid offset %q", value)
+		}
+		switch unit {
+		case 'h':
+			return base.Add(time.Duration(n * float64(time.Hour))), nil
+		case 'd':
+			return base.AddDate(0, 0, int(n)), nil
+		case 'w':
+			return base.AddDate(0, 0, int(n)*7), nil
+		default:
+			return time.Time{}, fmt.Errorf("invalid offset %q (use h, d or w)", value)
+		}
+	}
+
+	days := map[string]int{"today": 0, "t": 0, "tomorrow": 1, "yesterday": -1}
+	fields := strings.Fields(value)
+	if len(fields) > 0 {
+		if offset, ok := days[fields[0]]; ok {
+			day := today.AddDate(0, 0, offset)
+			if len(fields) == 1 {
+				return day, nil
+			}
+			clock, err := time.Parse("15:04", fields[1])
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid time of day %q", fields[1])
+			}
+			return day.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute), nil
+		}
+	}
+
+	return api.ParseTime(raw)
+}
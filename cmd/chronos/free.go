@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/freetime"
+)
+
+// freeSlot is the value passed to --format templates and printed by --json
+type freeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Hours float64   `json:"free_hours"`
+}
+
+// runFree implements `chronos free`
+func runFree(args []string) {
+	ctx := newCLIContext("free", "free --duration DURATION [--from TIME] [--to TIME] [flags]")
+	var duration, from, to, hours, days string
+	var limit int
+	ctx.fs.StringVar(&duration, "duration", "1h", "Minimum length of a slot, e.g. 1.5h, 90m or 1h30m")
+	ctx.fs.StringVar(&from, "from", "now", "Start of the search window")
+	ctx.fs.StringVar(&to, "to", "+7d", "End of the search window, absolute or relative to --from")
+	ctx.fs.StringVar(&hours, "hours", "", "Working hours to search, e.g. 09:00-17:00 (default: working_hours from config)")
+	ctx.fs.StringVar(&days, "days", "", "Days to search, e.g. mon-fri, weekends or all (default: working_days from config)")
+	ctx.fs.IntVar(&limit, "limit", freetime.DefaultLimit, "Maximum number of slots to print")
+	ctx.fs.Usage = func() {
+		fmt.Fprintf(ctx.fs.Output(), "Usage: chronos free --duration DURATION [--from TIME] [--to TIME] [flags]\n\n")
+		ctx.fs.PrintDefaults()
+		fmt.Fprintf(ctx.fs.Output(), "\nTemplate fields: .Start .End .Hours\n")
+		fmt.Fprintf(ctx.fs.Output(), "Exit codes: %d error, %d usage, %d no free slots\n", exitError, exitUsage, exitNotFound)
+	}
+	positional := ctx.parse(args)
+	defer ctx.database.CloseDatabase()
+
+	if len(positional) > 0 {
+		ctx.fail(exitUsage, "unexpected argument %q", positional[0])
+	}
+
+	query := freetime.Query{Limit: limit}
+	var err error
+	if query.Duration, err = freetime.ParseDuration(duration); err != nil {
+		ctx.fail(exitUsage, "--duration: %v", err)
+	}
+	if hours == "" {
+		hours = config.GetWorkingHours(ctx.config)
+	}
+	if query.DayStart, query.DayEnd, err = freetime.ParseHours(hours); err != nil {
+		ctx.fail(exitUsage, "--hours: %v", err)
+	}
+	if days == "" {
+		days = config.GetWorkingDays(ctx.config)
+	}
+	if query.Weekdays, err = freetime.ParseWeekdays(days); err != nil {
+		ctx.fail(exitUsage, "--days: %v", err)
+	}
+	query.From, query.To = ctx.parseRange(from, to)
+
+	slots, err := freetime.Find(ctx.eventManager, query)
+	if err != nil {
+		ctx.fail(exitError, "%v", err)
+	}
+
+	ctx.printSlots(slots)
+	if len(slots) == 0 {
+		ctx.fail(exitNotFound, "no free slots of %s found", duration)
+	}
+}
+
+// printSlots prints free slots as text, JSON or through the --format template
+func (ctx *cliContext) printSlots(slots []freetime.Slot) {
+	output := make([]freeSlot, len(slots))
+	for i, slot := range slots {
+		output[i] = freeSlot{Start: slot.Start, End: slot.End, Hours: slot.Hours()}
+	}
+
+	switch {
+	case ctx.jsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			ctx.fail(exitError, "%v", err)
+		}
+	case ctx.tmpl != nil:
+		for _, slot := range output {
+			if err := ctx.tmpl.Execute(os.Stdout, slot); err != nil {
+				ctx.fail(exitError, "template: %v", err)
+			}
+			fmt.Println()
+		}
+	default:
+		for _, slot := range output {
+			fmt.Printf("%s %s-%s  (%sh free)\n", slot.Start.Format("Mon 2006-01-02"),
+				slot.Start.Format("15:04"), slot.End.Format("15:04"), strconv.FormatFloat(slot.Hours, 'f', -1, 64))
+		}
+	}
+}
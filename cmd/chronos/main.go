@@ -13,17 +13,49 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/samuelstranges/chronos/internal/calendar"
 	"github.com/samuelstranges/chronos/internal/config"
 	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
 	"github.com/samuelstranges/chronos/internal/ics"
+	"github.com/samuelstranges/chronos/internal/keymap"
 	"github.com/samuelstranges/chronos/internal/notifications"
+	"github.com/samuelstranges/chronos/internal/status"
 	"github.com/samuelstranges/chronos/internal/ui"
 	"github.com/samuelstranges/chronos/pkg/views"
 	"github.com/jroimartin/gocui"
 )
 
 func main() {
+	// Subcommands are dispatched before the global flags are parsed
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "api":
+			runAPI(os.Args[2:])
+			return
+		case "add":
+			runAdd(os.Args[2:])
+			return
+		case "edit":
+			runEdit(os.Args[2:])
+			return
+		case "rm":
+			runRemove(os.Args[2:])
+			return
+		case "list":
+			runList(os.Args[2:])
+			return
+		case "search":
+			runSearch(os.Args[2:])
+			return
+		case "free":
+			runFree(os.Args[2:])
+			return
+		}
+	}
+
 	var backupPath string
 	var debugMode bool
 	var dbPath string
@@ -32,39 +64,27 @@ func main() {
 	var agendaFlag bool
 	var testNotificationFlag bool
 	var icsFlag string
+	statusOpts := status.Options{}
 	flag.StringVar(&backupPath, "backup", "", "Backup database to specified location")
 	flag.BoolVar(&debugMode, "debug", false, "Enable debug logging to /tmp/chronos_debug.txt and /tmp/chronos_getevents_debug.txt")
 	flag.StringVar(&dbPath, "db", "", "Custom database file path (default: ~/.local/share/chronos/data.db)")
 	flag.BoolVar(&nextFlag, "next", false, "Return next event")
 	flag.BoolVar(&currentFlag, "current", false, "Return current event (if exists)")
+	flag.StringVar(&statusOpts.Format, "format", "", "Print --next/--current with a Go template, e.g. '{{.Name}} in {{.Until}}'")
+	flag.BoolVar(&statusOpts.JSON, "json", false, "Print --next/--current as JSON (null when there is no event)")
+	flag.IntVar(&statusOpts.Width, "width", 0, "Truncate --next/--current output to this many characters per line")
+	flag.BoolVar(&statusOpts.Color, "color", false, "Colour --next/--current output with the event colour")
+	flag.BoolVar(&statusOpts.Watch, "watch", false, "Keep running and reprint --next/--current whenever the output changes")
+	flag.DurationVar(&statusOpts.Interval, "interval", 15*time.Second, "How often --watch checks for changes")
 	flag.BoolVar(&agendaFlag, "agenda", false, "Export agenda for today or specified date (provide date as next argument in YYYYMMDD format)")
 	flag.BoolVar(&testNotificationFlag, "test-notification", false, "Send a test notification")
 	flag.StringVar(&icsFlag, "ics", "", "Export all events to iCalendar (.ics) file at specified path")
+	flag.Usage = printUsage
 	flag.Parse()
 
-	// Set up cursor restoration on exit
-	setupCursorHandling()
+	cfg := loadConfig()
 
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Printf("Warning: Could not load config, using defaults: %v", err)
-		cfg = config.GetDefaultConfig()
-	}
-
-	// Command line flag takes precedence over config file
-	var dbFilePath string
-	if dbPath != "" {
-		dbFilePath = dbPath
-	} else {
-		dbFilePath = config.GetDatabasePath(cfg)
-	}
-
-	// Create directory for database path if it doesn't exist
-	dbDir := filepath.Dir(dbFilePath)
-	err = os.MkdirAll(dbDir, 0755)
-	if err != nil {
-		log.Fatal(err)
-	}
+	dbFilePath := resolveDatabasePath(cfg, dbPath)
 
 	if backupPath != "" {
 		err := backupDatabase(dbFilePath, backupPath)
@@ -75,21 +95,24 @@ func main() {
 		return
 	}
 
-	database := &database.Database{DebugMode: debugMode}
-	err = database.InitDatabase(dbFilePath)
-	if err != nil {
-		log.Fatal(err)
-	}
+	database := openDatabase(dbFilePath, debugMode)
 	defer database.CloseDatabase()
 
 	// Handle command-line queries
+	if nextFlag || currentFlag {
+		if err := statusOpts.Parse(); err != nil {
+			fmt.Fprintf(os.Stderr, "chronos: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+
 	if nextFlag {
-		handleNextEvent(database)
+		handleNextEvent(database, &statusOpts)
 		return
 	}
 	
 	if currentFlag {
-		handleCurrentEvent(database)
+		handleCurrentEvent(database, &statusOpts)
 		return
 	}
 	
@@ -113,6 +136,20 @@ func main() {
 		return
 	}
 
+	// Check the keybindings and filters before taking over the terminal
+	km, err := keymap.New(config.GetKeybindings(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chronos: keybindings: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	if _, err := config.GetFilters(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "chronos: filters: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	// Set up cursor restoration on exit (after the CLI queries so --watch output stays clean)
+	setupCursorHandling()
+
 	g, err := gocui.NewGui(gocui.Output256)
 	if err != nil {
 		log.Panicln(err)
@@ -125,13 +162,21 @@ func main() {
 	// Set cursor to block shape for better visibility in tmux
 	setCursorBlock()
 
+	g.Mouse = config.IsMouseEnabled(cfg)
+
 	av := views.NewAppView(g, database, cfg)
 	g.SetManager(av)
 
-	if err := ui.InitKeybindings(g, av); err != nil {
+	if err := ui.InitKeybindings(g, av, km); err != nil {
 		log.Panicln(err)
 	}
 
+	// Start the scripting API if enabled; changes made through it refresh the open views
+	if config.IsAPIEnabled(cfg) {
+		apiServer := startTUIAPIServer(g, av, cfg)
+		defer apiServer.Close()
+	}
+
 	// Start notification system
 	notificationManager := notifications.NewNotificationManager(cfg)
 	scheduler := notifications.NewNotificationScheduler(notificationManager, database)
@@ -143,6 +188,68 @@ func main() {
 	}
 }
 
+// printUsage lists the subcommands followed by the global flags
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: chronos [flags]\n       chronos <command> [args]\n\n")
+	fmt.Fprintf(out, "Commands:\n")
+	fmt.Fprintf(out, "  add      Add an event\n")
+	fmt.Fprintf(out, "  edit     Edit an event by id\n")
+	fmt.Fprintf(out, "  rm       Delete events by id\n")
+	fmt.Fprintf(out, "  list     List events in a date range\n")
+	fmt.Fprintf(out, "  search   Search events by name, description or location\n")
+	fmt.Fprintf(out, "  free     Find free slots between events\n")
+	fmt.Fprintf(out, "  serve    Serve an .ics feed and CalDAV collection\n")
+	fmt.Fprintf(out, "  api      Serve the JSON-RPC scripting API\n")
+	fmt.Fprintf(out, "\nRun 'chronos <command> -h' for command flags.\n\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// loadConfig loads the config file, falling back to defaults on error
+func loadConfig() *config.Config {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Printf("Warning: Could not load config, using defaults: %v", err)
+		cfg = config.GetDefaultConfig()
+	}
+	return cfg
+}
+
+// resolveDatabasePath returns the database path and makes sure its directory exists
+func resolveDatabasePath(cfg *config.Config, dbPath string) string {
+	// Command line flag takes precedence over config file
+	var dbFilePath string
+	if dbPath != "" {
+		dbFilePath = dbPath
+	} else {
+		dbFilePath = config.GetDatabasePath(cfg)
+	}
+
+	// Create directory for database path if it doesn't exist
+	dbDir := filepath.Dir(dbFilePath)
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	return dbFilePath
+}
+
+// openDatabase opens the database at the given path or exits on failure
+func openDatabase(dbFilePath string, debugMode bool) *database.Database {
+	db := &database.Database{DebugMode: debugMode}
+	if err := db.InitDatabase(dbFilePath); err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
+
+// newEventManager creates an EventManager that follows the configured overlap policy
+func newEventManager(db *database.Database, cfg *config.Config) *eventmanager.EventManager {
+	em := eventmanager.NewEventManager(db)
+	em.SetOverlapPolicy(eventmanager.OverlapPolicy(config.GetOverlapPolicy(cfg)))
+	return em
+}
+
 func backupDatabase(srcPath, destPath string) error {
 	srcFile, err := os.Open(srcPath)
 	if err != nil {
@@ -186,73 +293,6 @@ func restoreCursor() {
 	fmt.Print("\033[0 q")
 }
 
-// handleNextEvent finds and prints the next upcoming event
-func handleNextEvent(db *database.Database) {
-	events, err := db.GetAllEvents()
-	if err != nil {
-		log.Fatal("Error getting events:", err)
-	}
-
-	now := time.Now()
-	var nextEvent *calendar.Event = nil
-	var nextTime time.Time
-
-	for _, event := range events {
-		// Convert UTC stored time to local time for comparison
-		eventTime := event.Time.Local()
-		if eventTime.After(now) {
-			if nextEvent == nil || eventTime.Before(nextTime) {
-				nextEvent = event
-				nextTime = eventTime
-			}
-		}
-	}
-
-	if nextEvent == nil {
-		fmt.Println("No upcoming events found")
-		return
-	}
-
-	// Convert UTC stored time to local time for display
-	localTime := nextEvent.Time.Local()
-	fmt.Printf("%s at %s\n", nextEvent.Name, localTime.Format("2006-01-02 15:04"))
-	if nextEvent.Description != "" {
-		fmt.Printf("Description: %s\n", nextEvent.Description)
-	}
-	if nextEvent.Location != "" {
-		fmt.Printf("Location: %s\n", nextEvent.Location)
-	}
-}
-
-// handleCurrentEvent finds and prints the current event if one exists
-func handleCurrentEvent(db *database.Database) {
-	events, err := db.GetEventsByDate(time.Now())
-	if err != nil {
-		log.Fatal("Error getting today's events:", err)
-	}
-
-	now := time.Now()
-	
-	for _, event := range events {
-		// Convert UTC stored time to local time for comparison
-		eventStart := event.Time.Local()
-		eventEnd := eventStart.Add(time.Duration(event.DurationHour * float64(time.Hour)))
-		
-		if (now.After(eventStart) || now.Equal(eventStart)) && now.Before(eventEnd) {
-			fmt.Printf("%s (until %s)\n", event.Name, eventEnd.Format("15:04"))
-			if event.Description != "" {
-				fmt.Printf("Description: %s\n", event.Description)
-			}
-			if event.Location != "" {
-				fmt.Printf("Location: %s\n", event.Location)
-			}
-			return
-		}
-	}
-	
-	fmt.Println("No current event")
-}
-
 // handleTestNotification sends a test notification
 func handleTestNotification(cfg *config.Config) {
 	if !config.IsNotificationsEnabled(cfg) {
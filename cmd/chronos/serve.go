@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/samuelstranges/chronos/internal/server"
+)
+
+// runServe implements `chronos serve`, exposing the database as an .ics feed and CalDAV collection
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var dbPath string
+	var addr string
+	var readOnly bool
+	var debugMode bool
+	fs.StringVar(&dbPath, "db", "", "Custom database file path (default: ~/.local/share/chronos/data.db)")
+	fs.StringVar(&addr, "addr", "", "Bind address (overrides serve_address in config, default: 127.0.0.1:5232)")
+	fs.BoolVar(&readOnly, "read-only", false, "Reject PUT and DELETE requests (overrides serve_read_only in config)")
+	fs.BoolVar(&debugMode, "debug", false, "Enable debug logging")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: chronos serve [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Serves the calendar at /calendar.ics and as a CalDAV collection at %s\n\n", server.CollectionPath)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg := loadConfig()
+
+	// Command line flags take precedence over config file
+	if addr != "" {
+		cfg.ServeAddress = addr
+	}
+	if readOnly {
+		cfg.ServeReadOnly = true
+	}
+	if cfg.ServeUsername != "" && cfg.ServePassword == "" {
+		fmt.Fprintln(os.Stderr, "serve_username is set but serve_password is empty")
+		os.Exit(1)
+	}
+
+	database := openDatabase(resolveDatabasePath(cfg, dbPath), debugMode)
+	defer database.CloseDatabase()
+
+	srv := server.NewServer(newEventManager(database, cfg), database, cfg)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatal("Server failed:", err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/samuelstranges/chronos/internal/status"
+)
+
+// statusQuery finds the event to report at a given time, or nil if there is none
+type statusQuery func(db *database.Database, now time.Time) *calendar.Event
+
+// findNextEvent returns the first event starting after now
+func findNextEvent(db *database.Database, now time.Time) *calendar.Event {
+	events, err := db.GetAllEvents()
+	if err != nil {
+		log.Fatal("Error getting events:", err)
+	}
+
+	var nextEvent *calendar.Event
+	for _, event := range events {
+		// Convert UTC stored time to local time for comparison
+		eventTime := event.Time.Local()
+		if eventTime.After(now) && (nextEvent == nil || eventTime.Before(nextEvent.Time.Local())) {
+			nextEvent = event
+		}
+	}
+	return nextEvent
+}
+
+// findCurrentEvent returns the event in progress at now, including ones that started yesterday
+func findCurrentEvent(db *database.Database, now time.Time) *calendar.Event {
+	events, err := db.GetEventsByDateRange(now.Add(-24*time.Hour), now.Add(time.Minute))
+	if err != nil {
+		log.Fatal("Error getting today's events:", err)
+	}
+
+	for _, event := range events {
+		eventStart := event.Time.Local()
+		eventEnd := eventStart.Add(time.Duration(event.DurationHour * float64(time.Hour)))
+		if !now.Before(eventStart) && now.Before(eventEnd) {
+			return event
+		}
+	}
+	return nil
+}
+
+// handleNextEvent prints the next upcoming event
+func handleNextEvent(db *database.Database, opts *status.Options) {
+	runStatus(db, opts, findNextEvent, status.DescribeNext, false)
+}
+
+// handleCurrentEvent prints the current event if one exists
+func handleCurrentEvent(db *database.Database, opts *status.Options) {
+	runStatus(db, opts, findCurrentEvent, status.DescribeCurrent, true)
+}
+
+// runStatus prints the status once, or with --watch every time the printed output changes.
+// Until counts down to the end of the event when untilEnd is set, otherwise to its start.
+func runStatus(db *database.Database, opts *status.Options, query statusQuery, describe func(*status.Event) string, untilEnd bool) {
+	render := func() string {
+		now := time.Now()
+		var event *status.Event
+		if found := query(db, now); found != nil {
+			event = status.NewEvent(found, now, untilEnd)
+		}
+		output, err := opts.Render(event, describe)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return output
+	}
+
+	if !opts.Watch {
+		fmt.Println(render())
+		return
+	}
+
+	// Re-query the already open database on every tick; only changes are printed
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	status.PrintChanges(os.Stdout, ticker.C, render)
+}
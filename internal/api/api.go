@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+)
+
+const (
+	// RPCPath accepts JSON-RPC 2.0 requests via POST
+	RPCPath = "/rpc"
+	// SchemaPath serves the JSON schemas for API types
+	SchemaPath = "/schema"
+
+	// maxRequestBody limits the size of a single JSON-RPC request
+	maxRequestBody = 1 << 20
+)
+
+// Server exposes an EventManager as a JSON-RPC 2.0 API over HTTP
+type Server struct {
+	eventManager *eventmanager.EventManager
+
+	// runner executes fn so it does not race with other users of the EventManager
+	runner func(fn func())
+
+	mu         sync.Mutex
+	httpServer *http.Server
+	listener   net.Listener
+	socketPath string
+}
+
+// NewServer creates an API server backed by the given EventManager
+func NewServer(em *eventmanager.EventManager) *Server {
+	s := &Server{eventManager: em}
+	s.runner = func(fn func()) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		fn()
+	}
+	return s
+}
+
+// SetRunner sets how calls touching the EventManager are executed, e.g. on the UI goroutine.
+// The runner must block until fn has returned.
+func (s *Server) SetRunner(runner func(fn func())) {
+	s.runner = runner
+}
+
+// Handler returns the HTTP handler serving the RPC and schema endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(RPCPath, s.handleRPC)
+	mux.HandleFunc(SchemaPath, s.handleSchema)
+	return mux
+}
+
+// ListenUnix starts serving on a Unix socket in the background.
+// A stale socket left by a crashed instance is removed; a live one is an error.
+func (s *Server) ListenUnix(socketPath string) error {
+	if _, err := os.Stat(socketPath); err == nil {
+		if conn, err := net.DialTimeout("unix", socketPath, time.Second); err == nil {
+			conn.Close()
+			return fmt.Errorf("another chronos instance is already listening on %s", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+	// Only the owner may talk to the calendar
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.listener = listener
+	s.socketPath = socketPath
+	s.httpServer = &http.Server{Handler: s.Handler()}
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("api: server stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Close stops the server and removes its socket
+func (s *Server) Close() error {
+	if s.httpServer == nil {
+		return nil
+	}
+	err := s.httpServer.Close()
+	os.Remove(s.socketPath)
+	return err
+}
+
+// handleSchema serves all published JSON schemas
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Schemas())
+}
+
+// handleRPC decodes a JSON-RPC request (or batch) and writes the response
+func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	trimmed := trimLeadingSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil || len(batch) == 0 {
+			json.NewEncoder(w).Encode(errorResponse(nil, codeInvalidRequest, "invalid batch"))
+			return
+		}
+		responses := make([]*response, 0, len(batch))
+		for _, raw := range batch {
+			if resp := s.handleMessage(raw); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+
+	resp := s.handleMessage(trimmed)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleMessage processes one JSON-RPC request; notifications (no id) return nil
+func (s *Server) handleMessage(raw json.RawMessage) *response {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(nil, codeParseError, "parse error: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, `requests must set "jsonrpc": "2.0" and a method`)
+	}
+
+	result, rpcErr := s.call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	if rpcErr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// call runs a method through the runner so it is serialised with the rest of the application
+func (s *Server) call(method string, params json.RawMessage) (result interface{}, rpcErr *Error) {
+	handler, ok := methods[method]
+	if !ok {
+		return nil, &Error{Code: codeMethodNotFound, Message: "method not found: " + method}
+	}
+
+	s.runner(func() {
+		result, rpcErr = handler(s, params)
+	})
+	return result, rpcErr
+}
+
+func trimLeadingSpace(b []byte) []byte {
+	for len(b) > 0 && (b[0] == ' ' || b[0] == '\t' || b[0] == '\n' || b[0] == '\r') {
+		b = b[1:]
+	}
+	return b
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/utils"
+)
+
+// Event is the JSON representation of a calendar.Event used by the API
+type Event struct {
+	ID            int       `json:"id,omitempty"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	Location      string    `json:"location,omitempty"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end,omitempty"`
+	DurationHours float64   `json:"duration_hours"`
+	FrequencyDays int       `json:"frequency_days,omitempty"`
+	Occurrences   int       `json:"occurrences,omitempty"`
+	Color         string    `json:"color,omitempty"`
+}
+
+// FromCalendarEvent converts a stored event (UTC or local) into its API form in local time
+func FromCalendarEvent(event *calendar.Event) Event {
+	start := event.Time.In(time.Local)
+	color := calendar.ColorAttributeToName(event.Color)
+	if color == "Default" {
+		color = ""
+	}
+	return Event{
+		ID:            event.Id,
+		Name:          event.Name,
+		Description:   event.Description,
+		Location:      event.Location,
+		Start:         start,
+		End:           start.Add(time.Duration(event.DurationHour * float64(time.Hour))),
+		DurationHours: event.DurationHour,
+		FrequencyDays: event.FrequencyDay,
+		Occurrences:   event.Occurence,
+		Color:         color,
+	}
+}
+
+// FromCalendarEvents converts a list of stored events into their API form
+func FromCalendarEvents(events []*calendar.Event) []Event {
+	result := make([]Event, 0, len(events))
+	for _, event := range events {
+		result = append(result, FromCalendarEvent(event))
+	}
+	return result
+}
+
+// ToCalendarEvent validates the API event and converts it to a calendar.Event in local time.
+// The End field is ignored unless duration_hours is zero.
+func (e Event) ToCalendarEvent() (*calendar.Event, error) {
+	name := strings.TrimSpace(e.Name)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if e.Start.IsZero() {
+		return nil, fmt.Errorf("start is required")
+	}
+
+	duration := e.DurationHours
+	if duration == 0 && !e.End.IsZero() {
+		duration = e.End.Sub(e.Start).Hours()
+	}
+	if !utils.ValidateDuration(strconv.FormatFloat(duration, 'f', -1, 64)) {
+		return nil, fmt.Errorf("invalid duration of %g hours (more than 0, at most 24, in steps of 0.5)", duration)
+	}
+	if e.Occurrences < 0 {
+		return nil, fmt.Errorf("occurrences must not be negative")
+	}
+
+	color := calendar.GenerateColorFromName(name)
+	if e.Color != "" {
+		color = calendar.ColorNameToAttribute(e.Color)
+		if calendar.ColorAttributeToName(color) != e.Color {
+			return nil, fmt.Errorf("unknown color %q (use one of %s)", e.Color, strings.Join(calendar.GetColorNames(), ", "))
+		}
+	}
+
+	occurrences := e.Occurrences
+	if occurrences == 0 {
+		occurrences = 1
+	}
+
+	return calendar.NewEvent(name, e.Description, e.Location, e.Start.In(time.Local), duration, e.FrequencyDays, occurrences, color), nil
+}
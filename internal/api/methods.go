@@ -0,0 +1,432 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/database"
+)
+
+// JSON-RPC 2.0 error codes, plus application codes for rejected changes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+
+	// CodeRejected means the EventManager refused the change (e.g. it overlaps another event)
+	CodeRejected = 1
+	// CodeNotFound means the referenced event does not exist
+	CodeNotFound = 2
+	// CodeNothingToUndo means the undo or redo stack is empty
+	CodeNothingToUndo = 3
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+type methodHandler func(s *Server, params json.RawMessage) (interface{}, *Error)
+
+// methods maps JSON-RPC method names to their handlers
+var methods map[string]methodHandler
+
+func init() {
+	methods = map[string]methodHandler{
+		"events.list":   (*Server).listEvents,
+		"events.get":    (*Server).getEvent,
+		"events.search": (*Server).searchEvents,
+		"events.add":    (*Server).addEvent,
+		"events.update": (*Server).updateEvent,
+		"events.delete": (*Server).deleteEvent,
+		"undo":          (*Server).undo,
+		"redo":          (*Server).redo,
+		"schema":        (*Server).schema,
+		"methods":       (*Server).listMethods,
+	}
+}
+
+type listParams struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type idParams struct {
+	ID int `json:"id"`
+}
+
+type searchParams struct {
+	Query string `json:"query"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type addParams struct {
+	Event json.RawMessage `json:"event"`
+}
+
+type updateParams struct {
+	ID    int             `json:"id"`
+	Event json.RawMessage `json:"event"`
+}
+
+type changeResult struct {
+	Events      []Event `json:"events,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// listEvents returns events in [from, to), defaulting to the next 7 days from today
+func (s *Server) listEvents(raw json.RawMessage) (interface{}, *Error) {
+	var params listParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if params.From != "" {
+		t, err := ParseTime(params.From)
+		if err != nil {
+			return nil, invalidParams("from: %v", err)
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, 7)
+	if params.To != "" {
+		t, err := ParseTime(params.To)
+		if err != nil {
+			return nil, invalidParams("to: %v", err)
+		}
+		to = t
+	}
+	if !to.After(from) {
+		return nil, invalidParams("to must be after from")
+	}
+
+	events, err := s.eventManager.GetEventsByDateRange(from, to)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return FromCalendarEvents(events), nil
+}
+
+// getEvent returns a single event by ID
+func (s *Server) getEvent(raw json.RawMessage) (interface{}, *Error) {
+	var params idParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	event, rpcErr := s.lookupEvent(params.ID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return FromCalendarEvent(event), nil
+}
+
+// searchEvents matches name, description and location, optionally within a date window
+func (s *Server) searchEvents(raw json.RawMessage) (interface{}, *Error) {
+	var params searchParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(strings.TrimSpace(params.Query))
+	if query == "" {
+		return nil, invalidParams("query is required")
+	}
+
+	if params.From != "" || params.To != "" {
+		events, rpcErr := s.searchRange(query, params)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		return FromCalendarEvents(events), nil
+	}
+
+	events, err := s.eventManager.SearchEventsWithFilters(database.SearchCriteria{Query: query})
+	if err != nil {
+		return nil, internalError(err)
+	}
+	return FromCalendarEvents(events), nil
+}
+
+// searchRange filters the events in a date window by text
+func (s *Server) searchRange(query string, params searchParams) ([]*calendar.Event, *Error) {
+	from := time.Time{}
+	to := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	if params.From != "" {
+		t, err := ParseTime(params.From)
+		if err != nil {
+			return nil, invalidParams("from: %v", err)
+		}
+		from = t
+	}
+	if params.To != "" {
+		t, err := ParseTime(params.To)
+		if err != nil {
+			return nil, invalidParams("to: %v", err)
+		}
+		to = t
+	}
+
+	events, err := s.eventManager.GetEventsByDateRange(from, to)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	var matches []*calendar.Event
+	for _, event := range events {
+		if strings.Contains(strings.ToLower(event.Name), query) ||
+			strings.Contains(strings.ToLower(event.Description), query) ||
+			strings.Contains(strings.ToLower(event.Location), query) {
+			matches = append(matches, event)
+		}
+	}
+	return matches, nil
+}
+
+// addEvent adds an event (and its recurrences) through the EventManager
+func (s *Server) addEvent(raw json.RawMessage) (interface{}, *Error) {
+	var params addParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if len(params.Event) == 0 {
+		return nil, invalidParams("event is required")
+	}
+
+	var apiEvent Event
+	if err := strictUnmarshal(params.Event, &apiEvent); err != nil {
+		return nil, invalidParams("event: %v", err)
+	}
+	event, err := apiEvent.ToCalendarEvent()
+	if err != nil {
+		return nil, invalidParams("event: %v", err)
+	}
+
+	// The occurrences are added as a single change, or none of them when one is rejected
+	occurrences := event.GetReccuringEvents()
+	var results []*calendar.Event
+	var ok bool
+	title, message := s.eventManager.CaptureErrors(func() {
+		results, ok = s.eventManager.AddEvents(occurrences, fmt.Sprintf("add %d events", len(occurrences)))
+	})
+	if !ok {
+		return nil, rejected(title, message)
+	}
+
+	added := make([]Event, 0, len(results))
+	for _, result := range results {
+		added = append(added, FromCalendarEvent(result))
+	}
+	return changeResult{Events: added}, nil
+}
+
+// updateEvent applies the given fields on top of an existing event.
+// Omitted fields keep their current values.
+func (s *Server) updateEvent(raw json.RawMessage) (interface{}, *Error) {
+	var params updateParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if len(params.Event) == 0 {
+		return nil, invalidParams("event is required")
+	}
+
+	existing, rpcErr := s.lookupEvent(params.ID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	apiEvent := FromCalendarEvent(existing)
+	if err := strictUnmarshal(params.Event, &apiEvent); err != nil {
+		return nil, invalidParams("event: %v", err)
+	}
+
+	// Setting only "end" changes the duration rather than being ignored
+	var fields map[string]json.RawMessage
+	json.Unmarshal(params.Event, &fields)
+	_, hasEnd := fields["end"]
+	_, hasDuration := fields["duration_hours"]
+	if hasEnd && !hasDuration {
+		apiEvent.DurationHours = 0
+	}
+
+	event, err := apiEvent.ToCalendarEvent()
+	if err != nil {
+		return nil, invalidParams("event: %v", err)
+	}
+	// Keep the stored colour unless a new one was given
+	if _, hasColor := fields["color"]; !hasColor {
+		event.Color = existing.Color
+	}
+	event.Id = existing.Id
+	event.FrequencyDay = existing.FrequencyDay
+	event.Occurence = existing.Occurence
+
+	var ok bool
+	title, message := s.eventManager.CaptureErrors(func() {
+		ok = s.eventManager.UpdateEvent(existing.Id, event)
+	})
+	if !ok {
+		return nil, rejected(title, message)
+	}
+
+	return changeResult{Events: []Event{FromCalendarEvent(event)}}, nil
+}
+
+// deleteEvent deletes an event by ID
+func (s *Server) deleteEvent(raw json.RawMessage) (interface{}, *Error) {
+	var params idParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	event, rpcErr := s.lookupEvent(params.ID)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if err := s.eventManager.DeleteEvent(params.ID); err != nil {
+		return nil, internalError(err)
+	}
+
+	return changeResult{Events: []Event{FromCalendarEvent(event)}}, nil
+}
+
+// undo reverts the last change, whether it was made through the API or the UI
+func (s *Server) undo(raw json.RawMessage) (interface{}, *Error) {
+	if !s.eventManager.CanUndo() {
+		return nil, &Error{Code: CodeNothingToUndo, Message: "nothing to undo"}
+	}
+	description := s.eventManager.GetUndoDescription()
+	if err := s.eventManager.Undo(); err != nil {
+		return nil, internalError(err)
+	}
+	return changeResult{Description: description}, nil
+}
+
+// redo re-applies the last undone change
+func (s *Server) redo(raw json.RawMessage) (interface{}, *Error) {
+	if !s.eventManager.CanRedo() {
+		return nil, &Error{Code: CodeNothingToUndo, Message: "nothing to redo"}
+	}
+	description := s.eventManager.GetRedoDescription()
+	if err := s.eventManager.Redo(); err != nil {
+		return nil, internalError(err)
+	}
+	return changeResult{Description: description}, nil
+}
+
+// schema returns the JSON schemas for API types
+func (s *Server) schema(raw json.RawMessage) (interface{}, *Error) {
+	return Schemas(), nil
+}
+
+// listMethods returns the names of all supported methods
+func (s *Server) listMethods(raw json.RawMessage) (interface{}, *Error) {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// lookupEvent returns the event with the given ID or a not-found error
+func (s *Server) lookupEvent(id int) (*calendar.Event, *Error) {
+	if id <= 0 {
+		return nil, invalidParams("id must be a positive integer")
+	}
+	event, err := s.eventManager.GetEventById(id)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if event == nil {
+		return nil, &Error{Code: CodeNotFound, Message: fmt.Sprintf("event %d not found", id)}
+	}
+	return event, nil
+}
+
+// ParseTime accepts RFC 3339 timestamps as well as local "YYYY-MM-DD[ HH:MM]" and "YYYYMMDD" forms
+func ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339 or YYYY-MM-DD[ HH:MM])", value)
+}
+
+func decodeParams(raw json.RawMessage, v interface{}) *Error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := strictUnmarshal(raw, v); err != nil {
+		return invalidParams("%v", err)
+	}
+	return nil
+}
+
+// strictUnmarshal decodes JSON and rejects unknown fields so typos are reported
+func strictUnmarshal(raw json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func invalidParams(format string, args ...interface{}) *Error {
+	return &Error{Code: codeInvalidParams, Message: "invalid params: " + fmt.Sprintf(format, args...)}
+}
+
+func internalError(err error) *Error {
+	return &Error{Code: codeInternalError, Message: err.Error()}
+}
+
+// rejected reports an EventManager refusal
+func rejected(title, message string) *Error {
+	if message == "" {
+		message = "event rejected"
+	}
+	if title != "" {
+		message = title + ": " + message
+	}
+	return &Error{Code: CodeRejected, Message: message}
+}
@@ -0,0 +1,65 @@
+package api
+
+import "github.com/samuelstranges/chronos/internal/calendar"
+
+// EventSchema returns the JSON Schema describing Event
+func EventSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  "chronos:event",
+		"title":                "Event",
+		"description":          "A calendar event. Times are RFC 3339; responses use the local time zone of the running instance.",
+		"type":                 "object",
+		"required":             []string{"name", "start"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"id": map[string]interface{}{
+				"type":        "integer",
+				"description": "Database ID, assigned by the server",
+				"readOnly":    true,
+			},
+			"name": map[string]interface{}{
+				"type":      "string",
+				"minLength": 1,
+			},
+			"description": map[string]interface{}{"type": "string"},
+			"location":    map[string]interface{}{"type": "string"},
+			"start": map[string]interface{}{
+				"type":   "string",
+				"format": "date-time",
+			},
+			"end": map[string]interface{}{
+				"type":        "string",
+				"format":      "date-time",
+				"description": "Derived from start and duration_hours; only used on input when duration_hours is omitted",
+			},
+			"duration_hours": map[string]interface{}{
+				"type":             "number",
+				"exclusiveMinimum": 0,
+				"maximum":          24,
+			},
+			"frequency_days": map[string]interface{}{
+				"type":        "integer",
+				"description": "Days between occurrences when adding a recurring event; -1 repeats on weekdays",
+				"minimum":     -1,
+			},
+			"occurrences": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of events to create when adding (default 1)",
+				"minimum":     0,
+			},
+			"color": map[string]interface{}{
+				"type":        "string",
+				"description": "Defaults to a colour generated from the name",
+				"enum":        calendar.GetColorNames(),
+			},
+		},
+	}
+}
+
+// Schemas returns every schema published by the API, keyed by name
+func Schemas() map[string]interface{} {
+	return map[string]interface{}{
+		"event": EventSchema(),
+	}
+}
@@ -0,0 +1,45 @@
+package calendar
+
+import "time"
+
+// AgendaDay is one date of an agenda with its events in start order
+type AgendaDay struct {
+	Date   time.Time
+	Events []*Event
+}
+
+// GroupEventsByDay groups events by their local date for an agenda of the given number of days
+// from start. Days without events are included unless skipEmpty is set. With days <= 0 the agenda
+// covers exactly the days that have events, which suits search results. Events must be sorted by
+// time and converted to local time.
+func GroupEventsByDay(events []*Event, start time.Time, days int, skipEmpty bool) []AgendaDay {
+	var agenda []AgendaDay
+	if days <= 0 {
+		for _, event := range events {
+			date := time.Date(event.Time.Year(), event.Time.Month(), event.Time.Day(), 0, 0, 0, 0, event.Time.Location())
+			if len(agenda) == 0 || !agenda[len(agenda)-1].Date.Equal(date) {
+				agenda = append(agenda, AgendaDay{Date: date})
+			}
+			agenda[len(agenda)-1].Events = append(agenda[len(agenda)-1].Events, event)
+		}
+		return agenda
+	}
+
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	next := 0
+	for i := 0; i < days; i++ {
+		date := first.AddDate(0, 0, i)
+		end := date.AddDate(0, 0, 1)
+		day := AgendaDay{Date: date}
+		for next < len(events) && events[next].Time.Before(end) {
+			if !events[next].Time.Before(date) {
+				day.Events = append(day.Events, events[next])
+			}
+			next++
+		}
+		if len(day.Events) > 0 || !skipEmpty {
+			agenda = append(agenda, day)
+		}
+	}
+	return agenda
+}
@@ -0,0 +1,23 @@
+package calendar
+
+import "time"
+
+// BookedHoursByDate sums the hours booked on each day, keyed by "2006-01-02" in the events' own
+// time zone. Events running past midnight count towards both days.
+func BookedHoursByDate(events []*Event) map[string]float64 {
+	hours := make(map[string]float64)
+	for _, event := range events {
+		start := event.Time
+		end := event.EndTime()
+		for start.Before(end) {
+			nextDay := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+			segmentEnd := end
+			if nextDay.Before(end) {
+				segmentEnd = nextDay
+			}
+			hours[start.Format("2006-01-02")] += segmentEnd.Sub(start).Hours()
+			start = segmentEnd
+		}
+	}
+	return hours
+}
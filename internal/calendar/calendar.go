@@ -8,10 +8,30 @@ import (
 type Calendar struct {
 	CurrentDay  *Day
 	CurrentWeek *Week
+
+	// DayCount is the number of days in CurrentWeek; Rolling makes CurrentWeek a window that
+	// scrolls with CurrentDay instead of the week starting on WeekStart
+	DayCount int
+	Rolling  bool
+
+	// WeekStart is the first day of the week used by CurrentWeek and the month and year grids
+	WeekStart time.Weekday
+
+	// SelectedEventId picks one of several overlapping events under the cursor (0 means the leftmost)
+	SelectedEventId int
+
+	// VisualAnchor is where visual mode started, nil outside visual mode
+	VisualAnchor *time.Time
+
+	// Grab is the event grab mode moves and resizes, nil outside grab mode
+	Grab *Grab
+
+	// Filter narrows the views and the event jumps to the events it matches, nil to show all
+	Filter *Filter
 }
 
 func NewCalendar(currentDay *Day) *Calendar {
-	c := &Calendar{CurrentDay: currentDay}
+	c := &Calendar{CurrentDay: currentDay, DayCount: 7}
 
 	c.CurrentWeek = NewWeek()
 	c.UpdateWeek()
@@ -23,11 +43,84 @@ func (c *Calendar) setWeekLimits() {
 	c.RoundTime()
 	d := c.CurrentDay.Date
 
-	diffToSunday := d.Weekday()
-	diffToSaturday := 6 - d.Weekday()
+	if c.Rolling {
+		c.setRollingLimits()
+		return
+	}
+
+	diffToStart := (int(d.Weekday()) - int(c.WeekStart) + 7) % 7
+
+	c.CurrentWeek.StartDate = d.AddDate(0, 0, -diffToStart)
+	c.CurrentWeek.EndDate = c.CurrentWeek.StartDate.AddDate(0, 0, 6)
+}
+
+// Filtered returns the events the active filter matches, or all of them without a filter
+func (c *Calendar) Filtered(events []*Event) []*Event {
+	if c.Filter == nil {
+		return events
+	}
+	now := time.Now()
+	matching := make([]*Event, 0, len(events))
+	for _, event := range events {
+		if c.Filter.Matches(event, now, c.WeekStart) {
+			matching = append(matching, event)
+		}
+	}
+	return matching
+}
 
-	c.CurrentWeek.StartDate = d.AddDate(0, 0, -int(diffToSunday))
-	c.CurrentWeek.EndDate = d.AddDate(0, 0, int(diffToSaturday))
+// SetWeekStart sets the first day of the week, e.g. time.Monday for ISO weeks
+func (c *Calendar) SetWeekStart(weekStart time.Weekday) {
+	c.WeekStart = weekStart
+	c.UpdateWeek()
+}
+
+// setRollingLimits keeps the current window if CurrentDay is inside it, otherwise scrolls it
+// just far enough to show CurrentDay as its first or last day
+func (c *Calendar) setRollingLimits() {
+	d := c.CurrentDay.Date
+	offset := 0
+	if start := c.CurrentWeek.StartDate; !start.IsZero() {
+		offset = daysBetween(start, d)
+		if offset < 0 {
+			offset = 0
+		} else if offset >= c.DayCount {
+			offset = c.DayCount - 1
+		}
+	}
+
+	c.CurrentWeek.StartDate = d.AddDate(0, 0, -offset)
+	c.CurrentWeek.EndDate = c.CurrentWeek.StartDate.AddDate(0, 0, c.DayCount-1)
+}
+
+// daysBetween counts the calendar days from a to b, ignoring the time of day
+func daysBetween(a, b time.Time) int {
+	aDate := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	bDate := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(bDate.Sub(aDate).Hours() / 24)
+}
+
+// SetLayout chooses the days in CurrentWeek: the week starting on WeekStart, or a rolling window of
+// days starting at CurrentDay. CurrentWeek is replaced, so views holding its days must be recreated.
+func (c *Calendar) SetLayout(days int, rolling bool) {
+	if !rolling || days < 1 {
+		days = 7
+		rolling = false
+	}
+	c.DayCount = days
+	c.Rolling = rolling
+	c.CurrentWeek = NewWeekOfDays(days)
+	c.UpdateWeek()
+}
+
+// DayIndex returns the position of date's day in CurrentWeek, or -1 if it is not shown
+func (c *Calendar) DayIndex(date time.Time) int {
+	for i, day := range c.CurrentWeek.Days {
+		if daysBetween(day.Date, date) == 0 {
+			return i
+		}
+	}
+	return -1
 }
 
 func (c *Calendar) FormatWeekBody() string {
@@ -35,6 +128,9 @@ func (c *Calendar) FormatWeekBody() string {
 	endDay := c.CurrentWeek.EndDate
 	month := endDay.Month().String()
 
+	if startDay.Month() != endDay.Month() {
+		return startDay.Month().String() + " " + strconv.Itoa(startDay.Day()) + " to " + month + " " + strconv.Itoa(endDay.Day())
+	}
 	return month + " " + strconv.Itoa(startDay.Day()) + " to " + strconv.Itoa(endDay.Day())
 }
 
@@ -149,3 +245,15 @@ func (c *Calendar) UpdateToPrevMonth() {
 	c.CurrentDay.Date = time.Date(prevMonth.Year(), prevMonth.Month(), 1, currentDate.Hour(), currentDate.Minute(), 0, 0, currentDate.Location())
 	c.UpdateWeek()
 }
+
+// ShiftMonth moves by n months keeping the day of the month, clamped to the length of the target month
+func (c *Calendar) ShiftMonth(n int) {
+	currentDate := c.CurrentDay.Date
+	first := time.Date(currentDate.Year(), currentDate.Month()+time.Month(n), 1, currentDate.Hour(), currentDate.Minute(), 0, 0, currentDate.Location())
+	day := currentDate.Day()
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	c.CurrentDay.Date = first.AddDate(0, 0, day-1)
+	c.UpdateWeek()
+}
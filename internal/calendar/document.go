@@ -0,0 +1,136 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/utils"
+	"github.com/jroimartin/gocui"
+)
+
+// documentDelimiter opens and closes the front matter of an event document
+const documentDelimiter = "---"
+
+// FormatEventDocument writes an event as a document for editing in a text editor: its fields as
+// front matter between "---" lines, followed by the description
+func FormatEventDocument(event *Event) string {
+	var b strings.Builder
+	b.WriteString(documentDelimiter + "\n")
+	fmt.Fprintf(&b, "name: %s\n", event.Name)
+	fmt.Fprintf(&b, "date: %s\n", event.Time.Format("2006-01-02"))
+	fmt.Fprintf(&b, "time: %s\n", event.Time.Format("15:04"))
+	fmt.Fprintf(&b, "duration: %s\n", strconv.FormatFloat(event.DurationHour, 'f', -1, 64))
+	fmt.Fprintf(&b, "location: %s\n", event.Location)
+	fmt.Fprintf(&b, "color: %s\n", ColorAttributeToName(event.Color))
+	b.WriteString(documentDelimiter + "\n")
+	if event.Description != "" {
+		b.WriteString(event.Description + "\n")
+	}
+	return b.String()
+}
+
+// ParseEventDocument reads a document written by FormatEventDocument back into a copy of event.
+// Fields missing from the front matter keep their values from event.
+func ParseEventDocument(document string, event *Event) (*Event, error) {
+	lines := strings.Split(strings.ReplaceAll(document, "\r\n", "\n"), "\n")
+
+	// Skip blank lines an editor may have left before the front matter
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) || strings.TrimSpace(lines[start]) != documentDelimiter {
+		return nil, fmt.Errorf("document must start with a %q line", documentDelimiter)
+	}
+
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == documentDelimiter {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("front matter is not closed by a %q line", documentDelimiter)
+	}
+
+	parsed := *event
+	local := event.Time.Location()
+	date := event.Time.Format("2006-01-02")
+	startTime := event.Time.Format("15:04")
+
+	for i := start + 1; i < end; i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected \"field: value\"", i+1)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name":
+			if !utils.ValidateName(value) {
+				return nil, fmt.Errorf("line %d: name cannot be empty", i+1)
+			}
+			parsed.Name = value
+		case "date":
+			if !utils.ValidateEventDate(value) {
+				return nil, fmt.Errorf("line %d: invalid date %q (YYYY-MM-DD)", i+1, value)
+			}
+			date = value
+		case "time":
+			if !utils.ValidateEventTime(value) {
+				return nil, fmt.Errorf("line %d: invalid time %q (HH:MM on the hour or half hour)", i+1, value)
+			}
+			startTime = value
+		case "duration":
+			if !utils.ValidateDuration(value) {
+				return nil, fmt.Errorf("line %d: invalid duration %q (hours in steps of 0.5)", i+1, value)
+			}
+			parsed.DurationHour, _ = strconv.ParseFloat(value, 64)
+		case "location":
+			parsed.Location = value
+		case "color", "colour":
+			color, ok := parseColorName(value)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown color %q", i+1, value)
+			}
+			parsed.Color = color
+		default:
+			return nil, fmt.Errorf("line %d: unknown field %q", i+1, strings.TrimSpace(key))
+		}
+	}
+
+	eventTime, err := time.ParseInLocation("2006-01-02 15:04", date+" "+startTime, local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date and time: %v", err)
+	}
+	parsed.Time = eventTime
+	if parsed.Color == gocui.ColorDefault {
+		parsed.Color = GenerateColorFromName(parsed.Name)
+	}
+	// The description keeps its indentation but not the blank lines around it
+	body := strings.Join(lines[end+1:], "\n")
+	parsed.Description = strings.TrimRight(strings.TrimLeft(body, "\n"), " \t\n")
+
+	return &parsed, nil
+}
+
+// parseColorName matches a colour name in any case. An empty name gives the default colour,
+// which ParseEventDocument replaces with one generated from the event name like the event forms do.
+func parseColorName(name string) (gocui.Attribute, bool) {
+	if name == "" || strings.EqualFold(name, "Default") {
+		return gocui.ColorDefault, true
+	}
+	for _, colorName := range GetColorNames() {
+		if strings.EqualFold(colorName, name) {
+			return ColorNameToAttribute(colorName), true
+		}
+	}
+	return gocui.ColorDefault, false
+}
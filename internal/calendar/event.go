@@ -134,6 +134,22 @@ func (e *Event) FormatDurationTime() string {
 	return fmt.Sprintf("%s-%s", startTimeString, endTimeString)
 }
 
+// EndTime returns the time the event ends
+func (e *Event) EndTime() time.Time {
+	return e.Time.Add(time.Duration(e.DurationHour * float64(time.Hour)))
+}
+
+// Equal reports whether two events have the same fields, comparing their times as instants
+// rather than by location and monotonic reading as == does
+func (e *Event) Equal(other *Event) bool {
+	a, b := *e, *other
+	if !a.Time.Equal(b.Time) {
+		return false
+	}
+	a.Time, b.Time = time.Time{}, time.Time{}
+	return a == b
+}
+
 func (e *Event) FormatBody() string {
 	var sb strings.Builder
 
@@ -186,3 +202,23 @@ func (e Event) GetReccuringEvents() []Event {
 
 	return events
 }
+
+// RecurrenceSummary describes how the event's series repeats, or returns "" for a single event
+func (e *Event) RecurrenceSummary() string {
+	if e.Occurence <= 1 {
+		return ""
+	}
+
+	var every string
+	switch e.FrequencyDay {
+	case -1:
+		every = "Weekdays"
+	case 1:
+		every = "Daily"
+	case 7:
+		every = "Weekly"
+	default:
+		every = fmt.Sprintf("Every %d days", e.FrequencyDay)
+	}
+	return fmt.Sprintf("%s, %d times", every, e.Occurence)
+}
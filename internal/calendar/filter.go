@@ -0,0 +1,152 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Filter is a named set of conditions that narrows the calendar views and the event jumps to the
+// events matching all of them. Empty conditions match every event.
+type Filter struct {
+	Name string `json:"name"`
+	// Text is found in the name, description or location, ignoring case
+	Text string `json:"text,omitempty"`
+	// Color is a color name, e.g. "Blue"
+	Color string `json:"color,omitempty"`
+	// Tag is a #tag written in the name, description or location, given with or without the #
+	Tag string `json:"tag,omitempty"`
+	// From and To are the first and last days of the window events start in: YYYYMMDD, t for
+	// today or w for this week
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Validate checks that the filter has a name of one word, valid conditions and at least one of them
+func (f *Filter) Validate() error {
+	if f.Name == "" || strings.ContainsFunc(f.Name, unicode.IsSpace) {
+		return fmt.Errorf("filter name %q must be one word", f.Name)
+	}
+	if f.Text == "" && f.Color == "" && f.Tag == "" && f.From == "" && f.To == "" {
+		return fmt.Errorf("filter %s has no conditions", f.Name)
+	}
+	if f.Color != "" && !isColorName(f.Color) {
+		return fmt.Errorf("filter %s: unknown color %q", f.Name, f.Color)
+	}
+	for _, date := range []string{f.From, f.To} {
+		if date == "" {
+			continue
+		}
+		if _, err := filterDay(date, time.Now(), time.Sunday, false); err != nil {
+			return fmt.Errorf("filter %s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether event meets every condition of the filter. now and weekStart place
+// the t and w date shortcuts.
+func (f *Filter) Matches(event *Event, now time.Time, weekStart time.Weekday) bool {
+	if f.Text != "" {
+		text := strings.ToLower(event.Name + "\n" + event.Description + "\n" + event.Location)
+		if !strings.Contains(text, strings.ToLower(f.Text)) {
+			return false
+		}
+	}
+	if f.Color != "" && !strings.EqualFold(ColorAttributeToName(event.Color), f.Color) {
+		return false
+	}
+	if f.Tag != "" && !hasTag(event, strings.TrimPrefix(f.Tag, "#")) {
+		return false
+	}
+	if f.From != "" {
+		if start, err := filterDay(f.From, now, weekStart, false); err == nil && event.Time.Before(start) {
+			return false
+		}
+	}
+	if f.To != "" {
+		if last, err := filterDay(f.To, now, weekStart, true); err == nil && !event.Time.Before(last.AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe lists the filter's conditions, e.g. `"standup" Blue #work from t`
+func (f *Filter) Describe() string {
+	var conditions []string
+	if f.Text != "" {
+		conditions = append(conditions, fmt.Sprintf("%q", f.Text))
+	}
+	if f.Color != "" {
+		conditions = append(conditions, normalizeColorName(f.Color))
+	}
+	if f.Tag != "" {
+		conditions = append(conditions, "#"+strings.TrimPrefix(f.Tag, "#"))
+	}
+	if f.From != "" {
+		conditions = append(conditions, "from "+f.From)
+	}
+	if f.To != "" {
+		conditions = append(conditions, "to "+f.To)
+	}
+	return strings.Join(conditions, " ")
+}
+
+// hasTag reports whether #tag is written in the event's name, description or location
+func hasTag(event *Event, tag string) bool {
+	for _, text := range []string{event.Name, event.Description, event.Location} {
+		for _, word := range strings.Fields(text) {
+			if !strings.HasPrefix(word, "#") {
+				continue
+			}
+			word = strings.TrimRightFunc(word[1:], func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+			if strings.EqualFold(word, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterDay returns the start of the day a filter date names: YYYYMMDD, t for today, or w for
+// the first day of this week, or for its last day at the end of a window
+func filterDay(date string, now time.Time, weekStart time.Weekday, isEnd bool) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	switch date {
+	case "t":
+		return today, nil
+	case "w":
+		start := StartOfWeek(today, weekStart)
+		if isEnd {
+			return start.AddDate(0, 0, 6), nil
+		}
+		return start, nil
+	}
+	day, err := time.ParseInLocation("20060102", date, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("dates are YYYYMMDD, t for today or w for this week")
+	}
+	return day, nil
+}
+
+func isColorName(name string) bool {
+	for _, color := range GetColorNames() {
+		if strings.EqualFold(color, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeColorName capitalizes a color name as GetColorNames lists it, e.g. "blue" to "Blue"
+func normalizeColorName(name string) string {
+	for _, color := range GetColorNames() {
+		if strings.EqualFold(color, name) {
+			return color
+		}
+	}
+	return name
+}
@@ -0,0 +1,63 @@
+package calendar
+
+import "time"
+
+// Grab is an event held in grab mode: moved and resized with the keyboard and previewed at its
+// new time until it is saved or dropped
+type Grab struct {
+	Event     Event // the event at its new time and length, in local time
+	Original  Event // the event as it was grabbed
+	Conflicts []int // ids of the events the preview overlaps
+}
+
+// NewGrab grabs a copy of event
+func NewGrab(event *Event) *Grab {
+	return &Grab{Event: *event, Original: *event}
+}
+
+// Move moves the preview days later and slots half hours later, earlier when negative
+func (g *Grab) Move(days, slots int) {
+	g.Event.Time = ApplyOffset(g.Event.Time, days, time.Duration(slots)*SlotDuration)
+}
+
+// Resize makes the preview slots half hours longer, shorter when negative, keeping at least one
+// slot
+func (g *Grab) Resize(slots int) {
+	minimum := SlotDuration.Hours()
+	g.Event.DurationHour += float64(slots) * minimum
+	if g.Event.DurationHour < minimum {
+		g.Event.DurationHour = minimum
+	}
+}
+
+// OnBottomEdge reports whether slot is the last one of an event longer than a slot: the edge a
+// mouse drag resizes rather than moves the event by
+func OnBottomEdge(event *Event, slot time.Time) bool {
+	return event.DurationHour > SlotDuration.Hours() && slot.Add(SlotDuration).Equal(event.EndTime())
+}
+
+// ResizeTo makes the preview end at end, keeping at least one slot
+func (g *Grab) ResizeTo(end time.Time) {
+	g.Event.DurationHour = end.Sub(g.Event.Time).Hours()
+	g.Resize(0)
+}
+
+// Changed reports whether the preview differs from the event as it was grabbed
+func (g *Grab) Changed() bool {
+	return !g.Event.Time.Equal(g.Original.Time) || g.Event.DurationHour != g.Original.DurationHour
+}
+
+// StartsOn reports whether the preview starts on the day of date
+func (g *Grab) StartsOn(date time.Time) bool {
+	return startOfDay(g.Event.Time).Equal(startOfDay(date))
+}
+
+// ConflictsWith reports whether the preview overlaps the event with the given id
+func (g *Grab) ConflictsWith(id int) bool {
+	for _, conflict := range g.Conflicts {
+		if conflict == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// OverlapColumn places an event in a group of overlapping events drawn side by side
+type OverlapColumn struct {
+	Column  int // 0-based column of the event
+	Columns int // number of columns used by the event's overlap group
+}
+
+// OverlapColumns assigns every event a column so that overlapping events never share one.
+// Events that overlap each other, directly or through a chain, form a group that is drawn
+// with the same number of columns. The result is indexed like events; events are ordered by
+// start time and then ID, so the earliest event of a slot is in the leftmost column.
+func OverlapColumns(events []*Event) []OverlapColumn {
+	result := make([]OverlapColumn, len(events))
+
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := events[order[a]], events[order[b]]
+		if !ea.Time.Equal(eb.Time) {
+			return ea.Time.Before(eb.Time)
+		}
+		return ea.Id < eb.Id
+	})
+
+	var group []int            // indexes of the events in the current group
+	var columnEnds []time.Time // end time of the last event in each column of the group
+	var groupEnd time.Time
+
+	closeGroup := func() {
+		for _, i := range group {
+			result[i].Columns = len(columnEnds)
+		}
+		group = group[:0]
+		columnEnds = columnEnds[:0]
+	}
+
+	for _, i := range order {
+		event := events[i]
+		end := event.EndTime()
+
+		if len(group) > 0 && !event.Time.Before(groupEnd) {
+			closeGroup()
+		}
+
+		column := -1
+		for c, columnEnd := range columnEnds {
+			if !event.Time.Before(columnEnd) {
+				column = c
+				break
+			}
+		}
+		if column == -1 {
+			column = len(columnEnds)
+			columnEnds = append(columnEnds, end)
+		} else {
+			columnEnds[column] = end
+		}
+
+		result[i].Column = column
+		if len(group) == 0 || end.After(groupEnd) {
+			groupEnd = end
+		}
+		group = append(group, i)
+	}
+	closeGroup()
+
+	return result
+}
@@ -0,0 +1,94 @@
+package calendar
+
+import "time"
+
+// SlotDuration is the length of a row of the time column, the smallest step of the cursor
+const SlotDuration = 30 * time.Minute
+
+// Selection is the block visual mode selects between two corners: every day from one corner's
+// date to the other's and, on each of them, the half-hour slots from one corner's time to the
+// other's
+type Selection struct {
+	Anchor time.Time // where visual mode started
+	Cursor time.Time // the selected date and time
+}
+
+// Visual returns the block selected in visual mode, from VisualAnchor to the cursor
+func (c *Calendar) Visual() (Selection, bool) {
+	if c.VisualAnchor == nil {
+		return Selection{}, false
+	}
+	return Selection{Anchor: *c.VisualAnchor, Cursor: c.CurrentDay.Date}, true
+}
+
+// FirstDay returns the start of the first day of the selection
+func (s Selection) FirstDay() time.Time {
+	first, _ := s.days()
+	return first
+}
+
+// Days returns the number of days the selection spans
+func (s Selection) Days() int {
+	first, last := s.days()
+	return daysBetween(first, last) + 1
+}
+
+// ContainsSlot reports whether the slot starting at t is selected
+func (s Selection) ContainsSlot(t time.Time) bool {
+	first, last := s.days()
+	day := startOfDay(t)
+	if day.Before(first) || day.After(last) {
+		return false
+	}
+	start, end := s.clock()
+	clock := t.Sub(day)
+	return clock >= start && clock < end
+}
+
+// Overlaps reports whether an event runs into the selected slots of its first day
+func (s Selection) Overlaps(event *Event) bool {
+	first, last := s.days()
+	day := startOfDay(event.Time)
+	if day.Before(first) || day.After(last) {
+		return false
+	}
+	start, end := s.clock()
+	return event.Time.Before(day.Add(end)) && event.EndTime().After(day.Add(start))
+}
+
+// days returns the start of the first and last day of the selection
+func (s Selection) days() (time.Time, time.Time) {
+	first, last := startOfDay(s.Anchor), startOfDay(s.Cursor)
+	if last.Before(first) {
+		first, last = last, first
+	}
+	return first, last
+}
+
+// clock returns the times of day the selected slots start and end at
+func (s Selection) clock() (time.Duration, time.Duration) {
+	start := s.Anchor.Sub(startOfDay(s.Anchor))
+	end := s.Cursor.Sub(startOfDay(s.Cursor))
+	if end < start {
+		start, end = end, start
+	}
+	return start, end + SlotDuration
+}
+
+// Offset returns how many days and how much time of day t is after from. Moving and pasting
+// events apply offsets with ApplyOffset so that they keep their times of day across changes of
+// daylight saving time.
+func Offset(from, t time.Time) (int, time.Duration) {
+	fromDay, day := startOfDay(from), startOfDay(t)
+	return daysBetween(fromDay, day), t.Sub(day) - from.Sub(fromDay)
+}
+
+// ApplyOffset returns the time days and clock after t, as Offset measures them
+func ApplyOffset(t time.Time, days int, clock time.Duration) time.Time {
+	day := startOfDay(t)
+	return time.Date(day.Year(), day.Month(), day.Day()+days, 0, 0, 0, 0, t.Location()).Add(t.Sub(day) + clock)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -9,15 +9,28 @@ type Week struct {
 }
 
 func NewWeek() *Week {
-	return &Week{
-		Days: []*Day{
-			NewDay(time.Time{}),
-			NewDay(time.Time{}),
-			NewDay(time.Time{}),
-			NewDay(time.Time{}),
-			NewDay(time.Time{}),
-			NewDay(time.Time{}),
-			NewDay(time.Time{}),
-		},
+	return NewWeekOfDays(7)
+}
+
+// NewWeekOfDays creates a span of n days, used by rolling layouts that are not a whole week
+func NewWeekOfDays(n int) *Week {
+	w := &Week{Days: make([]*Day, n)}
+	for i := range w.Days {
+		w.Days[i] = NewDay(time.Time{})
 	}
+	return w
+}
+
+// StartOfWeek returns midnight on the first day of date's week, for weeks starting on weekStart
+func StartOfWeek(date time.Time, weekStart time.Weekday) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) - int(weekStart) + 7) % 7))
+}
+
+// WeekNumber returns the ISO 8601 number of the week starting on start. Weeks that do not start on
+// Monday are numbered by the Monday they contain.
+func WeekNumber(start time.Time) int {
+	monday := start.AddDate(0, 0, (int(time.Monday)-int(start.Weekday())+7)%7)
+	_, week := monday.ISOWeek()
+	return week
 }
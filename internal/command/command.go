@@ -0,0 +1,337 @@
+// Package command reads the lines typed at the ':' prompt: it finds the command a line names,
+// parses the arguments that need more than splitting, and completes partly typed lines.
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/jroimartin/gocui"
+)
+
+// Command is a command that can be typed at the prompt
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+}
+
+// Commands lists every command in the order :help shows them
+var Commands = []Command{
+	{"goto", "DATE [TIME]", "Jump to a date (2026-11-03, 20261103, today) and time"},
+	{"add", "PHRASE", "Add an event from a quick add phrase, e.g. Lunch 12:30 1h"},
+	{"delete", "", "Delete the selected event"},
+	{"color", "COLOR", "Change the selected event's color"},
+	{"mark", "LETTER", "Mark the selected date and time with a letter a-z"},
+	{"marks", "", "List marks"},
+	{"registers", "", "List the yank history and named registers"},
+	{"filter", "[NAME | off]", "List filters or turn one on or off; filter delete NAME deletes one"},
+	{"export", "ics PATH", "Export all events to an iCalendar file"},
+	{"set", "[OPTION=VALUE]", "Change a setting until chronos exits, or list them"},
+	{"view", "NAME", "Switch view (week, day, days, month, weeks, year, agenda)"},
+	{"help", "", "List commands"},
+	{"quit", "", "Exit chronos"},
+}
+
+// ViewNames are the views :view switches to
+var ViewNames = []string{"week", "day", "days", "month", "weeks", "year", "agenda"}
+
+// Split returns the command name of a line and its arguments
+func Split(line string) (string, []string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// Find returns the command with the given name or the only one it abbreviates, so ":g" runs
+// ":goto"
+func Find(name string) (Command, error) {
+	var matches []Command
+	for _, cmd := range Commands {
+		if cmd.Name == name {
+			return cmd, nil
+		}
+		if strings.HasPrefix(cmd.Name, name) {
+			matches = append(matches, cmd)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Command{}, fmt.Errorf("unknown command %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, cmd := range matches {
+			names[i] = cmd.Name
+		}
+		return Command{}, fmt.Errorf("ambiguous command %q: %s", name, strings.Join(names, ", "))
+	}
+}
+
+// ParseGoto reads the arguments of :goto relative to the selected time: a date, a time or both.
+// Dates are YYYY-MM-DD, YYYYMMDD, today, tomorrow or yesterday; times are HH:MM on the half hour
+// or an hour such as 14 or 14.5. Without a time the selected time of day is kept.
+func ParseGoto(args []string, current time.Time) (time.Time, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return time.Time{}, fmt.Errorf("usage: goto DATE [TIME]")
+	}
+
+	date := current
+	hour, minute := current.Hour(), current.Minute()
+	timeArg := args[len(args)-1]
+	if d, ok := parseDate(args[0], current); ok {
+		date = d
+		if len(args) == 1 {
+			timeArg = ""
+		}
+	} else if len(args) == 2 {
+		return time.Time{}, fmt.Errorf("invalid date %q", args[0])
+	}
+
+	if timeArg != "" {
+		var err error
+		if hour, minute, err = parseTime(timeArg); err != nil {
+			if len(args) == 1 {
+				return time.Time{}, fmt.Errorf("invalid date or time %q", timeArg)
+			}
+			return time.Time{}, err
+		}
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, current.Location()), nil
+}
+
+func parseDate(s string, current time.Time) (time.Time, bool) {
+	now := time.Now().In(current.Location())
+	switch strings.ToLower(s) {
+	case "today", "t":
+		return now, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	}
+	for _, layout := range []string{"2006-01-02", "20060102"} {
+		if date, err := time.ParseInLocation(layout, s, current.Location()); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseTime reads a time of day on the half-hour grid of the calendar
+func parseTime(s string) (int, int, error) {
+	if clock, err := time.Parse("15:04", s); err == nil {
+		if clock.Minute()%30 != 0 {
+			return 0, 0, fmt.Errorf("invalid time %q: times are on the hour or half hour", s)
+		}
+		return clock.Hour(), clock.Minute(), nil
+	}
+	if hours, err := strconv.ParseFloat(s, 64); err == nil && hours >= 0 && hours < 24 {
+		if half := hours * 2; half == float64(int(half)) {
+			return int(hours), int(half) % 2 * 30, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid time %q", s)
+}
+
+// ParseColor reads a color name, in any case, or its first letter
+func ParseColor(name string) (gocui.Attribute, error) {
+	for _, colorName := range calendar.GetColorNames() {
+		if strings.EqualFold(colorName, name) || strings.EqualFold(colorName[:1], name) {
+			return calendar.ColorNameToAttribute(colorName), nil
+		}
+	}
+	return gocui.ColorDefault, fmt.Errorf("unknown color %q (%s)", name, strings.ToLower(strings.Join(calendar.GetColorNames(), ", ")))
+}
+
+// Complete returns the lines a partly typed line can be completed to, in order. The first word
+// completes to commands and keymap actions, the words after it to the command's arguments.
+func Complete(line string) []string {
+	fields := strings.Fields(line)
+	endsWord := line == "" || strings.HasSuffix(line, " ")
+	if len(fields) == 0 || (len(fields) == 1 && !endsWord) {
+		word := ""
+		if len(fields) == 1 {
+			word = strings.ToLower(fields[0])
+		}
+		var names []string
+		for _, cmd := range Commands {
+			names = append(names, cmd.Name)
+		}
+		for _, action := range keymap.Actions {
+			names = append(names, action.Name)
+		}
+		return withPrefix("", word, names)
+	}
+
+	word := ""
+	if !endsWord {
+		word = fields[len(fields)-1]
+	}
+	head := line[:len(line)-len(word)]
+	cmd, err := Find(strings.ToLower(fields[0]))
+	if err != nil {
+		return nil
+	}
+	// The argument being completed, counting from 0
+	arg := len(fields) - 1
+	if !endsWord {
+		arg--
+	}
+
+	switch {
+	case cmd.Name == "view" && arg == 0:
+		return withPrefix(head, word, ViewNames)
+	case cmd.Name == "color" && arg == 0:
+		var colors []string
+		for _, name := range calendar.GetColorNames() {
+			colors = append(colors, strings.ToLower(name))
+		}
+		return withPrefix(head, word, colors)
+	case cmd.Name == "filter" && arg == 0:
+		return withPrefix(head, word, []string{"off", "delete"})
+	case cmd.Name == "export" && arg == 0:
+		return withPrefix(head, word, []string{"ics"})
+	case cmd.Name == "goto" && arg == 0:
+		return withPrefix(head, word, []string{"today", "tomorrow", "yesterday"})
+	case cmd.Name == "set" && arg == 0:
+		if name, value, ok := strings.Cut(word, "="); ok {
+			if setting, ok := FindSetting(name); ok {
+				return withPrefix(head+name+"=", value, setting.Values)
+			}
+			return nil
+		}
+		var names []string
+		for _, setting := range Settings {
+			names = append(names, setting.Name+"=")
+		}
+		return withPrefix(head, word, names)
+	}
+	return nil
+}
+
+// withPrefix returns head followed by each candidate that starts with word
+func withPrefix(head, word string, candidates []string) []string {
+	var lines []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, strings.ToLower(word)) {
+			lines = append(lines, head+candidate)
+		}
+	}
+	return lines
+}
+
+// Setting is an option that :set changes
+type Setting struct {
+	Name        string
+	Description string
+	// Values lists the accepted values, or is empty for numbers
+	Values []string
+}
+
+// Settings lists the options :set changes, named as in the config file
+var Settings = []Setting{
+	{"week_start", "First day of the week", []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}},
+	{"overlap_policy", "How overlapping events are handled", []string{"forbid", "warn", "allow"}},
+	{"default_color", "Color of new events, auto for one from the name", []string{"auto", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}},
+	{"default_event_length", "Hours new events last", nil},
+	{"detail_pane", "Where the event details pane opens", []string{"right", "bottom"}},
+	{"agenda_skip_empty_days", "Leave days without events out of the agenda", []string{"true", "false"}},
+}
+
+// FindSetting returns the :set option with the given name
+func FindSetting(name string) (Setting, bool) {
+	for _, setting := range Settings {
+		if setting.Name == strings.ToLower(name) {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// SettingNames returns the names of the :set options in alphabetical order
+func SettingNames() []string {
+	names := make([]string, len(Settings))
+	for i, setting := range Settings {
+		names[i] = setting.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSetting reads the arguments of :set, written "option=value" or "option value", and
+// returns the option with the value in the form the config file takes it. A value is empty if
+// only the option is given.
+func ParseSetting(args []string) (Setting, string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return Setting{}, "", fmt.Errorf("usage: set OPTION=VALUE")
+	}
+	name, value, hasValue := strings.Cut(args[0], "=")
+	if len(args) == 2 {
+		if hasValue {
+			return Setting{}, "", fmt.Errorf("usage: set OPTION=VALUE")
+		}
+		value, hasValue = args[1], true
+	}
+
+	setting, ok := FindSetting(name)
+	if !ok {
+		return Setting{}, "", fmt.Errorf("unknown option %q (%s)", name, strings.Join(SettingNames(), ", "))
+	}
+	if !hasValue {
+		return setting, "", nil
+	}
+
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch setting.Name {
+	case "week_start":
+		// Day names may be abbreviated as in the config file
+		if len(value) >= 3 {
+			for _, day := range setting.Values {
+				if strings.HasPrefix(day, value) {
+					return setting, day, nil
+				}
+			}
+		}
+	case "default_color":
+		if value == "auto" {
+			return setting, value, nil
+		}
+		if color, err := ParseColor(value); err == nil {
+			return setting, strings.ToLower(calendar.ColorAttributeToName(color)), nil
+		}
+	case "default_event_length":
+		// Lengths are whole or half hours up to a day
+		if hours, err := strconv.ParseFloat(value, 64); err == nil && hours >= 0.5 && hours <= 24 && hours*2 == float64(int(hours*2)) {
+			return setting, strconv.FormatFloat(hours, 'f', -1, 64), nil
+		}
+	case "agenda_skip_empty_days":
+		switch value {
+		case "true", "on", "yes":
+			return setting, "true", nil
+		case "false", "off", "no":
+			return setting, "false", nil
+		}
+	default:
+		for _, allowed := range setting.Values {
+			if value == allowed {
+				return setting, value, nil
+			}
+		}
+	}
+
+	if len(setting.Values) == 0 {
+		return Setting{}, "", fmt.Errorf("invalid value %q for %s", value, setting.Name)
+	}
+	return Setting{}, "", fmt.Errorf("invalid value %q for %s (%s)", value, setting.Name, strings.Join(setting.Values, ", "))
+}
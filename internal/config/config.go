@@ -2,8 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
 )
 
 type Config struct {
@@ -15,6 +20,43 @@ type Config struct {
 	NotificationMinutes     int    `json:"notification_minutes,omitempty"`
 	DefaultColor            string `json:"default_color,omitempty"`
 	DefaultEventLength      float64 `json:"default_event_length,omitempty"`
+	ServeAddress            string  `json:"serve_address,omitempty"`
+	ServeUsername           string  `json:"serve_username,omitempty"`
+	ServePassword           string  `json:"serve_password,omitempty"`
+	ServeReadOnly           bool    `json:"serve_read_only,omitempty"`
+	APIEnabled              bool    `json:"api_enabled,omitempty"`
+	APISocket               string  `json:"api_socket,omitempty"`
+	OverlapPolicy           string  `json:"overlap_policy,omitempty"`
+	WorkingHours            string  `json:"working_hours,omitempty"`
+	WorkingDays             string  `json:"working_days,omitempty"`
+	RollingDays             int     `json:"rolling_days,omitempty"`
+	CompactWeeks            int     `json:"compact_weeks,omitempty"`
+	WeekStart               string  `json:"week_start,omitempty"`
+	AgendaDays              int     `json:"agenda_days,omitempty"`
+	AgendaSkipEmptyDays     bool    `json:"agenda_skip_empty_days,omitempty"`
+	DetailPane              string  `json:"detail_pane,omitempty"`
+	ShowDetailPane          bool    `json:"show_detail_pane,omitempty"`
+	DisableMouse            bool    `json:"disable_mouse,omitempty"`
+	Keybindings             map[string]KeyList `json:"keybindings,omitempty"`
+	Filters                 []calendar.Filter  `json:"filters,omitempty"`
+}
+
+// KeyList is the keys bound to an action, written as a list or as a single key
+type KeyList []string
+
+func (k *KeyList) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*k = KeyList{key}
+		return nil
+	}
+
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
 }
 
 func GetDefaultConfig() *Config {
@@ -27,6 +69,25 @@ func GetDefaultConfig() *Config {
 		NotificationMinutes:     15, // Default to 15 minutes before
 		DefaultColor:            "", // Empty means auto-generate from event name
 		DefaultEventLength:      1.0, // Default to 1 hour
+		ServeAddress:            "", // Empty means use default bind address
+		ServeUsername:           "", // Empty means no authentication
+		ServePassword:           "",
+		ServeReadOnly:           false, // Default to allowing writes
+		APIEnabled:              false, // Default to no API socket while the TUI runs
+		APISocket:               "", // Empty means use default socket path
+		OverlapPolicy:           "forbid", // Default to refusing overlapping events
+		WorkingHours:            "09:00-17:00", // Window searched for free slots
+		WorkingDays:             "mon-fri", // Days searched for free slots
+		RollingDays:             4, // Columns of the rolling days view
+		CompactWeeks:            2, // Rows of the compact weeks grid
+		WeekStart:               "sunday", // First day of the week in every view
+		AgendaDays:              7, // Days listed by the agenda
+		AgendaSkipEmptyDays:     false, // Default to listing days without events
+		DetailPane:              "right", // Side of the calendar the event detail pane opens on
+		ShowDetailPane:          false, // Default to opening the detail pane with i
+		DisableMouse:            false, // Default to clicking, dragging and scrolling the calendar
+		Keybindings:             nil, // Empty means every action keeps its default keys
+		Filters:                 nil, // Filters can also be saved from the search popup
 	}
 }
 
@@ -101,7 +162,7 @@ func GetDatabasePath(config *Config) string {
 func GetDefaultView(config *Config) string {
 	if config.DefaultView != "" {
 		switch config.DefaultView {
-		case "week", "month", "agenda":
+		case "week", "day", "days", "month", "weeks", "year", "agenda":
 			return config.DefaultView
 		default:
 			return "week" // fallback to week if invalid value
@@ -110,6 +171,70 @@ func GetDefaultView(config *Config) string {
 	return "week"
 }
 
+// GetRollingDays returns the number of days (1-14) shown side by side by the rolling days view
+func GetRollingDays(config *Config) int {
+	if config.RollingDays < 1 || config.RollingDays > 14 {
+		return 4 // Default to today and the next three days
+	}
+	return config.RollingDays
+}
+
+// GetCompactWeeks returns the number of weeks (2-4) shown by the compact weeks grid
+func GetCompactWeeks(config *Config) int {
+	if config.CompactWeeks < 2 || config.CompactWeeks > 4 {
+		return 2 // Default to two weeks
+	}
+	return config.CompactWeeks
+}
+
+// GetAgendaDays returns the number of days (1, 7, 14 or 30) listed by the agenda
+func GetAgendaDays(config *Config) int {
+	switch config.AgendaDays {
+	case 1, 7, 14, 30:
+		return config.AgendaDays
+	default:
+		return 7 // Default to a week
+	}
+}
+
+// IsAgendaSkipEmptyDays returns true if the agenda should leave out days without events
+func IsAgendaSkipEmptyDays(config *Config) bool {
+	return config.AgendaSkipEmptyDays
+}
+
+// GetDetailPanePosition returns where the event detail pane opens: "right" or "bottom"
+func GetDetailPanePosition(config *Config) string {
+	if config.DetailPane == "bottom" {
+		return "bottom"
+	}
+	return "right" // Default to the right of the calendar
+}
+
+// IsDetailPaneShown returns true if the event detail pane should be open at startup
+func IsDetailPaneShown(config *Config) bool {
+	return config.ShowDetailPane
+}
+
+// IsMouseEnabled returns true unless the mouse is disabled, leaving it to the terminal to select
+// text
+func IsMouseEnabled(config *Config) bool {
+	return !config.DisableMouse
+}
+
+// GetWeekStart returns the configured first day of the week, defaulting to Sunday. Day names may
+// be abbreviated to three letters, e.g. "mon".
+func GetWeekStart(config *Config) time.Weekday {
+	value := strings.ToLower(strings.TrimSpace(config.WeekStart))
+	if len(value) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.HasPrefix(strings.ToLower(day.String()), value) {
+				return day
+			}
+		}
+	}
+	return time.Sunday
+}
+
 // IsWeatherEnabled returns true if weather location is configured
 func IsWeatherEnabled(config *Config) bool {
 	return config.WeatherLocation != ""
@@ -165,4 +290,95 @@ func GetDefaultEventLength(config *Config) float64 {
 		return 1.0 // Default to 1 hour if invalid
 	}
 	return length
-}
\ No newline at end of file
+}
+
+// GetOverlapPolicy returns how overlapping events are handled: "forbid", "warn" or "allow"
+func GetOverlapPolicy(config *Config) string {
+	switch config.OverlapPolicy {
+	case "forbid", "warn", "allow":
+		return config.OverlapPolicy
+	default:
+		return "forbid" // Default to forbid if unset or invalid
+	}
+}
+
+// GetWorkingHours returns the working-hours window searched for free slots, e.g. "09:00-17:00"
+func GetWorkingHours(config *Config) string {
+	if config.WorkingHours != "" {
+		return config.WorkingHours
+	}
+	return "09:00-17:00"
+}
+
+// GetWorkingDays returns the days searched for free slots, e.g. "mon-fri" or "all"
+func GetWorkingDays(config *Config) string {
+	if config.WorkingDays != "" {
+		return config.WorkingDays
+	}
+	return "mon-fri"
+}
+
+// GetServeAddress returns the bind address for serve mode, defaulting to localhost only
+func GetServeAddress(config *Config) string {
+	if config.ServeAddress != "" {
+		return config.ServeAddress
+	}
+	return "127.0.0.1:5232"
+}
+
+// IsServeAuthEnabled returns true if basic auth credentials are configured for serve mode
+func IsServeAuthEnabled(config *Config) bool {
+	return config.ServeUsername != ""
+}
+
+// IsServeReadOnly returns true if serve mode should reject writes
+func IsServeReadOnly(config *Config) bool {
+	return config.ServeReadOnly
+}
+
+// IsAPIEnabled returns true if the TUI should serve the JSON-RPC API
+func IsAPIEnabled(config *Config) bool {
+	return config.APIEnabled
+}
+
+// GetAPISocketPath returns the Unix socket path for the JSON-RPC API
+func GetAPISocketPath(config *Config) string {
+	if config.APISocket != "" {
+		return config.APISocket
+	}
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "chronos.sock")
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "chronos.sock" // fallback to current directory
+	}
+
+	return filepath.Join(homeDir, ".local", "share", "chronos", "chronos.sock")
+}
+
+// GetKeybindings returns the configured keys of every remapped action
+func GetKeybindings(config *Config) map[string][]string {
+	keybindings := make(map[string][]string, len(config.Keybindings))
+	for action, keys := range config.Keybindings {
+		keybindings[action] = keys
+	}
+	return keybindings
+}
+
+// GetFilters returns the filters defined in the config, or an error naming the first invalid one
+func GetFilters(config *Config) ([]calendar.Filter, error) {
+	seen := make(map[string]bool, len(config.Filters))
+	for _, filter := range config.Filters {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[filter.Name] {
+			return nil, fmt.Errorf("filter %s is defined twice", filter.Name)
+		}
+		seen[filter.Name] = true
+	}
+	return append([]calendar.Filter(nil), config.Filters...), nil
+}
@@ -0,0 +1,77 @@
+package database
+
+// CalDAVResource links the resource name a CalDAV client created an event under, and the UID the
+// client gave it, to the event's ID. Resources outlive their events, so that undoing a delete
+// restores the event under the same name.
+type CalDAVResource struct {
+	Name    string
+	UID     string
+	EventId int
+}
+
+// SaveCalDAVResource saves a resource, replacing any resource with the same name or event
+func (database *Database) SaveCalDAVResource(resource CalDAVResource) error {
+	_, err := database.db.Exec(`
+        INSERT OR REPLACE INTO caldav_resources (name, uid, event_id) VALUES (?, ?, ?)`,
+		resource.Name,
+		resource.UID,
+		resource.EventId,
+	)
+	return err
+}
+
+// GetCalDAVResource retrieves a resource by name, or nil if no client created one by that name
+func (database *Database) GetCalDAVResource(name string) (*CalDAVResource, error) {
+	return database.getCalDAVResource("name = ?", name)
+}
+
+// GetCalDAVResourceByUID retrieves the resource of an existing event by the UID its client gave
+// the event, or nil
+func (database *Database) GetCalDAVResourceByUID(uid string) (*CalDAVResource, error) {
+	if uid == "" {
+		return nil, nil
+	}
+	return database.getCalDAVResource("uid = ? AND event_id IN (SELECT id FROM events)", uid)
+}
+
+func (database *Database) getCalDAVResource(where, value string) (*CalDAVResource, error) {
+	rows, err := database.db.Query(`
+        SELECT name, uid, event_id FROM caldav_resources WHERE `+where,
+		value,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var resource CalDAVResource
+		if err := rows.Scan(&resource.Name, &resource.UID, &resource.EventId); err != nil {
+			return nil, err
+		}
+		return &resource, nil
+	}
+
+	return nil, rows.Err()
+}
+
+// GetCalDAVResources retrieves every resource created by a client, by event ID
+func (database *Database) GetCalDAVResources() (map[int]CalDAVResource, error) {
+	rows, err := database.db.Query(`
+        SELECT name, uid, event_id FROM caldav_resources`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resources := make(map[int]CalDAVResource)
+	for rows.Next() {
+		var resource CalDAVResource
+		if err := rows.Scan(&resource.Name, &resource.UID, &resource.EventId); err != nil {
+			return nil, err
+		}
+		resources[resource.EventId] = resource
+	}
+
+	return resources, rows.Err()
+}
@@ -51,6 +51,37 @@ func (database *Database) createTables() error {
 		return err
 	}
 
+	_, err = database.db.Exec(`
+        CREATE TABLE IF NOT EXISTS marks (
+        name TEXT NOT NULL PRIMARY KEY,
+        time DATETIME NOT NULL
+    )`)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.db.Exec(`
+        CREATE TABLE IF NOT EXISTS filters (
+        name TEXT NOT NULL PRIMARY KEY,
+        text TEXT NOT NULL DEFAULT '',
+        color TEXT NOT NULL DEFAULT '',
+        tag TEXT NOT NULL DEFAULT '',
+        from_date TEXT NOT NULL DEFAULT '',
+        to_date TEXT NOT NULL DEFAULT ''
+    )`)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.db.Exec(`
+        CREATE TABLE IF NOT EXISTS caldav_resources (
+        name TEXT NOT NULL PRIMARY KEY,
+        uid TEXT NOT NULL DEFAULT '',
+        event_id INTEGER NOT NULL UNIQUE
+    )`)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
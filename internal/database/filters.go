@@ -0,0 +1,47 @@
+package database
+
+import (
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// SaveFilter saves a filter created in chronos, replacing any saved filter with the same name
+func (database *Database) SaveFilter(filter calendar.Filter) error {
+	_, err := database.db.Exec(`
+        INSERT OR REPLACE INTO filters (name, text, color, tag, from_date, to_date)
+        VALUES (?, ?, ?, ?, ?, ?)`,
+		filter.Name,
+		filter.Text,
+		filter.Color,
+		filter.Tag,
+		filter.From,
+		filter.To,
+	)
+	return err
+}
+
+// GetFilters retrieves every saved filter in order of name
+func (database *Database) GetFilters() ([]calendar.Filter, error) {
+	rows, err := database.db.Query(`
+        SELECT name, text, color, tag, from_date, to_date FROM filters ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filters []calendar.Filter
+	for rows.Next() {
+		var filter calendar.Filter
+		if err := rows.Scan(&filter.Name, &filter.Text, &filter.Color, &filter.Tag, &filter.From, &filter.To); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, rows.Err()
+}
+
+// DeleteFilter removes a saved filter by name
+func (database *Database) DeleteFilter(name string) error {
+	_, err := database.db.Exec("DELETE FROM filters WHERE name = ?", name)
+	return err
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"time"
+)
+
+// Mark is a named date and time to jump back to, set with a letter as in vim
+type Mark struct {
+	Name string
+	Time time.Time
+}
+
+// SetMark saves a mark, replacing any mark with the same name. Times are stored in UTC like
+// event times.
+func (database *Database) SetMark(name string, t time.Time) error {
+	_, err := database.db.Exec(`
+        INSERT OR REPLACE INTO marks (name, time) VALUES (?, ?)`,
+		name,
+		t.UTC(),
+	)
+	return err
+}
+
+// GetMark retrieves a mark by name, or nil if it is not set
+func (database *Database) GetMark(name string) (*Mark, error) {
+	rows, err := database.db.Query(`
+        SELECT name, time FROM marks WHERE name = ?`,
+		name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var mark Mark
+		if err := rows.Scan(&mark.Name, &mark.Time); err != nil {
+			return nil, err
+		}
+		return &mark, nil
+	}
+
+	return nil, rows.Err()
+}
+
+// GetMarks retrieves every mark in order of name
+func (database *Database) GetMarks() ([]Mark, error) {
+	rows, err := database.db.Query(`
+        SELECT name, time FROM marks ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var marks []Mark
+	for rows.Next() {
+		var mark Mark
+		if err := rows.Scan(&mark.Name, &mark.Time); err != nil {
+			return nil, err
+		}
+		marks = append(marks, mark)
+	}
+
+	return marks, rows.Err()
+}
+
+// DeleteMark removes a mark by name
+func (database *Database) DeleteMark(name string) error {
+	_, err := database.db.Exec("DELETE FROM marks WHERE name = ?", name)
+	return err
+}
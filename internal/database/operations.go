@@ -6,10 +6,33 @@ import (
 
 // AddEvent inserts a new event into the database
 func (database *Database) AddEvent(event calendar.Event) (int, error) {
+	return database.insertEvent(nil, event)
+}
+
+// RestoreEvent re-inserts a deleted event, as undo and redo do, under its old ID so that it keeps
+// any CalDAV resource it had. It gets a new ID if the old one has been taken since.
+func (database *Database) RestoreEvent(event calendar.Event) (int, error) {
+	var taken bool
+	if err := database.db.QueryRow("SELECT EXISTS (SELECT 1 FROM events WHERE id = ?)", event.Id).Scan(&taken); err != nil {
+		return -1, err
+	}
+	if taken || event.Id <= 0 {
+		return database.insertEvent(nil, event)
+	}
+	return database.insertEvent(event.Id, event)
+}
+
+// insertEvent inserts an event under id, or a new ID if id is nil. New IDs are above any a CalDAV
+// resource keeps for a deleted event, so that a new event never takes over a client's resource.
+func (database *Database) insertEvent(id interface{}, event calendar.Event) (int, error) {
 	result, err := database.db.Exec(`
         INSERT INTO events (
-            name, description, location, time, duration, frequency, occurence, color
-        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+            id, name, description, location, time, duration, frequency, occurence, color
+        ) VALUES (
+            COALESCE(?, (SELECT COALESCE(MAX(id), 0) + 1 FROM (
+                SELECT id FROM events UNION ALL SELECT event_id FROM caldav_resources))),
+            ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id,
 		event.Name,
 		event.Description,
 		event.Location,
@@ -23,12 +46,12 @@ func (database *Database) AddEvent(event calendar.Event) (int, error) {
 		return -1, err
 	}
 
-	id, err := result.LastInsertId()
+	newId, err := result.LastInsertId()
 	if err != nil {
 		return -1, err
 	}
 
-	return int(id), err
+	return int(newId), err
 }
 
 // DeleteEventById removes an event by its ID
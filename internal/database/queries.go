@@ -252,6 +252,24 @@ type SearchCriteria struct {
 	StartTime string
 	EndDate   string
 	EndTime   string
+	// WeekStart is the first day of the week used by the 'w' (this week) date shortcut
+	WeekStart time.Weekday
+}
+
+// resolveSearchDate expands the 't' (today) and 'w' (this week) shortcuts into a YYYYMMDD date.
+// 'w' is the first day of the current week for a start date and the last day for an end date.
+func resolveSearchDate(date string, weekStart time.Weekday, isEnd bool) string {
+	switch date {
+	case "t":
+		return time.Now().Format("20060102")
+	case "w":
+		start := calendar.StartOfWeek(time.Now(), weekStart)
+		if isEnd {
+			return start.AddDate(0, 0, 6).Format("20060102")
+		}
+		return start.Format("20060102")
+	}
+	return date
 }
 
 // SearchEventsWithFilters searches for events with text query and optional date/time filters
@@ -272,11 +290,7 @@ func (database *Database) SearchEventsWithFilters(criteria SearchCriteria) ([]*c
 	
 	// Parse start date/time
 	if criteria.StartDate != "" {
-		startDate := criteria.StartDate
-		// Handle 't' shortcut for today
-		if startDate == "t" {
-			startDate = time.Now().Format("20060102")
-		}
+		startDate := resolveSearchDate(criteria.StartDate, criteria.WeekStart, false)
 		
 		startTime := "00:00"
 		if criteria.StartTime != "" {
@@ -289,11 +303,7 @@ func (database *Database) SearchEventsWithFilters(criteria SearchCriteria) ([]*c
 	
 	// Parse end date/time
 	if criteria.EndDate != "" {
-		endDate := criteria.EndDate
-		// Handle 't' shortcut for today
-		if endDate == "t" {
-			endDate = time.Now().Format("20060102")
-		}
+		endDate := resolveSearchDate(criteria.EndDate, criteria.WeekStart, true)
 		
 		endTime := "23:59"
 		if criteria.EndTime != "" {
@@ -0,0 +1,113 @@
+package eventmanager
+
+import (
+	"fmt"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// AddEvents adds several (local time) events as a single undo step described by description,
+// e.g. "paste 3 events". Nothing is added if any of them is refused by the overlap policy.
+func (em *EventManager) AddEvents(events []calendar.Event, description string) ([]*calendar.Event, bool) {
+	if len(events) == 1 {
+		added, ok := em.AddEvent(events[0])
+		if !ok {
+			return nil, false
+		}
+		return []*calendar.Event{added}, true
+	}
+
+	var changes []EventChange
+	var added []*calendar.Event
+	var warning *calendar.Event
+	var overlaps []*calendar.Event
+	for i := range events {
+		event := events[i]
+		// Events added earlier in the batch are already stored, so they are checked too
+		overlapping, ok := em.checkOverlapPolicy(&event, "Cannot Add Events", event.Name+" would overlap with an existing event")
+		if !ok {
+			em.undoChanges(changes)
+			return nil, false
+		}
+		id, err := em.database.AddEvent(*em.toUTC(&event))
+		if err != nil {
+			em.showError("Cannot Add Events", "Failed to save "+event.Name+": "+err.Error())
+			em.undoChanges(changes)
+			return nil, false
+		}
+		event.Id = id
+		changes = append(changes, EventChange{After: &event})
+		added = append(added, &event)
+		if warning == nil && len(overlapping) > 0 {
+			warning, overlaps = &event, overlapping
+		}
+	}
+	if len(changes) == 0 {
+		return nil, true
+	}
+
+	em.pushUndoAction(UndoAction{
+		Type:        ActionBatch,
+		Description: description,
+		Changes:     changes,
+	})
+	if warning != nil {
+		em.warnOverlap(warning, overlaps)
+	}
+	return added, true
+}
+
+// UpdateEvents saves several edited (local time) events as a single undo step described by
+// description, e.g. "shift 3 events". The events are matched by ID and only checked for overlaps
+// with events outside the batch, which move with them.
+func (em *EventManager) UpdateEvents(events []*calendar.Event, description string) bool {
+	if len(events) == 1 {
+		edited := *events[0]
+		return em.UpdateEvent(edited.Id, &edited)
+	}
+
+	ids := make([]int, len(events))
+	for i, event := range events {
+		ids[i] = event.Id
+	}
+
+	var changes []EventChange
+	var warning *calendar.Event
+	var overlaps []*calendar.Event
+	for _, event := range events {
+		before, err := em.database.GetEventById(event.Id)
+		if err != nil || before == nil {
+			em.showError("Event Not Found", fmt.Sprintf("Cannot update %s: event does not exist", event.Name))
+			em.undoChanges(changes)
+			return false
+		}
+		edited := *event
+		overlapping, ok := em.checkOverlapPolicy(&edited, "Cannot Edit Events", edited.Name+" would overlap with an existing event", ids...)
+		if !ok {
+			em.undoChanges(changes)
+			return false
+		}
+		if err := em.database.UpdateEventById(edited.Id, em.toUTC(&edited)); err != nil {
+			em.showError("Cannot Edit Events", "Failed to save "+edited.Name+": "+err.Error())
+			em.undoChanges(changes)
+			return false
+		}
+		changes = append(changes, EventChange{Before: em.toLocal(before), After: &edited})
+		if warning == nil && len(overlapping) > 0 {
+			warning, overlaps = &edited, overlapping
+		}
+	}
+	if len(changes) == 0 {
+		return true
+	}
+
+	em.pushUndoAction(UndoAction{
+		Type:        ActionBatch,
+		Description: description,
+		Changes:     changes,
+	})
+	if warning != nil {
+		em.warnOverlap(warning, overlaps)
+	}
+	return true
+}
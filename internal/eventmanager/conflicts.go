@@ -0,0 +1,308 @@
+package eventmanager
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// Resolution is a way of resolving an add or edit that overlaps other events
+type Resolution string
+
+const (
+	ResolveShift   Resolution = "shift"   // move the event to the next free slot of the same length
+	ResolveShorten Resolution = "shorten" // end the event when the next conflicting event starts
+	ResolvePush    Resolution = "push"    // move the conflicting events later, cascading as needed
+)
+
+// maxShiftSearch limits how far ahead ResolveShift looks for a free slot
+const maxShiftSearch = 14 * 24 * time.Hour
+
+// ConflictPlan describes the changes that resolve a conflict, in local time
+type ConflictPlan struct {
+	Resolution Resolution
+	Event      *calendar.Event   // the added or edited event after resolution
+	Moved      []*calendar.Event // conflicting events with their new times (ResolvePush only)
+
+	// For a series, Event is the first occurrence the resolution changes, Series holds every
+	// occurrence to add and Resolved counts the ones changed
+	Series   []*calendar.Event
+	Resolved int
+}
+
+// overlapFinder returns the (local time) events overlapping a (local time) event
+type overlapFinder func(event *calendar.Event, excludeIds ...int) ([]*calendar.Event, error)
+
+// PlanConflictResolution works out how to resolve the overlaps of a (local time) event without
+// changing anything. eventId is the ID of the edited event, or 0 for a new event.
+func (em *EventManager) PlanConflictResolution(event *calendar.Event, eventId int, resolution Resolution) (*ConflictPlan, error) {
+	return planResolution(event, eventId, resolution, em.findLocalOverlaps)
+}
+
+// planResolution works out how to resolve the overlaps of an event with the events find returns
+func planResolution(event *calendar.Event, eventId int, resolution Resolution, find overlapFinder) (*ConflictPlan, error) {
+	planned := *event
+	plan := &ConflictPlan{Resolution: resolution, Event: &planned}
+
+	switch resolution {
+	case ResolveShift:
+		limit := event.Time.Add(maxShiftSearch)
+		for {
+			overlapping, err := find(&planned, eventId)
+			if err != nil {
+				return nil, err
+			}
+			if len(overlapping) == 0 {
+				return plan, nil
+			}
+			// Jump past the last conflicting event and try again
+			for _, other := range overlapping {
+				if end := other.EndTime(); end.After(planned.Time) {
+					planned.Time = end
+				}
+			}
+			if planned.Time.After(limit) {
+				return nil, errors.New("no free slot of the same length in the next 14 days")
+			}
+		}
+
+	case ResolveShorten:
+		overlapping, err := find(&planned, eventId)
+		if err != nil {
+			return nil, err
+		}
+		end := planned.EndTime()
+		for _, other := range overlapping {
+			if !other.Time.After(planned.Time) {
+				return nil, errors.New(other.Name + " is already on at the start time")
+			}
+			if other.Time.Before(end) {
+				end = other.Time
+			}
+		}
+		// Durations are stored in half hours
+		halfHours := int(end.Sub(planned.Time) / (30 * time.Minute))
+		if halfHours < 1 {
+			return nil, errors.New("less than 30 minutes free before the next event")
+		}
+		planned.DurationHour = float64(halfHours) / 2
+		return plan, nil
+
+	case ResolvePush:
+		moved, err := planPush(&planned, eventId, find)
+		if err != nil {
+			return nil, err
+		}
+		plan.Moved = moved
+		return plan, nil
+
+	default:
+		return nil, errors.New("unknown resolution: " + string(resolution))
+	}
+}
+
+// planPush moves every event overlapping the planned event to start after it, then moves
+// whatever those now overlap, until nothing overlaps
+func planPush(event *calendar.Event, eventId int, find overlapFinder) ([]*calendar.Event, error) {
+	// Events being moved no longer block their old slots
+	exclude := []int{eventId}
+	queue, err := find(event, exclude...)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range queue {
+		exclude = append(exclude, other.Id)
+	}
+
+	var moved []*calendar.Event
+	nextFree := event.EndTime()
+	for len(queue) > 0 {
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].Time.Before(queue[j].Time)
+		})
+		next := queue[0]
+		queue = queue[1:]
+
+		if next.Time.Before(nextFree) {
+			next.Time = nextFree
+		}
+		nextFree = next.EndTime()
+		moved = append(moved, next)
+
+		// The moved event may now run into later events, which are pushed in turn
+		overlapping, err := find(next, exclude...)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range overlapping {
+			exclude = append(exclude, other.Id)
+			queue = append(queue, other)
+		}
+	}
+
+	return moved, nil
+}
+
+// findLocalOverlaps returns the events overlapping a (local time) event, converted to local time
+func (em *EventManager) findLocalOverlaps(event *calendar.Event, excludeIds ...int) ([]*calendar.Event, error) {
+	overlapping, err := em.FindOverlappingEvents(event, excludeIds...)
+	if err != nil {
+		return nil, err
+	}
+	for i, other := range overlapping {
+		overlapping[i] = em.toLocal(other)
+	}
+	return overlapping, nil
+}
+
+// PlanSeriesResolution works out how to resolve the overlaps of a new (local time) series without
+// changing anything. Occurrences are resolved in order, each around the events stored, the
+// occurrences before it and the events pushed so far; those without overlaps stay as they are.
+func (em *EventManager) PlanSeriesResolution(events []calendar.Event, resolution Resolution) (*ConflictPlan, error) {
+	plan := &ConflictPlan{Resolution: resolution}
+	// Occurrences go by negative IDs while planning, as ID 0 is excluded as the one being planned
+	var series []*calendar.Event
+	var moved []*calendar.Event
+	movedIds := make(map[int]int) // stored event ID to index in moved
+
+	find := func(event *calendar.Event, excludeIds ...int) ([]*calendar.Event, error) {
+		// Pushed events block their new slots rather than their stored ones
+		skip := append([]int{}, excludeIds...)
+		for id := range movedIds {
+			skip = append(skip, id)
+		}
+		overlapping, err := em.findLocalOverlaps(event, skip...)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range append(append([]*calendar.Event{}, moved...), series...) {
+			if containsId(excludeIds, other.Id) || !other.Time.Before(event.EndTime()) || !other.EndTime().After(event.Time) {
+				continue
+			}
+			// planPush moves what it is given, so the plan so far only changes through its result
+			copied := *other
+			overlapping = append(overlapping, &copied)
+		}
+		return overlapping, nil
+	}
+
+	for i := range events {
+		occurrence := events[i]
+		occurrence.Id = -(i + 1)
+		overlapping, err := find(&occurrence, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(overlapping) == 0 {
+			series = append(series, &occurrence)
+			continue
+		}
+
+		occurrence.Id = 0
+		resolved, err := planResolution(&occurrence, 0, resolution, find)
+		if err != nil {
+			return nil, errors.New(occurrence.Time.Format("Mon 2 Jan 15:04") + ": " + err.Error())
+		}
+		for _, event := range resolved.Moved {
+			if event.Id < 0 {
+				*series[-event.Id-1] = *event
+			} else if index, ok := movedIds[event.Id]; ok {
+				moved[index] = event
+			} else {
+				movedIds[event.Id] = len(moved)
+				moved = append(moved, event)
+			}
+		}
+		resolved.Event.Id = -(i + 1)
+		series = append(series, resolved.Event)
+		plan.Resolved++
+		if plan.Event == nil {
+			plan.Event = resolved.Event
+		}
+	}
+
+	for _, event := range series {
+		event.Id = 0
+	}
+	if plan.Event == nil && len(series) > 0 {
+		plan.Event = series[0]
+	}
+	plan.Series = series
+	plan.Moved = moved
+	return plan, nil
+}
+
+// containsId reports whether ids holds id
+func containsId(ids []int, id int) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyConflictPlan saves a conflict plan as a single undoable action. eventId is the ID of the
+// edited event, or 0 to add plan.Event, or every occurrence of plan.Series, as new events.
+func (em *EventManager) ApplyConflictPlan(plan *ConflictPlan, eventId int) bool {
+	var changes []EventChange
+
+	// Move the conflicting events first so the resolved event has room
+	for _, event := range plan.Moved {
+		before, err := em.database.GetEventById(event.Id)
+		if err != nil || before == nil {
+			em.showError("Cannot Resolve Conflict", "Conflicting event no longer exists")
+			em.undoChanges(changes)
+			return false
+		}
+		if err := em.database.UpdateEventById(event.Id, em.toUTC(event)); err != nil {
+			em.showError("Cannot Resolve Conflict", "Failed to move "+event.Name+": "+err.Error())
+			em.undoChanges(changes)
+			return false
+		}
+		changes = append(changes, EventChange{Before: em.toLocal(before), After: event})
+	}
+
+	if eventId == 0 {
+		events := plan.Series
+		if len(events) == 0 {
+			events = []*calendar.Event{plan.Event}
+		}
+		for _, event := range events {
+			id, err := em.database.AddEvent(*em.toUTC(event))
+			if err != nil {
+				em.showError("Cannot Add Event", "Failed to save event: "+err.Error())
+				em.undoChanges(changes)
+				return false
+			}
+			added := *event
+			added.Id = id
+			changes = append(changes, EventChange{After: &added})
+		}
+	} else {
+		before, err := em.database.GetEventById(eventId)
+		if err != nil || before == nil {
+			em.showError("Event Not Found", "Cannot update event: event does not exist")
+			em.undoChanges(changes)
+			return false
+		}
+		edited := *plan.Event
+		edited.Id = eventId
+		if err := em.database.UpdateEventById(eventId, em.toUTC(&edited)); err != nil {
+			em.showError("Cannot Edit Event", "Failed to save changes: "+err.Error())
+			em.undoChanges(changes)
+			return false
+		}
+		changes = append(changes, EventChange{Before: em.toLocal(before), After: &edited})
+	}
+
+	em.pushUndoAction(UndoAction{
+		Type:        ActionBatch,
+		Description: "resolve conflict: " + plan.Event.Name,
+		Changes:     changes,
+	})
+
+	return true
+}
@@ -16,6 +16,16 @@ const (
 	ActionDelete ActionType = "delete"
 	ActionEdit   ActionType = "edit"
 	ActionBulkDelete ActionType = "bulk_delete"
+	ActionBatch      ActionType = "batch"
+)
+
+// OverlapPolicy decides what happens when an added or edited event overlaps another one
+type OverlapPolicy string
+
+const (
+	OverlapForbid OverlapPolicy = "forbid" // refuse the change (default)
+	OverlapWarn   OverlapPolicy = "warn"   // save the change and report the overlap
+	OverlapAllow  OverlapPolicy = "allow"  // save the change silently
 )
 
 type UndoAction struct {
@@ -24,6 +34,14 @@ type UndoAction struct {
 	EventAfter  *calendar.Event   // State after action (nil for delete)
 	EventIds    []int             // For bulk operations (legacy)
 	Events      []*calendar.Event // Full events for bulk operations
+	Description string            // For batch operations
+	Changes     []EventChange     // For batch operations, in the order they were applied
+}
+
+// EventChange is one event's part of a batch action (Before is nil for an add, After is nil for a delete)
+type EventChange struct {
+	Before *calendar.Event
+	After  *calendar.Event
 }
 
 type EventManager struct {
@@ -32,6 +50,8 @@ type EventManager struct {
 	redoStack  []UndoAction
 	maxUndos   int
 	errorHandler func(title, message string) // Callback for displaying errors
+	overlapPolicy OverlapPolicy
+	lastChange    *RepeatableChange // the change '.' repeats
 }
 
 func NewEventManager(db *database.Database) *EventManager {
@@ -40,14 +60,42 @@ func NewEventManager(db *database.Database) *EventManager {
 		undoStack: make([]UndoAction, 0),
 		redoStack: make([]UndoAction, 0),
 		maxUndos:  50, // Keep last 50 actions
+		overlapPolicy: OverlapForbid,
+	}
+}
+
+// SetOverlapPolicy sets how overlapping events are handled; unknown policies mean forbid
+func (em *EventManager) SetOverlapPolicy(policy OverlapPolicy) {
+	switch policy {
+	case OverlapWarn, OverlapAllow:
+		em.overlapPolicy = policy
+	default:
+		em.overlapPolicy = OverlapForbid
 	}
 }
 
+// GetOverlapPolicy returns how overlapping events are handled
+func (em *EventManager) GetOverlapPolicy() OverlapPolicy {
+	return em.overlapPolicy
+}
+
 // SetErrorHandler sets the error display callback
 func (em *EventManager) SetErrorHandler(handler func(title, message string)) {
 	em.errorHandler = handler
 }
 
+// CaptureErrors runs fn with errors redirected away from the error handler and returns the last one reported
+func (em *EventManager) CaptureErrors(fn func()) (title, message string) {
+	previous := em.errorHandler
+	em.errorHandler = func(t, m string) {
+		title, message = t, m
+	}
+	defer func() { em.errorHandler = previous }()
+
+	fn()
+	return title, message
+}
+
 // toUTC converts an event's time to UTC for database storage
 func (em *EventManager) toUTC(event *calendar.Event) *calendar.Event {
 	utcEvent := *event
@@ -74,14 +122,9 @@ func (em *EventManager) AddEvent(event calendar.Event) (*calendar.Event, bool) {
 	// Convert to UTC for database storage
 	utcEvent := em.toUTC(&event)
 	
-	// Check for overlaps before adding (using UTC time for consistency)
-	hasOverlap, err := em.database.CheckEventOverlap(*utcEvent)
-	if err != nil {
-		em.showError("Database Error", "Failed to check for overlapping events: "+err.Error())
-		return nil, false
-	}
-	if hasOverlap {
-		em.showError("Cannot Add Event", "This event overlaps with an existing event")
+	// Check for overlaps before adding
+	overlapping, ok := em.checkOverlapPolicy(&event, "Cannot Add Event", "This event overlaps with an existing event")
+	if !ok {
 		return nil, false
 	}
 
@@ -104,6 +147,7 @@ func (em *EventManager) AddEvent(event calendar.Event) (*calendar.Event, bool) {
 		Type:       ActionAdd,
 		EventAfter: localEvent,
 	})
+	em.warnOverlap(localEvent, overlapping)
 
 	return localEvent, true
 }
@@ -155,14 +199,9 @@ func (em *EventManager) UpdateEvent(eventId int, newEvent *calendar.Event) bool
 	// Convert new event to UTC for database storage
 	utcNewEvent := em.toUTC(newEvent)
 
-	// Check for overlaps before updating (exclude current event, using UTC time)
-	hasOverlap, err := em.database.CheckEventOverlap(*utcNewEvent, eventId)
-	if err != nil {
-		em.showError("Database Error", "Failed to check for overlapping events: "+err.Error())
-		return false
-	}
-	if hasOverlap {
-		em.showError("Cannot Edit Event", "Updated event would overlap with an existing event")
+	// Check for overlaps before updating (exclude current event)
+	overlapping, ok := em.checkOverlapPolicy(newEvent, "Cannot Edit Event", "Updated event would overlap with an existing event", eventId)
+	if !ok {
 		return false
 	}
 
@@ -178,6 +217,7 @@ func (em *EventManager) UpdateEvent(eventId int, newEvent *calendar.Event) bool
 		EventBefore: localEventBefore,
 		EventAfter:  newEvent, // newEvent is already in local time from UI
 	})
+	em.warnOverlap(newEvent, overlapping)
 
 	return true
 }
@@ -240,9 +280,10 @@ func (em *EventManager) Undo() error {
 		return em.database.DeleteEventById(lastAction.EventAfter.Id)
 
 	case ActionDelete:
-		// Undo delete by re-adding the event (convert to UTC for storage)
+		// Undo delete by restoring the event (convert to UTC for storage)
 		utcEvent := em.toUTC(lastAction.EventBefore)
-		_, err := em.database.AddEvent(*utcEvent)
+		var err error
+		lastAction.EventBefore.Id, err = em.database.RestoreEvent(*utcEvent)
 		return err
 
 	case ActionEdit:
@@ -251,16 +292,20 @@ func (em *EventManager) Undo() error {
 		return em.database.UpdateEventById(lastAction.EventBefore.Id, utcEvent)
 
 	case ActionBulkDelete:
-		// Undo bulk delete by re-adding all the deleted events (convert to UTC for storage)
+		// Undo bulk delete by restoring all the deleted events (convert to UTC for storage)
 		for _, event := range lastAction.Events {
 			utcEvent := em.toUTC(event)
-			_, err := em.database.AddEvent(*utcEvent)
+			var err error
+			event.Id, err = em.database.RestoreEvent(*utcEvent)
 			if err != nil {
 				return err
 			}
 		}
 		return nil
 
+	case ActionBatch:
+		return em.undoChanges(lastAction.Changes)
+
 	default:
 		return errors.New("unknown action type")
 	}
@@ -287,9 +332,10 @@ func (em *EventManager) Redo() error {
 	// Re-apply the action
 	switch lastAction.Type {
 	case ActionAdd:
-		// Redo add by re-adding the event (convert to UTC for storage)
+		// Redo add by restoring the event (convert to UTC for storage)
 		utcEvent := em.toUTC(lastAction.EventAfter)
-		_, err := em.database.AddEvent(*utcEvent)
+		var err error
+		lastAction.EventAfter.Id, err = em.database.RestoreEvent(*utcEvent)
 		return err
 
 	case ActionDelete:
@@ -309,6 +355,9 @@ func (em *EventManager) Redo() error {
 		}
 		return nil
 
+	case ActionBatch:
+		return em.redoChanges(lastAction.Changes)
+
 	default:
 		return errors.New("unknown action type")
 	}
@@ -343,6 +392,8 @@ func (em *EventManager) GetUndoDescription() string {
 			return "Undo bulk delete: " + lastAction.Events[0].Name + " (" + strconv.Itoa(len(lastAction.Events)) + " events)"
 		}
 		return "Undo bulk delete"
+	case ActionBatch:
+		return "Undo " + lastAction.Description
 	default:
 		return "Undo last action"
 	}
@@ -367,11 +418,53 @@ func (em *EventManager) GetRedoDescription() string {
 			return "Redo bulk delete: " + lastAction.Events[0].Name + " (" + strconv.Itoa(len(lastAction.Events)) + " events)"
 		}
 		return "Redo bulk delete"
+	case ActionBatch:
+		return "Redo " + lastAction.Description
 	default:
 		return "Redo last action"
 	}
 }
 
+// undoChanges reverts batch changes in reverse order. Restored events keep their IDs where they
+// can; new IDs are recorded so that a redo finds them.
+func (em *EventManager) undoChanges(changes []EventChange) error {
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		var err error
+		switch {
+		case change.Before == nil:
+			err = em.database.DeleteEventById(change.After.Id)
+		case change.After == nil:
+			change.Before.Id, err = em.database.RestoreEvent(*em.toUTC(change.Before))
+		default:
+			err = em.database.UpdateEventById(change.Before.Id, em.toUTC(change.Before))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redoChanges re-applies batch changes in their original order
+func (em *EventManager) redoChanges(changes []EventChange) error {
+	for _, change := range changes {
+		var err error
+		switch {
+		case change.Before == nil:
+			change.After.Id, err = em.database.RestoreEvent(*em.toUTC(change.After))
+		case change.After == nil:
+			err = em.database.DeleteEventById(change.Before.Id)
+		default:
+			err = em.database.UpdateEventById(change.After.Id, em.toUTC(change.After))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // pushUndoAction adds an action to the undo stack
 func (em *EventManager) pushUndoAction(action UndoAction) {
 	em.undoStack = append(em.undoStack, action)
@@ -385,6 +478,73 @@ func (em *EventManager) pushUndoAction(action UndoAction) {
 	}
 }
 
+// FindOverlappingEvents returns the stored events that overlap the given (local time) event,
+// ignoring any event whose ID is in excludeIds. Adjacent events do not overlap.
+func (em *EventManager) FindOverlappingEvents(event *calendar.Event, excludeIds ...int) ([]*calendar.Event, error) {
+	start := event.Time
+	end := start.Add(time.Duration(event.DurationHour * float64(time.Hour)))
+
+	// Events are at most 24 hours long, so anything overlapping starts within a day before
+	candidates, err := em.database.GetEventsByDateRange(start.Add(-24*time.Hour), end)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlapping []*calendar.Event
+	for _, candidate := range candidates {
+		excluded := false
+		for _, id := range excludeIds {
+			if candidate.Id == id {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+
+		candidateEnd := candidate.Time.Add(time.Duration(candidate.DurationHour * float64(time.Hour)))
+		if start.Before(candidateEnd) && end.After(candidate.Time) {
+			overlapping = append(overlapping, candidate)
+		}
+	}
+
+	return overlapping, nil
+}
+
+// checkOverlapPolicy finds the events a (local time) event would overlap and applies the overlap
+// policy. It returns false, after reporting title and message, if the change must be refused.
+func (em *EventManager) checkOverlapPolicy(event *calendar.Event, title, message string, excludeIds ...int) ([]*calendar.Event, bool) {
+	if em.overlapPolicy == OverlapAllow {
+		return nil, true
+	}
+
+	overlapping, err := em.FindOverlappingEvents(event, excludeIds...)
+	if err != nil {
+		em.showError("Database Error", "Failed to check for overlapping events: "+err.Error())
+		return nil, false
+	}
+	if len(overlapping) > 0 && em.overlapPolicy == OverlapForbid {
+		em.showError(title, message)
+		return nil, false
+	}
+
+	return overlapping, true
+}
+
+// warnOverlap reports a saved event that overlaps others under the warn policy
+func (em *EventManager) warnOverlap(event *calendar.Event, overlapping []*calendar.Event) {
+	if len(overlapping) == 0 {
+		return
+	}
+
+	message := event.Name + " overlaps with " + overlapping[0].Name
+	if len(overlapping) > 1 {
+		message += " and " + strconv.Itoa(len(overlapping)-1) + " more"
+	}
+	em.showError("Overlapping Event", message)
+}
+
 // Pass-through methods for read operations (no undo needed)
 func (em *EventManager) GetEventById(id int) (*calendar.Event, error) {
 	return em.database.GetEventById(id)
@@ -402,6 +562,10 @@ func (em *EventManager) GetEventsByMonth(year int, month time.Month) ([]*calenda
 	return em.database.GetEventsByMonth(year, month)
 }
 
+func (em *EventManager) GetEventsByDateRange(startDate, endDate time.Time) ([]*calendar.Event, error) {
+	return em.database.GetEventsByDateRange(startDate, endDate)
+}
+
 func (em *EventManager) GetAllEvents() ([]*calendar.Event, error) {
 	return em.database.GetAllEvents()
 }
@@ -0,0 +1,78 @@
+package eventmanager
+
+import (
+	"fmt"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// ChangeKind names a change that can be repeated at another event or time
+type ChangeKind string
+
+const (
+	ChangePaste    ChangeKind = "paste"    // paste Count copies of Events at the cursor, EveryDays apart
+	ChangeDuration ChangeKind = "duration" // give the event at the cursor Duration
+	ChangeColor    ChangeKind = "color"    // give the event at the cursor the color named ColorName
+	ChangeDelete   ChangeKind = "delete"   // delete Count events from the cursor on
+)
+
+// RepeatableChange is a change made at the cursor, remembered so that it can be made again
+// elsewhere (vim's '.')
+type RepeatableChange struct {
+	Kind      ChangeKind
+	Events    []calendar.Event
+	Duration  float64
+	ColorName string
+	Count     int
+	EveryDays int
+}
+
+// RememberChange records the last repeatable change. Undo and redo leave it alone, as in vim.
+func (em *EventManager) RememberChange(change RepeatableChange) {
+	change.Events = append([]calendar.Event(nil), change.Events...)
+	if change.Count < 1 {
+		change.Count = 1
+	}
+	em.lastChange = &change
+}
+
+// LastChange returns the last repeatable change, if any
+func (em *EventManager) LastChange() (RepeatableChange, bool) {
+	if em.lastChange == nil {
+		return RepeatableChange{}, false
+	}
+	return *em.lastChange, true
+}
+
+// DeleteEvents deletes several events as a single undo step
+func (em *EventManager) DeleteEvents(eventIds []int) error {
+	if len(eventIds) == 1 {
+		return em.DeleteEvent(eventIds[0])
+	}
+
+	var changes []EventChange
+	for _, id := range eventIds {
+		before, err := em.database.GetEventById(id)
+		if err == nil && before == nil {
+			err = fmt.Errorf("event not found: cannot delete non-existent event %d", id)
+		}
+		if err == nil {
+			err = em.database.DeleteEventById(id)
+		}
+		if err != nil {
+			em.undoChanges(changes)
+			return err
+		}
+		changes = append(changes, EventChange{Before: em.toLocal(before)})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	em.pushUndoAction(UndoAction{
+		Type:        ActionBatch,
+		Description: fmt.Sprintf("delete %d events", len(changes)),
+		Changes:     changes,
+	})
+	return nil
+}
@@ -0,0 +1,209 @@
+// Package finder ranks events by how closely their names and locations fuzzy match what is
+// typed, listing each recurring series once with its number of occurrences.
+package finder
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// Scores of a fuzzy match. Every character of a term scores scoreMatch, more when it follows the
+// previous one or starts a word, less for the characters skipped before it.
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 16
+	bonusWordStart   = 12
+	penaltyGap       = 3
+	maxGapPenalty    = 8 // characters of a gap that count towards its penalty
+	// penaltyLocation ranks a match in the name above the same match in the location
+	penaltyLocation = 8
+)
+
+// Entry is an event, or a recurring series listed once
+type Entry struct {
+	// Event is the occurrence to jump to: a series' next one that has not ended, or its last
+	Event *calendar.Event
+	// Count is the number of occurrences, 1 for an event that does not repeat
+	Count int
+	// Upcoming reports whether Event had not ended when the entries were made
+	Upcoming bool
+
+	name     []rune // lowercase, for matching
+	location []rune
+}
+
+// Match is an entry that matches a query, with how well it matches
+type Match struct {
+	*Entry
+	Score int
+}
+
+// seriesKey tells the occurrences of a series, which are stored as separate events, apart from
+// other events of the same name
+type seriesKey struct {
+	name, location string
+	frequency      int
+	occurrences    int
+}
+
+// Entries lists events as entries, one for each recurring series. now decides which occurrence
+// of a series an entry jumps to.
+func Entries(events []*calendar.Event, now time.Time) []*Entry {
+	entries := make([]*Entry, 0, len(events))
+	series := make(map[seriesKey]*Entry)
+
+	for _, event := range events {
+		if event.Occurence > 1 {
+			key := seriesKey{event.Name, event.Location, event.FrequencyDay, event.Occurence}
+			if entry, ok := series[key]; ok {
+				entry.Count++
+				if next(event, entry.Event, now) {
+					entry.Event = event
+				}
+				continue
+			}
+			entry := newEntry(event)
+			series[key] = entry
+			entries = append(entries, entry)
+			continue
+		}
+		entries = append(entries, newEntry(event))
+	}
+
+	for _, entry := range entries {
+		entry.Upcoming = entry.Event.EndTime().After(now)
+	}
+	return entries
+}
+
+func newEntry(event *calendar.Event) *Entry {
+	return &Entry{
+		Event:    event,
+		Count:    1,
+		name:     []rune(strings.ToLower(event.Name)),
+		location: []rune(strings.ToLower(event.Location)),
+	}
+}
+
+// next reports whether occurrence is a better one of its series to jump to than current: the
+// earliest that has not ended, or failing that the latest
+func next(occurrence, current *calendar.Event, now time.Time) bool {
+	occurrenceUpcoming := occurrence.EndTime().After(now)
+	currentUpcoming := current.EndTime().After(now)
+	switch {
+	case occurrenceUpcoming && currentUpcoming:
+		return occurrence.Time.Before(current.Time)
+	case occurrenceUpcoming != currentUpcoming:
+		return occurrenceUpcoming
+	default:
+		return occurrence.Time.After(current.Time)
+	}
+}
+
+// Rank returns the entries matching every word of query in their name or location, best first,
+// and at most limit of them when limit is positive. Entries that score the same, and all of them
+// for an empty query, list upcoming events soonest first and then past events latest first.
+func Rank(entries []*Entry, query string, limit int) []Match {
+	var terms [][]rune
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		terms = append(terms, []rune(term))
+	}
+
+	matches := make([]Match, 0, len(entries))
+	for _, entry := range entries {
+		if score, ok := entry.score(terms); ok {
+			matches = append(matches, Match{entry, score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Upcoming != b.Upcoming {
+			return a.Upcoming
+		}
+		if a.Upcoming {
+			return a.Event.Time.Before(b.Event.Time)
+		}
+		return a.Event.Time.After(b.Event.Time)
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// score adds up how well each term matches the entry's name or location
+func (e *Entry) score(terms [][]rune) (int, bool) {
+	total := 0
+	for _, term := range terms {
+		best, found := Score(term, e.name)
+		if score, ok := Score(term, e.location); ok && (!found || score-penaltyLocation > best) {
+			best, found = score-penaltyLocation, true
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// Score reports whether the characters of term appear in text in order, both lowercase, and how
+// well they match: higher for characters that follow each other or start words
+func Score(term, text []rune) (int, bool) {
+	if len(term) == 0 {
+		return 0, true
+	}
+
+	best, found := 0, false
+	for start, ch := range text {
+		if ch != term[0] {
+			continue
+		}
+		score, ok := scoreFrom(term, text, start)
+		if !ok {
+			// Starting later finds no more of the term
+			break
+		}
+		if !found || score > best {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// scoreFrom matches term in text from start, taking each character at its first place
+func scoreFrom(term, text []rune, start int) (int, bool) {
+	score, matched, previous := 0, 0, -1
+	for i := start; i < len(text) && matched < len(term); i++ {
+		if text[i] != term[matched] {
+			continue
+		}
+		score += scoreMatch
+		if previous >= 0 {
+			if gap := i - previous - 1; gap == 0 {
+				score += bonusConsecutive
+			} else {
+				score -= penaltyGap * min(gap, maxGapPenalty)
+			}
+		}
+		if i == 0 || !isWordCharacter(text[i-1]) {
+			score += bonusWordStart
+		}
+		previous = i
+		matched++
+	}
+	return score, matched == len(term)
+}
+
+func isWordCharacter(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch)
+}
@@ -0,0 +1,287 @@
+// Package freetime finds free slots between events, e.g. "the next 90 minutes on a weekday
+// between 09:00 and 17:00".
+package freetime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// DefaultLimit is the number of slots returned when Query.Limit is 0
+const DefaultLimit = 20
+
+// slotStep aligns slot starts to the half-hour grid events are created on
+const slotStep = 30 * time.Minute
+
+// EventSource is implemented by the database and the EventManager
+type EventSource interface {
+	GetEventsByDateRange(startDate, endDate time.Time) ([]*calendar.Event, error)
+}
+
+// Weekdays is a set of days of the week; the zero value means every day
+type Weekdays uint8
+
+const (
+	AllDays  Weekdays = 0x7f
+	WorkWeek Weekdays = 1<<time.Monday | 1<<time.Tuesday | 1<<time.Wednesday | 1<<time.Thursday | 1<<time.Friday
+	Weekend  Weekdays = 1<<time.Saturday | 1<<time.Sunday
+)
+
+// Has reports whether the set contains day
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w == 0 || w&(1<<day) != 0
+}
+
+// String formats the set the way ParseWeekdays reads it, e.g. "mon-fri" or "mon,wed,fri"
+func (w Weekdays) String() string {
+	switch w {
+	case 0, AllDays:
+		return "all"
+	case WorkWeek:
+		return "mon-fri"
+	case Weekend:
+		return "sat,sun"
+	}
+
+	var days []string
+	for day := time.Monday; ; day = (day + 1) % 7 {
+		if w.Has(day) {
+			days = append(days, dayNames[day])
+		}
+		if day == time.Sunday {
+			break
+		}
+	}
+	return strings.Join(days, ",")
+}
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseWeekdays reads "all", "weekdays", "weekends" or a comma-separated list of days and
+// ranges such as "mon-fri" or "mon,wed,fri". An empty string means every day.
+func ParseWeekdays(value string) (Weekdays, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", "all", "any":
+		return AllDays, nil
+	case "weekdays", "workdays", "w":
+		return WorkWeek, nil
+	case "weekends", "weekend":
+		return Weekend, nil
+	}
+
+	var mask Weekdays
+	for _, part := range strings.Split(value, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		first, err := parseDay(bounds[0])
+		if err != nil {
+			return 0, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseDay(bounds[1]); err != nil {
+				return 0, err
+			}
+		}
+		// Ranges may wrap around the end of the week, e.g. "fri-mon"
+		for day := first; ; day = (day + 1) % 7 {
+			mask |= 1 << day
+			if day == last {
+				break
+			}
+		}
+	}
+	return mask, nil
+}
+
+// parseDay reads a day name or its first three letters
+func parseDay(value string) (time.Weekday, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 3 {
+		for i, name := range dayNames {
+			if strings.HasPrefix(value, name) {
+				return time.Weekday(i), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", value)
+}
+
+// ParseHours reads a working-hours window such as "09:00-17:00" as offsets from midnight.
+// "24:00" may be used as the end of the day.
+func ParseHours(value string) (start, end time.Duration, err error) {
+	bounds := strings.SplitN(strings.TrimSpace(value), "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("invalid hours %q (use HH:MM-HH:MM)", value)
+	}
+	if start, err = parseClock(bounds[0]); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(bounds[1]); err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("invalid hours %q: end must be after start", value)
+	}
+	return start, end, nil
+}
+
+// parseClock reads "HH:MM" or "HH" as an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	hourText, minuteText, found := strings.Cut(value, ":")
+	hour, err := strconv.Atoi(hourText)
+	minute := 0
+	if err == nil && found {
+		minute, err = strconv.Atoi(minuteText)
+	}
+	if err != nil || hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// ParseDuration reads "1.5h", "90m", "1h30m" or a bare number of hours
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if hours, err := strconv.ParseFloat(value, 64); err == nil {
+		value = strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// Query describes the free time to look for
+type Query struct {
+	Duration time.Duration // minimum length of a slot
+	From     time.Time     // start of the search window
+	To       time.Time     // end of the search window
+	DayStart time.Duration // working hours as offsets from midnight; DayEnd 0 means 24:00
+	DayEnd   time.Duration
+	Weekdays Weekdays // days to search; 0 means every day
+	Limit    int      // maximum number of slots; 0 means DefaultLimit
+}
+
+// Slot is a free period of at least the requested duration
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Hours returns the length of the slot in hours
+func (s Slot) Hours() float64 {
+	return s.End.Sub(s.Start).Hours()
+}
+
+// Find returns the free slots matching the query in chronological order. Events are read with a
+// single GetEventsByDateRange call covering the whole window.
+func Find(source EventSource, query Query) ([]Slot, error) {
+	if query.Duration <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+	if !query.To.After(query.From) {
+		return nil, errors.New("the end of the search window must be after its start")
+	}
+	dayEnd := query.DayEnd
+	if dayEnd == 0 {
+		dayEnd = 24 * time.Hour
+	}
+	if dayEnd <= query.DayStart {
+		return nil, errors.New("working hours must end after they start")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	// Events are at most 24 hours long, so anything overlapping the window starts within a day before
+	events, err := source.GetEventsByDateRange(query.From.Add(-24*time.Hour), query.To)
+	if err != nil {
+		return nil, err
+	}
+	busy := make([]Slot, 0, len(events))
+	for _, event := range events {
+		start := event.Time.In(time.Local)
+		busy = append(busy, Slot{Start: start, End: start.Add(time.Duration(event.DurationHour * float64(time.Hour)))})
+	}
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+
+	var slots []Slot
+	from := query.From.In(time.Local)
+	to := query.To.In(time.Local)
+	for day := midnight(from); day.Before(to) && len(slots) < limit; day = day.AddDate(0, 0, 1) {
+		if !query.Weekdays.Has(day.Weekday()) {
+			continue
+		}
+
+		windowStart := maxTime(addClock(day, query.DayStart), from)
+		windowEnd := minTime(addClock(day, dayEnd), to)
+		cursor := alignUp(windowStart, day)
+
+		for _, b := range busy {
+			if !b.End.After(cursor) {
+				continue
+			}
+			if !b.Start.Before(windowEnd) {
+				break
+			}
+			if b.Start.Sub(cursor) >= query.Duration {
+				slots = append(slots, Slot{Start: cursor, End: b.Start})
+			}
+			cursor = alignUp(b.End, day)
+		}
+		if windowEnd.Sub(cursor) >= query.Duration {
+			slots = append(slots, Slot{Start: cursor, End: windowEnd})
+		}
+	}
+
+	if len(slots) > limit {
+		slots = slots[:limit]
+	}
+	return slots, nil
+}
+
+// midnight returns the start of t's day
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// addClock returns the wall-clock time offset from midnight on day (correct across DST changes)
+func addClock(day time.Time, offset time.Duration) time.Time {
+	minutes := int(offset / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, day.Location())
+}
+
+// alignUp rounds t up to the next half hour of day's grid
+func alignUp(t, day time.Time) time.Time {
+	offset := t.Sub(day)
+	if rem := offset % slotStep; rem != 0 {
+		return t.Add(slotStep - rem)
+	}
+	return t
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
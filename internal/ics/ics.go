@@ -57,7 +57,7 @@ func (e *ICSExporter) formatEvent(event *calendar.Event) string {
 	builder.WriteString("BEGIN:VEVENT\r\n")
 	
 	// UID - Generate unique identifier using event ID
-	builder.WriteString(fmt.Sprintf("UID:chronos-event-%d@chronos.local\r\n", event.Id))
+	builder.WriteString(fmt.Sprintf("UID:%s\r\n", EventUID(event)))
 	
 	// DTSTAMP - Creation/modification timestamp (current time in UTC)
 	now := time.Now().UTC()
@@ -67,11 +67,11 @@ func (e *ICSExporter) formatEvent(event *calendar.Event) string {
 	builder.WriteString(fmt.Sprintf("DTCREATED:%s\r\n", now.Format("20060102T150405Z")))
 	
 	// DTSTART - Event start time (UTC format for compatibility)
-	builder.WriteString(fmt.Sprintf("DTSTART:%s\r\n", event.Time.Format("20060102T150405Z")))
+	builder.WriteString(fmt.Sprintf("DTSTART:%s\r\n", event.Time.UTC().Format("20060102T150405Z")))
 	
 	// DTEND - Event end time (UTC format for compatibility)
 	utcEndTime := event.Time.Add(time.Duration(event.DurationHour * float64(time.Hour)))
-	builder.WriteString(fmt.Sprintf("DTEND:%s\r\n", utcEndTime.Format("20060102T150405Z")))
+	builder.WriteString(fmt.Sprintf("DTEND:%s\r\n", utcEndTime.UTC().Format("20060102T150405Z")))
 	
 	// SUMMARY - Event title (required)
 	builder.WriteString(fmt.Sprintf("SUMMARY:%s\r\n", e.escapeText(event.Name)))
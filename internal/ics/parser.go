@@ -0,0 +1,286 @@
+package ics
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// ParsedEvent is a VEVENT read from an iCalendar document along with its UID
+type ParsedEvent struct {
+	UID   string
+	Event *calendar.Event
+}
+
+// ICSParser handles import of events from iCalendar format
+type ICSParser struct{}
+
+// NewICSParser creates a new ICS parser
+func NewICSParser() *ICSParser {
+	return &ICSParser{}
+}
+
+// ParseEvents parses every VEVENT component in an iCalendar document.
+// Times are returned in local time and durations are rounded to the nearest
+// half hour so they satisfy the database constraints.
+func (p *ICSParser) ParseEvents(data string) ([]ParsedEvent, error) {
+	var parsed []ParsedEvent
+	var current map[string]icsProperty
+	inEvent := false
+
+	for _, line := range p.unfoldLines(data) {
+		if line == "" {
+			continue
+		}
+
+		switch strings.ToUpper(line) {
+		case "BEGIN:VEVENT":
+			inEvent = true
+			current = make(map[string]icsProperty)
+			continue
+		case "END:VEVENT":
+			if !inEvent {
+				return nil, fmt.Errorf("unexpected END:VEVENT")
+			}
+			event, err := p.buildEvent(current)
+			if err != nil {
+				return nil, err
+			}
+			parsed = append(parsed, event)
+			inEvent = false
+			continue
+		}
+
+		if !inEvent {
+			continue
+		}
+
+		prop, ok := p.parseProperty(line)
+		if !ok {
+			continue
+		}
+		// Keep the first occurrence of each property
+		if _, exists := current[prop.name]; !exists {
+			current[prop.name] = prop
+		}
+	}
+
+	if inEvent {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+
+	return parsed, nil
+}
+
+// ParseEventID extracts the Chronos event ID from a UID generated by the exporter.
+// Returns false if the UID was not created by Chronos.
+func ParseEventID(uid string) (int, bool) {
+	if !strings.HasPrefix(uid, "chronos-event-") || !strings.HasSuffix(uid, "@chronos.local") {
+		return 0, false
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(uid, "chronos-event-"), "@chronos.local")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// EventUID returns the iCalendar UID used for an event
+func EventUID(event *calendar.Event) string {
+	return fmt.Sprintf("chronos-event-%d@chronos.local", event.Id)
+}
+
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldLines joins folded content lines according to RFC 5545
+func (p *ICSParser) unfoldLines(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	rawLines := strings.Split(data, "\n")
+
+	var lines []string
+	for _, raw := range rawLines {
+		if len(raw) > 0 && (raw[0] == ' ' || raw[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(raw, "\r"))
+	}
+
+	return lines
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func (p *ICSParser) parseProperty(line string) (icsProperty, bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return icsProperty{}, false
+	}
+
+	head := line[:colon]
+	prop := icsProperty{
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+
+	parts := strings.Split(head, ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if eq := strings.Index(param, "="); eq > 0 {
+			prop.params[strings.ToUpper(param[:eq])] = strings.Trim(param[eq+1:], `"`)
+		}
+	}
+
+	return prop, true
+}
+
+// buildEvent converts the collected properties of a VEVENT into an event
+func (p *ICSParser) buildEvent(props map[string]icsProperty) (ParsedEvent, error) {
+	summary, ok := props["SUMMARY"]
+	if !ok || strings.TrimSpace(summary.value) == "" {
+		return ParsedEvent{}, fmt.Errorf("VEVENT is missing SUMMARY")
+	}
+
+	dtstart, ok := props["DTSTART"]
+	if !ok {
+		return ParsedEvent{}, fmt.Errorf("VEVENT %q is missing DTSTART", summary.value)
+	}
+	start, err := p.parseDateTime(dtstart)
+	if err != nil {
+		return ParsedEvent{}, err
+	}
+
+	duration := time.Hour
+	if dtend, ok := props["DTEND"]; ok {
+		end, err := p.parseDateTime(dtend)
+		if err != nil {
+			return ParsedEvent{}, err
+		}
+		duration = end.Sub(start)
+	} else if dur, ok := props["DURATION"]; ok {
+		duration, err = p.parseDuration(dur.value)
+		if err != nil {
+			return ParsedEvent{}, err
+		}
+	}
+
+	// Round to half hours and clamp to what the database accepts
+	hours := math.Round(duration.Hours()*2) / 2
+	if hours < 0.5 {
+		hours = 0.5
+	}
+	if hours > 24 {
+		hours = 24
+	}
+
+	name := p.unescapeText(summary.value)
+	event := calendar.NewEventWithAutoColor(
+		name,
+		p.unescapeText(props["DESCRIPTION"].value),
+		p.unescapeText(props["LOCATION"].value),
+		start.In(time.Local),
+		hours,
+		0,
+		1,
+	)
+
+	return ParsedEvent{UID: props["UID"].value, Event: event}, nil
+}
+
+// parseDateTime parses DATE, DATE-TIME (UTC, floating or with TZID) values
+func (p *ICSParser) parseDateTime(prop icsProperty) (time.Time, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if prop.params["VALUE"] == "DATE" || len(value) == 8 {
+		return time.ParseInLocation("20060102", value, time.Local)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+
+	loc := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+// parseDuration parses the subset of RFC 5545 durations used by calendar clients (e.g. PT1H30M, P1D)
+func (p *ICSParser) parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			number = ""
+			switch {
+			case r == 'W':
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				total += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// unescapeText reverses escapeText according to RFC 5545
+func (p *ICSParser) unescapeText(text string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range text {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				sb.WriteRune('\n')
+			default:
+				sb.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+// Package jumplist remembers where the selection was before big jumps, so that they can be
+// walked back and forward as with vim's Ctrl-o and Ctrl-i.
+package jumplist
+
+import "time"
+
+// maxJumps caps the positions remembered; the oldest are forgotten first
+const maxJumps = 100
+
+// List is the jump list. The zero value is an empty list ready to use.
+type List struct {
+	positions []time.Time
+	// index is the position Back and Forward last returned; len(positions) when the list is
+	// not being walked
+	index int
+}
+
+// Push records the position a jump starts from. A position is remembered once, at its latest
+// jump, and pushing stops any walk through the list.
+func (l *List) Push(t time.Time) {
+	l.remove(t)
+	l.positions = append(l.positions, t)
+	if len(l.positions) > maxJumps {
+		l.positions = l.positions[len(l.positions)-maxJumps:]
+	}
+	l.index = len(l.positions)
+}
+
+// Back returns the position before the one at current, remembering current when the walk
+// starts so that Forward comes back to it
+func (l *List) Back(current time.Time) (time.Time, bool) {
+	if l.index >= len(l.positions) {
+		l.Push(current)
+		l.index = len(l.positions) - 1
+	}
+	if l.index == 0 {
+		return time.Time{}, false
+	}
+	l.index--
+	return l.positions[l.index], true
+}
+
+// Forward returns the position after the last one Back returned
+func (l *List) Forward() (time.Time, bool) {
+	if l.index+1 >= len(l.positions) {
+		return time.Time{}, false
+	}
+	l.index++
+	return l.positions[l.index], true
+}
+
+// Last returns the position the latest jump started from
+func (l *List) Last() (time.Time, bool) {
+	if len(l.positions) == 0 {
+		return time.Time{}, false
+	}
+	return l.positions[len(l.positions)-1], true
+}
+
+// Len returns the number of positions remembered
+func (l *List) Len() int {
+	return len(l.positions)
+}
+
+func (l *List) remove(t time.Time) {
+	for i, position := range l.positions {
+		if position.Equal(t) {
+			l.positions = append(l.positions[:i], l.positions[i+1:]...)
+			return
+		}
+	}
+}
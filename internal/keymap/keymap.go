@@ -0,0 +1,355 @@
+// Package keymap names the actions of the calendar views and maps keys to them, starting from
+// default keys that the keybindings config can remap or unbind.
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Scopes say where an action's keys work
+const (
+	// ScopeCalendar actions work in every calendar view
+	ScopeCalendar = "calendar"
+	// ScopeYear actions work in the year view only
+	ScopeYear = "year"
+	// ScopeAgenda actions work in the agenda only
+	ScopeAgenda = "agenda"
+	// ScopeVisual actions work in visual mode, where their keys take precedence over the view's
+	ScopeVisual = "visual"
+	// ScopeGrab actions work in grab mode, where no other keys do
+	ScopeGrab = "grab"
+)
+
+// Action is a named command that keys can be bound to
+type Action struct {
+	Name        string
+	Category    string
+	Description string
+	Scope       string
+	Defaults    []string
+}
+
+// Actions lists every action in the order the help shows them
+var Actions = []Action{
+	{"app.quit", "Views", "Exit chronos", ScopeCalendar, []string{"q"}},
+	{"app.help", "Views", "Show/hide help", ScopeCalendar, []string{"?"}},
+	{"app.command", "Views", "Command line (:help lists commands)", ScopeCalendar, []string{":"}},
+	{"view.cycle", "Views", "Cycle views (Week, Day, Days, Month, Weeks, Year, Agenda)", ScopeCalendar, []string{"v"}},
+	{"view.more", "Views", "More days or weeks shown", ScopeCalendar, []string{"+"}},
+	{"view.fewer", "Views", "Fewer days or weeks shown", ScopeCalendar, []string{"-"}},
+	{"agenda.toggle_empty_days", "Views", "Agenda: hide/show empty days", ScopeAgenda, []string{"z"}},
+	{"detail.toggle", "Views", "Show/hide event details pane", ScopeCalendar, []string{"i"}},
+	{"detail.move", "Views", "Move details pane right/bottom", ScopeCalendar, []string{"I"}},
+	{"detail.scroll_down", "Views", "Scroll details pane down", ScopeCalendar, []string{"ctrl+d"}},
+	{"detail.scroll_up", "Views", "Scroll details pane up", ScopeCalendar, []string{"ctrl+u"}},
+	{"year.next_month", "Views", "Year view: next month", ScopeYear, []string{"J"}},
+	{"year.prev_month", "Views", "Year view: previous month", ScopeYear, []string{"K"}},
+	{"year.open_week", "Views", "Year view: open week view", ScopeYear, []string{"enter"}},
+	{"year.open_month", "Views", "Year view: open month view", ScopeYear, []string{"space"}},
+
+	{"nav.prev_day", "Navigation", "Previous day", ScopeCalendar, []string{"h", "left"}},
+	{"nav.next_day", "Navigation", "Next day", ScopeCalendar, []string{"l", "right"}},
+	{"nav.prev_week", "Navigation", "Previous week", ScopeCalendar, []string{"H"}},
+	{"nav.next_week", "Navigation", "Next week", ScopeCalendar, []string{"L"}},
+	{"nav.next_month", "Navigation", "Next month", ScopeCalendar, []string{"m"}},
+	{"nav.prev_month", "Navigation", "Previous month", ScopeCalendar, []string{"M"}},
+	{"nav.next_time", "Navigation", "Move time cursor down", ScopeCalendar, []string{"j", "down"}},
+	{"nav.prev_time", "Navigation", "Move time cursor up", ScopeCalendar, []string{"k", "up"}},
+	{"nav.today", "Navigation", "Go to today", ScopeCalendar, []string{"t"}},
+	{"nav.goto_date", "Navigation", "Jump to specific date", ScopeCalendar, []string{"D"}},
+	{"nav.goto_time", "Navigation", "Jump to time in day", ScopeCalendar, []string{"T"}},
+	{"nav.next_event", "Navigation", "Jump to next event", ScopeCalendar, []string{"w"}},
+	{"nav.prev_event", "Navigation", "Jump to previous event", ScopeCalendar, []string{"b"}},
+	{"nav.event_end", "Navigation", "Jump to end of event", ScopeCalendar, []string{"e"}},
+	{"nav.day_start", "Navigation", "Start of day (00:00)", ScopeCalendar, []string{"g"}},
+	{"nav.day_end", "Navigation", "End of day (23:30)", ScopeCalendar, []string{"G"}},
+
+	{"event.add", "Event Management", "Add new event", ScopeCalendar, []string{"a"}},
+	{"event.quick_add", "Event Management", "Quick add (natural language)", ScopeCalendar, []string{"A"}},
+	{"event.find_free_slot", "Event Management", "Find a free slot and add there", ScopeCalendar, []string{"f"}},
+	{"event.edit", "Event Management", "Change event", ScopeCalendar, []string{"c"}},
+	{"event.edit_description", "Event Management", "Edit description in $EDITOR", ScopeCalendar, []string{"E"}},
+	{"event.edit_document", "Event Management", "Edit whole event in $EDITOR", ScopeCalendar, []string{"ctrl+e"}},
+	{"event.color", "Event Management", "Color picker", ScopeCalendar, []string{"C"}},
+	{"event.duration", "Event Management", "Change duration", ScopeCalendar, []string{"d"}},
+	{"event.yank", "Event Management", "Copy event", ScopeCalendar, []string{"y"}},
+	{"event.paste", "Event Management", "Paste event (a count pastes on that many days)", ScopeCalendar, []string{"p"}},
+	{"event.paste_weekly", "Event Management", "Paste event in following weeks (a count for how many)", ScopeCalendar, []string{"P"}},
+	{"register.select", "Event Management", "Use register a-z, or 0-9 from the yank history, for the next yank, paste or delete (then a letter or digit)", ScopeCalendar, []string{"\""}},
+	{"event.delete", "Event Management", "Delete event", ScopeCalendar, []string{"x"}},
+	{"event.delete_all", "Event Management", "Delete all events with same name", ScopeCalendar, []string{"B"}},
+	{"event.repeat", "Event Management", "Repeat last paste, duration, color or delete", ScopeCalendar, []string{"."}},
+
+	{"mark.set", "Marks", "Set mark (then a letter a-z)", ScopeCalendar, []string{"\\"}},
+	{"mark.jump", "Marks", "Jump to mark (then a-z, or ' for the last jump)", ScopeCalendar, []string{"'"}},
+	{"mark.list", "Marks", "List marks", ScopeCalendar, []string{"`"}},
+	{"jump.back", "Marks", "Back to the position before a jump", ScopeCalendar, []string{"ctrl+o"}},
+	{"jump.forward", "Marks", "Forward again after jumping back", ScopeCalendar, []string{"tab"}},
+
+	{"visual.start", "Visual Mode", "Select time slots and days, or agenda rows", ScopeCalendar, []string{"V"}},
+	{"visual.exit", "Visual Mode", "Visual: leave visual mode", ScopeVisual, []string{"V", "esc"}},
+	{"visual.delete", "Visual Mode", "Visual: delete selected events", ScopeVisual, []string{"d", "x"}},
+	{"visual.yank", "Visual Mode", "Visual: copy selected events", ScopeVisual, []string{"y"}},
+	{"visual.color", "Visual Mode", "Visual: recolor selected events", ScopeVisual, []string{"C"}},
+	{"visual.shift_later", "Visual Mode", "Visual: move selected events a day later", ScopeVisual, []string{">"}},
+	{"visual.shift_earlier", "Visual Mode", "Visual: move selected events a day earlier", ScopeVisual, []string{"<"}},
+	{"visual.shift_down", "Visual Mode", "Visual: move selected events 30 minutes later", ScopeVisual, []string{"J"}},
+	{"visual.shift_up", "Visual Mode", "Visual: move selected events 30 minutes earlier", ScopeVisual, []string{"K"}},
+	{"visual.duplicate", "Visual Mode", "Visual: duplicate selected events after the selection", ScopeVisual, []string{"D"}},
+
+	{"grab.start", "Grab Mode", "Grab the selected event to move and resize it", ScopeCalendar, []string{"R"}},
+	{"grab.commit", "Grab Mode", "Grab: save the new time and length", ScopeGrab, []string{"enter"}},
+	{"grab.cancel", "Grab Mode", "Grab: put the event back", ScopeGrab, []string{"esc"}},
+	{"grab.down", "Grab Mode", "Grab: move 30 minutes later", ScopeGrab, []string{"j", "down"}},
+	{"grab.up", "Grab Mode", "Grab: move 30 minutes earlier", ScopeGrab, []string{"k", "up"}},
+	{"grab.next_day", "Grab Mode", "Grab: move a day later", ScopeGrab, []string{"l", "right"}},
+	{"grab.prev_day", "Grab Mode", "Grab: move a day earlier", ScopeGrab, []string{"h", "left"}},
+	{"grab.grow", "Grab Mode", "Grab: make 30 minutes longer", ScopeGrab, []string{"J"}},
+	{"grab.shrink", "Grab Mode", "Grab: make 30 minutes shorter", ScopeGrab, []string{"K"}},
+
+	{"search.start", "Search", "Search events (name/desc/loc)", ScopeCalendar, []string{"/"}},
+	{"search.next", "Search", "Next search match", ScopeCalendar, []string{"n"}},
+	{"search.prev", "Search", "Previous search match", ScopeCalendar, []string{"N"}},
+	{"search.clear", "Search", "Clear search", ScopeCalendar, []string{"esc"}},
+	{"search.find", "Search", "Find events by name or location as you type", ScopeCalendar, []string{"ctrl+p"}},
+	{"filter.toggle", "Search", "Turn the last filter used on or off", ScopeCalendar, []string{"F"}},
+
+	{"history.undo", "Undo Buffer", "Undo last action", ScopeCalendar, []string{"u"}},
+	{"history.redo", "Undo Buffer", "Redo last undone action", ScopeCalendar, []string{"r"}},
+}
+
+// repeated lists the actions that a count typed before their key runs that many times, e.g. 5j
+var repeated = map[string]bool{
+	"view.more":          true,
+	"view.fewer":         true,
+	"detail.scroll_down": true,
+	"detail.scroll_up":   true,
+	"year.next_month":    true,
+	"year.prev_month":    true,
+	"nav.prev_day":       true,
+	"nav.next_day":       true,
+	"nav.prev_week":      true,
+	"nav.next_week":      true,
+	"nav.next_month":     true,
+	"nav.prev_month":     true,
+	"nav.next_time":      true,
+	"nav.prev_time":      true,
+	"nav.next_event":     true,
+	"nav.prev_event":     true,
+	"jump.back":          true,
+	"grab.down":          true,
+	"grab.up":            true,
+	"grab.next_day":      true,
+	"grab.prev_day":      true,
+	"grab.grow":          true,
+	"grab.shrink":        true,
+	"jump.forward":       true,
+	"search.next":        true,
+	"search.prev":        true,
+	"history.undo":       true,
+	"history.redo":       true,
+}
+
+// Repeats reports whether a count before the action's key runs it that many times
+func Repeats(action string) bool {
+	return repeated[action]
+}
+
+// FindAction returns the registered action with the given name
+func FindAction(name string) (Action, bool) {
+	for _, action := range Actions {
+		if action.Name == name {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// Keymap holds the keys bound to every action
+type Keymap struct {
+	keys map[string][]Key
+}
+
+// Default returns the keymap with every action on its default keys
+func Default() *Keymap {
+	km, err := New(nil)
+	if err != nil {
+		panic(err)
+	}
+	return km
+}
+
+// New builds a keymap from the default keys, replacing those of every action named in overrides.
+// An action given no keys is unbound. Unknown actions, invalid keys and keys bound to two actions
+// that can be used in the same view are errors.
+func New(overrides map[string][]string) (*Keymap, error) {
+	km := &Keymap{keys: make(map[string][]Key)}
+
+	// Report unknown actions in a stable order
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := FindAction(name); !ok {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+	}
+
+	for _, action := range Actions {
+		specs, ok := overrides[action.Name]
+		if !ok {
+			specs = action.Defaults
+		}
+		for _, spec := range specs {
+			if strings.TrimSpace(spec) == "" {
+				continue
+			}
+			key, err := ParseKey(spec)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", action.Name, err)
+			}
+			if !containsKey(km.keys[action.Name], key) {
+				km.keys[action.Name] = append(km.keys[action.Name], key)
+			}
+		}
+	}
+
+	if err := km.validate(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// validate checks that no key is bound to two actions that share a view
+func (km *Keymap) validate() error {
+	for i, action := range Actions {
+		for _, other := range Actions[i+1:] {
+			if !scopesOverlap(action.Scope, other.Scope) {
+				continue
+			}
+			for _, key := range km.keys[action.Name] {
+				if containsKey(km.keys[other.Name], key) {
+					return fmt.Errorf("key %s is bound to both %s and %s", key, action.Name, other.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// scopesOverlap reports whether two scopes share a view. Visual and grab mode keys override the
+// others.
+func scopesOverlap(a, b string) bool {
+	if isMode(a) || isMode(b) {
+		return a == b
+	}
+	return a == b || a == ScopeCalendar || b == ScopeCalendar
+}
+
+// isMode reports whether a scope is a mode whose keys override the view's
+func isMode(scope string) bool {
+	return scope == ScopeVisual || scope == ScopeGrab
+}
+
+func containsKey(keys []Key, key Key) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys bound to an action
+func (km *Keymap) Keys(action string) []Key {
+	return km.keys[action]
+}
+
+// Lookup returns the action a key runs in a view with the given scope
+func (km *Keymap) Lookup(scope string, key Key) (string, bool) {
+	for _, action := range Actions {
+		if action.Scope != ScopeCalendar && action.Scope != scope {
+			continue
+		}
+		if containsKey(km.keys[action.Name], key) {
+			return action.Name, true
+		}
+	}
+	return "", false
+}
+
+// LookupMode returns the action a key runs in visual or grab mode, if any
+func (km *Keymap) LookupMode(mode string, key Key) (string, bool) {
+	for _, action := range Actions {
+		if action.Scope == mode && containsKey(km.keys[action.Name], key) {
+			return action.Name, true
+		}
+	}
+	return "", false
+}
+
+// ScopeKeys returns every key bound in a view with the given scope, without Alt
+func (km *Keymap) ScopeKeys(scope string) []Key {
+	var keys []Key
+	for _, action := range Actions {
+		if action.Scope != ScopeCalendar && action.Scope != scope {
+			continue
+		}
+		for _, key := range km.keys[action.Name] {
+			if !containsKey(keys, key.Plain()) {
+				keys = append(keys, key.Plain())
+			}
+		}
+	}
+	return keys
+}
+
+// HasAltBindings reports whether any action is bound to an Alt key
+func (km *Keymap) HasAltBindings() bool {
+	for _, keys := range km.keys {
+		for _, key := range keys {
+			if key.Mod != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HelpEntry is a line of the help: an action's keys and what it does
+type HelpEntry struct {
+	Keys        string
+	Description string
+}
+
+// HelpSection is a category of actions in the help
+type HelpSection struct {
+	Category string
+	Entries  []HelpEntry
+}
+
+// Help lists the bound actions by category in registry order
+func (km *Keymap) Help() []HelpSection {
+	var sections []HelpSection
+	for _, action := range Actions {
+		keys := km.keys[action.Name]
+		if len(keys) == 0 {
+			continue
+		}
+		if len(sections) == 0 || sections[len(sections)-1].Category != action.Category {
+			sections = append(sections, HelpSection{Category: action.Category})
+		}
+
+		names := make([]string, len(keys))
+		for i, key := range keys {
+			names[i] = key.String()
+		}
+		section := &sections[len(sections)-1]
+		section.Entries = append(section.Entries, HelpEntry{Keys: strings.Join(names, " "), Description: action.Description})
+	}
+	return sections
+}
@@ -0,0 +1,165 @@
+package keymap
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jroimartin/gocui"
+)
+
+// Key is a key press that can be bound to an action: a character or a special key, optionally
+// with Alt held
+type Key struct {
+	Ch  rune
+	Key gocui.Key
+	Mod gocui.Modifier
+}
+
+// namedKeys maps the names accepted in key specs to special keys
+var namedKeys = map[string]gocui.Key{
+	"esc":       gocui.KeyEsc,
+	"escape":    gocui.KeyEsc,
+	"enter":     gocui.KeyEnter,
+	"return":    gocui.KeyEnter,
+	"space":     gocui.KeySpace,
+	"tab":       gocui.KeyTab,
+	"backspace": gocui.KeyBackspace2,
+	"delete":    gocui.KeyDelete,
+	"insert":    gocui.KeyInsert,
+	"home":      gocui.KeyHome,
+	"end":       gocui.KeyEnd,
+	"pgup":      gocui.KeyPgup,
+	"pgdn":      gocui.KeyPgdn,
+	"left":      gocui.KeyArrowLeft,
+	"right":     gocui.KeyArrowRight,
+	"up":        gocui.KeyArrowUp,
+	"down":      gocui.KeyArrowDown,
+	"f1":        gocui.KeyF1,
+	"f2":        gocui.KeyF2,
+	"f3":        gocui.KeyF3,
+	"f4":        gocui.KeyF4,
+	"f5":        gocui.KeyF5,
+	"f6":        gocui.KeyF6,
+	"f7":        gocui.KeyF7,
+	"f8":        gocui.KeyF8,
+	"f9":        gocui.KeyF9,
+	"f10":       gocui.KeyF10,
+	"f11":       gocui.KeyF11,
+	"f12":       gocui.KeyF12,
+}
+
+// keyNames are the names keys are shown with in the help
+var keyNames = map[gocui.Key]string{
+	gocui.KeyEsc:        "Esc",
+	gocui.KeyEnter:      "Enter",
+	gocui.KeySpace:      "Space",
+	gocui.KeyTab:        "Tab",
+	gocui.KeyBackspace2: "Backspace",
+	gocui.KeyDelete:     "Delete",
+	gocui.KeyInsert:     "Insert",
+	gocui.KeyHome:       "Home",
+	gocui.KeyEnd:        "End",
+	gocui.KeyPgup:       "PgUp",
+	gocui.KeyPgdn:       "PgDn",
+	gocui.KeyArrowLeft:  "←",
+	gocui.KeyArrowRight: "→",
+	gocui.KeyArrowUp:    "↑",
+	gocui.KeyArrowDown:  "↓",
+	gocui.KeyF1:         "F1",
+	gocui.KeyF2:         "F2",
+	gocui.KeyF3:         "F3",
+	gocui.KeyF4:         "F4",
+	gocui.KeyF5:         "F5",
+	gocui.KeyF6:         "F6",
+	gocui.KeyF7:         "F7",
+	gocui.KeyF8:         "F8",
+	gocui.KeyF9:         "F9",
+	gocui.KeyF10:        "F10",
+	gocui.KeyF11:        "F11",
+	gocui.KeyF12:        "F12",
+}
+
+// ParseKey reads a key spec: a single character such as "a" or "?", a key name such as "enter"
+// or "left", or either prefixed with "ctrl+" or "alt+" (e.g. "ctrl+e", "alt+j"). Ctrl works with
+// letters only, as terminals send nothing else for it.
+func ParseKey(spec string) (Key, error) {
+	rest := spec
+	var ctrl, alt bool
+	for {
+		lower := strings.ToLower(rest)
+		if strings.HasPrefix(lower, "ctrl+") && len(rest) > len("ctrl+") {
+			ctrl = true
+			rest = rest[len("ctrl+"):]
+		} else if strings.HasPrefix(lower, "alt+") && len(rest) > len("alt+") {
+			alt = true
+			rest = rest[len("alt+"):]
+		} else {
+			break
+		}
+	}
+
+	var key Key
+	if alt {
+		key.Mod = gocui.ModAlt
+	}
+
+	if utf8.RuneCountInString(rest) == 1 {
+		ch, _ := utf8.DecodeRuneInString(rest)
+		switch {
+		case ctrl:
+			lower := ch | 0x20
+			if lower < 'a' || lower > 'z' {
+				return Key{}, fmt.Errorf("invalid key %q: ctrl only combines with letters", spec)
+			}
+			key.Key = gocui.KeyCtrlA + gocui.Key(lower-'a')
+		case ch == ' ':
+			key.Key = gocui.KeySpace
+		default:
+			key.Ch = ch
+		}
+		return key, nil
+	}
+
+	named, ok := namedKeys[strings.ToLower(rest)]
+	if !ok {
+		return Key{}, fmt.Errorf("invalid key %q", spec)
+	}
+	if ctrl {
+		return Key{}, fmt.Errorf("invalid key %q: ctrl only combines with letters", spec)
+	}
+	key.Key = named
+	return key, nil
+}
+
+// Binding returns the key as gocui takes it in SetKeybinding, without the Alt modifier
+func (k Key) Binding() interface{} {
+	if k.Ch != 0 {
+		return k.Ch
+	}
+	return k.Key
+}
+
+// Plain returns the key without Alt
+func (k Key) Plain() Key {
+	return Key{Ch: k.Ch, Key: k.Key}
+}
+
+// String shows the key as the help lists it, e.g. "a", "Ctrl-e", "Alt-j" or "Enter"
+func (k Key) String() string {
+	var name string
+	switch {
+	case k.Ch != 0:
+		name = string(k.Ch)
+	case keyNames[k.Key] != "":
+		name = keyNames[k.Key]
+	case k.Key >= gocui.KeyCtrlA && k.Key <= gocui.KeyCtrlZ:
+		name = "Ctrl-" + string(rune('a'+k.Key-gocui.KeyCtrlA))
+	default:
+		name = fmt.Sprintf("key %d", k.Key)
+	}
+	if k.Mod == gocui.ModAlt {
+		return "Alt-" + name
+	}
+	return name
+}
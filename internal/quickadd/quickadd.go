@@ -0,0 +1,752 @@
+// Package quickadd parses natural-language event descriptions such as
+// "Lunch with Sam tomorrow 12:30 for 90m at Cafe Roma" into events.
+package quickadd
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/jroimartin/gocui"
+)
+
+// DefaultOccurrences is used for recurring phrases without an explicit count
+const DefaultOccurrences = 10
+
+// Result holds the fields parsed from a quick-add phrase
+type Result struct {
+	Name          string
+	Location      string
+	Start         time.Time
+	DurationHours float64
+	FrequencyDays int // 0 for a single event, -1 for every weekday
+	Occurrences   int
+	Color         gocui.Attribute // 0 means generate from the name
+
+	HasDate bool
+	HasTime bool
+}
+
+// Parser turns quick-add phrases into Results
+type Parser struct {
+	// Now is the reference point for relative dates like "tomorrow" and "next fri"
+	Now time.Time
+	// DefaultStart is used when the phrase has no date or time; its clock time is
+	// also used when only a date is given
+	DefaultStart time.Time
+	// DefaultDuration is used when the phrase has no duration
+	DefaultDuration float64
+}
+
+// NewParser creates a parser relative to now with the given defaults
+func NewParser(now, defaultStart time.Time, defaultDuration float64) *Parser {
+	return &Parser{Now: now, DefaultStart: defaultStart, DefaultDuration: defaultDuration}
+}
+
+// Event converts the result into a calendar event in local time
+func (r *Result) Event() *calendar.Event {
+	color := r.Color
+	if color == 0 {
+		color = calendar.GenerateColorFromName(r.Name)
+	}
+	return calendar.NewEvent(r.Name, "", r.Location, r.Start, r.DurationHours, r.FrequencyDays, r.Occurrences, color)
+}
+
+// Describe returns a one-line human readable summary for confirmation prompts
+func (r *Result) Describe() string {
+	end := r.Start.Add(time.Duration(r.DurationHours * float64(time.Hour)))
+	desc := fmt.Sprintf("%s, %s %s-%s", r.Name, r.Start.Format("Mon Jan 2 2006"), r.Start.Format("15:04"), end.Format("15:04"))
+	if r.Location != "" {
+		desc += " at " + r.Location
+	}
+	switch {
+	case r.FrequencyDays == -1:
+		desc += fmt.Sprintf(", every weekday x%d", r.Occurrences)
+	case r.FrequencyDays == 1 && r.Occurrences > 1:
+		desc += fmt.Sprintf(", every day x%d", r.Occurrences)
+	case r.FrequencyDays > 1 && r.Occurrences > 1:
+		desc += fmt.Sprintf(", every %d days x%d", r.FrequencyDays, r.Occurrences)
+	}
+	return desc
+}
+
+// parseState tracks what has been recognised so far
+type parseState struct {
+	p      *Parser
+	tokens []string // original tokens
+	lower  []string // lowercased tokens with trailing punctuation removed
+
+	nameParts     []string
+	locationParts []string
+	inLocation    bool
+
+	date        time.Time
+	hasDate     bool
+	weekdayDate bool // date is the next day of "every <weekday>", not one given
+	clock       time.Duration // time of day
+	hasTime     bool
+	duration    float64
+	hasDuration bool
+	frequency   int
+	recurring   bool
+	occurrences int
+	color       gocui.Attribute
+}
+
+// Parse parses a quick-add phrase. Unrecognised words become the event name,
+// and words following "at" or "@" (that are not a time) become the location.
+//
+// Recognised forms include:
+//   - dates: today, tomorrow, yesterday, mon..sun, next fri, this fri, in 3 days,
+//     next week, 2025-07-07, 20250707, jul 7, 7th july, on <date>
+//   - times: 9am, 9:30pm, 14:00, noon, midnight, at 9, 9am-10:30am, from 9 to 11
+//   - durations: for 90m, for 1.5h, for 1h30m, for 2 hours, for an hour, for half an hour
+//   - recurrence: daily, weekly, every day, every weekday, every 2 days, every week,
+//     every other week, every monday; counts as x10, 10 times
+//   - colours: #red, #blue, ...
+//
+// A bare weekday means the next such day including today; "next fri" skips today.
+// A time without a date that has already passed today is moved to tomorrow, for recurring
+// phrases too, and "every <weekday>" at a time already passed today starts the next week.
+func (p *Parser) Parse(input string) (*Result, error) {
+	tokens := strings.Fields(input)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("nothing to add")
+	}
+
+	s := &parseState{p: p, tokens: tokens, duration: p.DefaultDuration}
+	for _, token := range tokens {
+		s.lower = append(s.lower, strings.TrimRight(strings.ToLower(token), ",.;"))
+	}
+
+	for i := 0; i < len(tokens); {
+		n, err := s.match(i)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			s.inLocation = false
+			i += n
+			continue
+		}
+
+		// "at"/"@" followed by something that is not a time starts the location
+		if s.lower[i] == "at" || s.lower[i] == "@" {
+			s.inLocation = true
+			i++
+			continue
+		}
+		if strings.HasPrefix(s.tokens[i], "@") && len(s.tokens[i]) > 1 {
+			s.inLocation = true
+			s.locationParts = append(s.locationParts, s.tokens[i][1:])
+			i++
+			continue
+		}
+
+		if s.inLocation {
+			s.locationParts = append(s.locationParts, s.tokens[i])
+		} else {
+			s.nameParts = append(s.nameParts, s.tokens[i])
+		}
+		i++
+	}
+
+	return s.result()
+}
+
+// match tries every recogniser at position i and returns how many tokens were consumed
+func (s *parseState) match(i int) (int, error) {
+	matchers := []func(int) (int, error){
+		s.matchRecurrence,
+		s.matchCount,
+		s.matchDuration,
+		s.matchTimeRange,
+		s.matchTime,
+		s.matchDate,
+		s.matchColor,
+	}
+	for _, m := range matchers {
+		n, err := m(i)
+		if err != nil || n > 0 {
+			return n, err
+		}
+	}
+	return 0, nil
+}
+
+// word returns the lowercased token at i, or "" past the end
+func (s *parseState) word(i int) string {
+	if i < 0 || i >= len(s.lower) {
+		return ""
+	}
+	return s.lower[i]
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "weds": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+// parseCount parses a positive integer written as digits or a small number word
+func parseCount(word string) (int, bool) {
+	if n, ok := numberWords[word]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(word)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// matchRecurrence recognises "daily", "weekly", "weekdays", "every ..." phrases
+func (s *parseState) matchRecurrence(i int) (int, error) {
+	switch s.word(i) {
+	case "daily":
+		return s.setRecurrence(1), nil
+	case "weekly":
+		return s.setRecurrence(7), nil
+	case "fortnightly":
+		return s.setRecurrence(14), nil
+	case "weekdays":
+		return s.setRecurrence(-1), nil
+	case "every":
+	default:
+		return 0, nil
+	}
+
+	next := s.word(i + 1)
+	switch next {
+	case "day":
+		return 1 + s.setRecurrence(1), nil
+	case "weekday", "weekdays":
+		return 1 + s.setRecurrence(-1), nil
+	case "week":
+		return 1 + s.setRecurrence(7), nil
+	case "fortnight":
+		return 1 + s.setRecurrence(14), nil
+	case "other":
+		switch s.word(i + 2) {
+		case "day":
+			return 2 + s.setRecurrence(2), nil
+		case "week":
+			return 2 + s.setRecurrence(14), nil
+		}
+		return 0, fmt.Errorf("expected \"day\" or \"week\" after \"every other\"")
+	}
+
+	if weekday, ok := weekdays[next]; ok {
+		s.setRecurrence(7)
+		if !s.hasDate {
+			s.date = s.nextWeekday(weekday, true)
+			s.hasDate = true
+			s.weekdayDate = true
+		}
+		return 2, nil
+	}
+
+	if n, ok := parseCount(next); ok {
+		switch s.word(i + 2) {
+		case "day", "days":
+			return 2 + s.setRecurrence(n), nil
+		case "week", "weeks":
+			return 2 + s.setRecurrence(7*n), nil
+		}
+	}
+
+	return 0, fmt.Errorf("could not understand \"every %s\"", next)
+}
+
+func (s *parseState) setRecurrence(frequency int) int {
+	s.frequency = frequency
+	s.recurring = true
+	return 1
+}
+
+var countPattern = regexp.MustCompile(`^x(\d+)$`)
+
+// matchCount recognises "x10", "10x", "10 times" and "10 occurrences"
+func (s *parseState) matchCount(i int) (int, error) {
+	if m := countPattern.FindStringSubmatch(s.word(i)); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return 1, s.setCount(n)
+	}
+	if strings.HasSuffix(s.word(i), "x") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s.word(i), "x")); err == nil {
+			return 1, s.setCount(n)
+		}
+	}
+	if n, ok := parseCount(s.word(i)); ok {
+		switch s.word(i + 1) {
+		case "times", "occurrences":
+			return 2, s.setCount(n)
+		}
+	}
+	return 0, nil
+}
+
+func (s *parseState) setCount(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("the number of occurrences must be positive")
+	}
+	s.occurrences = n
+	return nil
+}
+
+var (
+	compactDurationPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)(h|hr|hrs|hour|hours|m|min|mins|minutes)$`)
+	mixedDurationPattern   = regexp.MustCompile(`^(\d+)h(\d+)m?$`)
+)
+
+// parseDurationAt parses a duration starting at token i, returning hours and tokens consumed
+func (s *parseState) parseDurationAt(i int) (float64, int) {
+	word := s.word(i)
+
+	if m := mixedDurationPattern.FindStringSubmatch(word); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		return float64(h) + float64(min)/60, 1
+	}
+	if m := compactDurationPattern.FindStringSubmatch(word); m != nil {
+		value, _ := strconv.ParseFloat(m[1], 64)
+		return toHours(value, m[2]), 1
+	}
+
+	switch word {
+	case "an", "a", "one":
+		if unit := s.word(i + 1); unit == "hour" {
+			return 1, 2
+		}
+	case "half":
+		if s.word(i+1) == "an" && s.word(i+2) == "hour" {
+			return 0.5, 3
+		}
+		if s.word(i+1) == "hour" {
+			return 0.5, 2
+		}
+	}
+
+	value, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		if n, ok := numberWords[word]; ok {
+			value = float64(n)
+		} else {
+			return 0, 0
+		}
+	}
+	switch unit := s.word(i + 1); unit {
+	case "h", "hr", "hrs", "hour", "hours", "m", "min", "mins", "minute", "minutes":
+		return toHours(value, unit), 2
+	}
+	return 0, 0
+}
+
+func toHours(value float64, unit string) float64 {
+	if strings.HasPrefix(unit, "h") {
+		return value
+	}
+	return value / 60
+}
+
+// matchDuration recognises "for <duration>" and bare compact durations like "90m"
+func (s *parseState) matchDuration(i int) (int, error) {
+	offset := 0
+	if s.word(i) == "for" {
+		offset = 1
+	} else if !compactDurationPattern.MatchString(s.word(i)) && !mixedDurationPattern.MatchString(s.word(i)) {
+		return 0, nil
+	}
+
+	hours, n := s.parseDurationAt(i + offset)
+	if n == 0 {
+		return 0, nil
+	}
+	if err := s.setDuration(hours); err != nil {
+		return 0, err
+	}
+	return offset + n, nil
+}
+
+func (s *parseState) setDuration(hours float64) error {
+	if hours <= 0 || hours > 24 {
+		return fmt.Errorf("duration must be between 30 minutes and 24 hours")
+	}
+	if math.Mod(hours*60, 30) != 0 {
+		return fmt.Errorf("duration must be a multiple of 30 minutes")
+	}
+	s.duration = hours
+	s.hasDuration = true
+	return nil
+}
+
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm|a|p)?$`)
+
+// parseClock parses a time of day at token i. Bare numbers are only accepted when
+// allowBare is set (after "at", "from" or in a range), since they are otherwise ambiguous.
+func (s *parseState) parseClock(i int, allowBare bool) (time.Duration, int, error) {
+	word := s.word(i)
+	switch word {
+	case "noon", "midday":
+		return 12 * time.Hour, 1, nil
+	case "midnight":
+		return 0, 1, nil
+	}
+
+	m := clockPattern.FindStringSubmatch(word)
+	if m == nil {
+		return 0, 0, nil
+	}
+
+	n := 1
+	suffix := m[3]
+	if suffix == "" {
+		// Allow a separate "am"/"pm" token, e.g. "9 am"
+		switch s.word(i + 1) {
+		case "am", "pm":
+			suffix = s.word(i + 1)
+			n = 2
+		}
+	}
+	if m[2] == "" && suffix == "" && !allowBare {
+		return 0, 0, nil
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	if suffix != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("invalid time %q", s.tokens[i])
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if strings.HasPrefix(suffix, "p") {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q", s.tokens[i])
+	}
+	if minute != 0 && minute != 30 {
+		return 0, 0, fmt.Errorf("time %q must be on the hour or half hour", s.tokens[i])
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, n, nil
+}
+
+// matchTime recognises a time of day, optionally preceded by "at"
+func (s *parseState) matchTime(i int) (int, error) {
+	offset := 0
+	if s.word(i) == "at" || s.word(i) == "@" {
+		offset = 1
+	}
+
+	clock, n, err := s.parseClock(i+offset, offset == 1)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	s.clock = clock
+	s.hasTime = true
+	return offset + n, nil
+}
+
+// matchTimeRange recognises "9am-10:30am", "9-11am", "from 9 to 11" and "9am to 11am"
+func (s *parseState) matchTimeRange(i int) (int, error) {
+	offset := 0
+	if s.word(i) == "from" || s.word(i) == "at" {
+		offset = 1
+	}
+
+	// Single token "9am-10:30am"; bare "1-2" is only a range after "from" or "at"
+	word := s.word(i + offset)
+	parts := strings.Split(word, "-")
+	if len(parts) == 2 && parts[0] != "" && parts[1] != "" && (offset == 1 || strings.ContainsAny(word, ":apm")) {
+		rangeState := &parseState{lower: parts, tokens: parts}
+		end, n, err := rangeState.parseClock(1, true)
+		if err != nil || n == 0 {
+			return 0, err
+		}
+		start, n, err := rangeState.parseClock(0, true)
+		if err != nil || n == 0 {
+			return 0, err
+		}
+		return offset + 1, s.setRange(parts[0], start, end, rangeState.lower[1])
+	}
+
+	// "from 9 to 11" / "9am to 11am" / "9am until 11am"
+	start, n, err := s.parseClock(i+offset, offset == 1)
+	if err != nil || n == 0 {
+		return 0, nil
+	}
+	sep := s.word(i + offset + n)
+	if sep != "to" && sep != "until" && sep != "till" && sep != "-" {
+		return 0, nil
+	}
+	endIndex := i + offset + n + 1
+	end, m, err := s.parseClock(endIndex, true)
+	if err != nil || m == 0 {
+		return 0, err
+	}
+	return offset + n + 1 + m, s.setRange(s.word(i+offset), start, end, s.word(endIndex))
+}
+
+// setRange sets the start time and duration from a time range. A start without
+// am/pm borrows the end's suffix, so "9-11am" is 09:00-11:00 and "1-3pm" is 13:00-15:00.
+func (s *parseState) setRange(startWord string, start, end time.Duration, endWord string) error {
+	startHasSuffix := strings.HasSuffix(startWord, "m") || strings.HasSuffix(startWord, "a") || strings.HasSuffix(startWord, "p")
+	if !startHasSuffix && strings.HasSuffix(endWord, "pm") && start < 12*time.Hour && start+12*time.Hour < end {
+		start += 12 * time.Hour
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+
+	s.clock = start
+	s.hasTime = true
+	return s.setDuration((end - start).Hours())
+}
+
+var (
+	isoDatePattern     = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	compactDatePattern = regexp.MustCompile(`^(\d{4})(\d{2})(\d{2})$`)
+	dayNumberPattern   = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+)
+
+// matchDate recognises absolute and relative dates, optionally preceded by "on"
+func (s *parseState) matchDate(i int) (int, error) {
+	offset := 0
+	if s.word(i) == "on" {
+		offset = 1
+	}
+
+	date, n, err := s.parseDateAt(i + offset)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	if s.hasDate && !s.recurring {
+		return 0, fmt.Errorf("more than one date given")
+	}
+	s.date = date
+	s.hasDate = true
+	s.weekdayDate = false
+	return offset + n, nil
+}
+
+func (s *parseState) parseDateAt(i int) (time.Time, int, error) {
+	today := dateOnly(s.p.Now)
+	word := s.word(i)
+
+	switch word {
+	case "today", "tonight":
+		return today, 1, nil
+	case "tomorrow", "tmr", "tmrw":
+		return today.AddDate(0, 0, 1), 1, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), 1, nil
+	case "next", "this":
+		if weekday, ok := weekdays[s.word(i+1)]; ok {
+			return s.nextWeekday(weekday, word == "this"), 2, nil
+		}
+		if word == "next" && s.word(i+1) == "week" {
+			return today.AddDate(0, 0, 7), 2, nil
+		}
+		if word == "next" && s.word(i+1) == "month" {
+			return today.AddDate(0, 1, 0), 2, nil
+		}
+		return time.Time{}, 0, nil
+	case "in":
+		if n, ok := parseCount(s.word(i + 1)); ok {
+			switch s.word(i + 2) {
+			case "day", "days":
+				return today.AddDate(0, 0, n), 3, nil
+			case "week", "weeks":
+				return today.AddDate(0, 0, 7*n), 3, nil
+			}
+		}
+		if s.word(i+1) == "a" && s.word(i+2) == "week" {
+			return today.AddDate(0, 0, 7), 3, nil
+		}
+		return time.Time{}, 0, nil
+	}
+
+	if weekday, ok := weekdays[word]; ok {
+		return s.nextWeekday(weekday, true), 1, nil
+	}
+
+	if m := isoDatePattern.FindStringSubmatch(word); m != nil {
+		return s.buildDate(m[1], m[2], m[3], s.tokens[i])
+	}
+	if m := compactDatePattern.FindStringSubmatch(word); m != nil {
+		return s.buildDate(m[1], m[2], m[3], s.tokens[i])
+	}
+
+	// "jul 7", "july 7th"
+	if month, ok := months[word]; ok {
+		if m := dayNumberPattern.FindStringSubmatch(s.word(i + 1)); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			date, err := s.monthDay(month, day)
+			return date, 2, err
+		}
+		return time.Time{}, 0, nil
+	}
+
+	// "7 jul", "7th july", "7th of july"
+	if m := dayNumberPattern.FindStringSubmatch(word); m != nil {
+		next := i + 1
+		if s.word(next) == "of" {
+			next++
+		}
+		if month, ok := months[s.word(next)]; ok {
+			day, _ := strconv.Atoi(m[1])
+			date, err := s.monthDay(month, day)
+			return date, next - i + 1, err
+		}
+	}
+
+	return time.Time{}, 0, nil
+}
+
+func (s *parseState) buildDate(year, month, day, original string) (time.Time, int, error) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, s.p.Now.Location())
+	if date.Month() != time.Month(m) || date.Day() != d {
+		return time.Time{}, 0, fmt.Errorf("invalid date %q", original)
+	}
+	return date, 1, nil
+}
+
+// monthDay returns the next occurrence of the given month and day, including today
+func (s *parseState) monthDay(month time.Month, day int) (time.Time, error) {
+	today := dateOnly(s.p.Now)
+	year := today.Year()
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Month() != month {
+		return time.Time{}, fmt.Errorf("invalid date %s %d", month, day)
+	}
+	if date.Before(today) {
+		date = time.Date(year+1, month, day, 0, 0, 0, 0, today.Location())
+	}
+	return date, nil
+}
+
+// nextWeekday returns the next date falling on weekday, including today if includeToday is set
+func (s *parseState) nextWeekday(weekday time.Weekday, includeToday bool) time.Time {
+	today := dateOnly(s.p.Now)
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// matchColor recognises "#red", "#blue", ...
+func (s *parseState) matchColor(i int) (int, error) {
+	word := s.word(i)
+	if !strings.HasPrefix(word, "#") || len(word) < 2 {
+		return 0, nil
+	}
+	name := strings.ToUpper(word[1:2]) + word[2:]
+	color := calendar.ColorNameToAttribute(name)
+	if color == gocui.ColorDefault {
+		return 0, fmt.Errorf("unknown colour %q (use one of %s)", s.tokens[i], strings.Join(calendar.GetColorNames(), ", "))
+	}
+	s.color = color
+	return 1, nil
+}
+
+// result combines the parsed pieces into a Result
+func (s *parseState) result() (*Result, error) {
+	name := strings.TrimRight(strings.Join(s.nameParts, " "), ",;")
+	if name == "" {
+		return nil, fmt.Errorf("missing event name")
+	}
+
+	var start time.Time
+	switch {
+	case s.hasDate && s.hasTime:
+		start = atClock(s.date, s.clock)
+		if start.Before(s.p.Now) && s.weekdayDate {
+			// "every mon 9am" on a Monday after 9am starts next week
+			start = start.AddDate(0, 0, s.frequency)
+		}
+	case s.hasDate:
+		start = time.Date(s.date.Year(), s.date.Month(), s.date.Day(), s.p.DefaultStart.Hour(), s.p.DefaultStart.Minute(), 0, 0, s.date.Location())
+	case s.hasTime:
+		start = atClock(s.p.Now, s.clock)
+		if start.Before(s.p.Now) {
+			start = start.AddDate(0, 0, 1)
+		}
+	default:
+		start = s.p.DefaultStart
+	}
+
+	occurrences := 1
+	if s.recurring {
+		occurrences = DefaultOccurrences
+	}
+	if s.occurrences > 0 {
+		occurrences = s.occurrences
+		if !s.recurring {
+			// "x5" on its own repeats daily
+			s.frequency = 1
+		}
+	}
+
+	return &Result{
+		Name:          name,
+		Location:      strings.TrimRight(strings.Join(s.locationParts, " "), ",;"),
+		Start:         start,
+		DurationHours: s.duration,
+		FrequencyDays: s.frequency,
+		Occurrences:   occurrences,
+		Color:         s.color,
+		HasDate:       s.hasDate,
+		HasTime:       s.hasTime,
+	}, nil
+}
+
+// atClock returns the time of day clock on t's date. Adding clock to midnight would be an hour
+// off on days the clocks change.
+func atClock(t time.Time, clock time.Duration) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, t.Location())
+}
+
+// dateOnly truncates t to midnight in its location
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,151 @@
+// Package registers holds the events yanked for pasting, as vim's registers hold text: the
+// history of recent yanks, the latest of which paste takes by default, and named registers a-z.
+package registers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// HistorySize is the number of yanks remembered, read back as registers 0-9
+const HistorySize = 10
+
+// Unnamed is the register yank and paste use when none is named: the latest yank
+const Unnamed rune = 0
+
+// Registers holds yanked events. The zero value is empty and ready to use.
+type Registers struct {
+	named map[rune][]calendar.Event
+	// history holds the latest yanks, newest first
+	history [][]calendar.Event
+}
+
+// Register is a register's name and the events it holds, in the order they were yanked
+type Register struct {
+	Name   rune
+	Events []calendar.Event
+}
+
+// IsName reports whether a character names a register: a-z, or 0-9 for the yank history
+func IsName(name rune) bool {
+	return isNamed(name) || isHistory(name)
+}
+
+func isNamed(name rune) bool {
+	return name >= 'a' && name <= 'z'
+}
+
+func isHistory(name rune) bool {
+	return name >= '0' && name <= '9'
+}
+
+// Yank stores copies of events as the latest yank and, when name is a-z, in that register too
+func (r *Registers) Yank(name rune, events []*calendar.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	switch {
+	case name == Unnamed:
+	case isNamed(name):
+	case isHistory(name):
+		return fmt.Errorf("registers 0-9 hold the yank history; name a register a-z to yank into")
+	default:
+		return fmt.Errorf("registers are named a-z or 0-9, not %q", name)
+	}
+
+	copied := make([]calendar.Event, len(events))
+	for i, event := range events {
+		copied[i] = *event
+	}
+	if isNamed(name) {
+		if r.named == nil {
+			r.named = make(map[rune][]calendar.Event)
+		}
+		r.named[name] = copied
+	}
+	r.history = append([][]calendar.Event{copied}, r.history...)
+	if len(r.history) > HistorySize {
+		r.history = r.history[:HistorySize]
+	}
+	return nil
+}
+
+// Get returns the events in a register: the latest yank for Unnamed and 0, earlier yanks for
+// 1-9 and a named register for a-z
+func (r *Registers) Get(name rune) ([]calendar.Event, error) {
+	var events []calendar.Event
+	switch {
+	case name == Unnamed:
+		if len(r.history) > 0 {
+			events = r.history[0]
+		}
+	case isHistory(name):
+		if i := int(name - '0'); i < len(r.history) {
+			events = r.history[i]
+		}
+	case isNamed(name):
+		events = r.named[name]
+	default:
+		return nil, fmt.Errorf("registers are named a-z or 0-9, not %q", name)
+	}
+
+	if len(events) == 0 {
+		if name == Unnamed {
+			return nil, fmt.Errorf("nothing yanked")
+		}
+		return nil, fmt.Errorf("register %c is empty", name)
+	}
+	return events, nil
+}
+
+// List returns the registers that hold events: the yank history 0-9, then a-z
+func (r *Registers) List() []Register {
+	var list []Register
+	for i, events := range r.history {
+		list = append(list, Register{rune('0' + i), events})
+	}
+	for name := 'a'; name <= 'z'; name++ {
+		if events, ok := r.named[name]; ok {
+			list = append(list, Register{name, events})
+		}
+	}
+	return list
+}
+
+// Paste returns copies of events placed for pasting at target, keeping their offsets from the
+// first event. The copies are repeated every everyDays days, copies times in all.
+func Paste(events []calendar.Event, target time.Time, copies, everyDays int) []calendar.Event {
+	if len(events) == 0 {
+		return nil
+	}
+	if copies < 1 {
+		copies = 1
+	}
+
+	pasted := make([]calendar.Event, 0, len(events)*copies)
+	for n := 0; n < copies; n++ {
+		start := calendar.ApplyOffset(target, n*everyDays, 0)
+		for _, event := range events {
+			days, clock := calendar.Offset(events[0].Time, event.Time)
+			event.Id = 0
+			event.Time = calendar.ApplyOffset(start, days, clock)
+			pasted = append(pasted, event)
+		}
+	}
+	return pasted
+}
+
+// Describe sums up the events in a register, e.g. "Standup Mon Oct 19 09:00" or "3 events from
+// Lunch Mon Oct 19 12:00"
+func Describe(events []calendar.Event) string {
+	if len(events) == 0 {
+		return ""
+	}
+	first := events[0]
+	if len(events) == 1 {
+		return fmt.Sprintf("%s %s", first.Name, first.Time.Format("Mon Jan 2 15:04"))
+	}
+	return fmt.Sprintf("%d events from %s %s", len(events), first.Name, first.Time.Format("Mon Jan 2 15:04"))
+}
@@ -0,0 +1,513 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/samuelstranges/chronos/internal/ics"
+)
+
+// maxRequestBody limits the size of PROPFIND/REPORT/PUT bodies
+const maxRequestBody = 1 << 20
+
+// handleCalDAV dispatches requests for the principal, the collection and its event resources
+func (s *Server) handleCalDAV(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	urlPath := r.URL.Path
+	switch {
+	case urlPath == "/" || urlPath == PrincipalPath || urlPath == strings.TrimSuffix(PrincipalPath, "/"):
+		s.handlePrincipal(w, r)
+	case urlPath == CollectionPath || urlPath == strings.TrimSuffix(CollectionPath, "/"):
+		s.handleCollection(w, r)
+	case strings.HasPrefix(urlPath, CollectionPath) && strings.HasSuffix(urlPath, ".ics"):
+		s.handleResource(w, r, path.Base(urlPath))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handlePrincipal answers discovery PROPFINDs so clients can find the calendar collection
+func (s *Server) handlePrincipal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PROPFIND" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var ms multiStatus
+	ms.add(r.URL.Path, []string{
+		`<d:resourcetype><d:collection/><d:principal/></d:resourcetype>`,
+		`<d:displayname>Chronos</d:displayname>`,
+		`<d:current-user-principal><d:href>` + PrincipalPath + `</d:href></d:current-user-principal>`,
+		`<d:principal-URL><d:href>` + PrincipalPath + `</d:href></d:principal-URL>`,
+		`<c:calendar-home-set><d:href>` + PrincipalPath + `</d:href></c:calendar-home-set>`,
+	})
+
+	if r.Header.Get("Depth") == "1" {
+		events, err := s.allEvents()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ms.add(CollectionPath, s.collectionProps(events))
+	}
+
+	ms.write(w)
+}
+
+// handleCollection implements PROPFIND and REPORT on the calendar collection
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "PROPFIND":
+		events, err := s.allEvents()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		clients, err := s.clientResources()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var ms multiStatus
+		ms.add(CollectionPath, s.collectionProps(events))
+		if r.Header.Get("Depth") != "0" {
+			for _, event := range events {
+				ms.add(clients.href(event), s.resourceProps(event, clients, false))
+			}
+		}
+		ms.write(w)
+
+	case "REPORT":
+		s.handleReport(w, r)
+
+	case http.MethodGet, http.MethodHead:
+		s.handleFeed(w, r)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReport answers calendar-query and calendar-multiget reports with full calendar data
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseReport(body)
+	if err != nil {
+		http.Error(w, "Invalid REPORT body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.sync {
+		// No sync-token is advertised; clients poll the CTag instead
+		http.Error(w, "sync-collection REPORT is not supported", http.StatusForbidden)
+		return
+	}
+
+	events, err := s.allEvents()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	clients, err := s.clientResources()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var ms multiStatus
+	if query.multiget {
+		byHref := make(map[string]*calendar.Event, len(events))
+		for _, event := range events {
+			byHref[clients.href(event)] = event
+		}
+		for _, href := range query.hrefs {
+			if event, ok := byHref[href]; ok {
+				ms.add(href, s.resourceProps(event, clients, true))
+			} else {
+				ms.addStatus(href, http.StatusNotFound)
+			}
+		}
+	} else {
+		for _, event := range events {
+			if !query.matches(event) {
+				continue
+			}
+			ms.add(clients.href(event), s.resourceProps(event, clients, true))
+		}
+	}
+
+	ms.write(w)
+}
+
+// handleResource implements GET, PUT and DELETE on a single event resource, named after the
+// event's ID or by the client that created it
+func (s *Server) handleResource(w http.ResponseWriter, r *http.Request, name string) {
+	id, issued, err := s.resourceEventId(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		event, err := s.lookupEvent(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if event == nil {
+			http.NotFound(w, r)
+			return
+		}
+		clients, err := s.clientResources()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("ETag", s.eventETag(event))
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, s.eventICS(event, clients))
+		}
+
+	case http.MethodPut:
+		if config.IsServeReadOnly(s.config) {
+			http.Error(w, "Server is read-only", http.StatusForbidden)
+			return
+		}
+		s.handlePut(w, r, name, id, issued)
+
+	case http.MethodDelete:
+		if config.IsServeReadOnly(s.config) {
+			http.Error(w, "Server is read-only", http.StatusForbidden)
+			return
+		}
+		event, err := s.lookupEvent(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if event == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && match != "*" && match != s.eventETag(event) {
+			http.Error(w, "Event has changed", http.StatusPreconditionFailed)
+			return
+		}
+		if err := s.deleteEvent(id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePut creates or updates an event from an iCalendar body.
+// Resources the server listed an event under and still carrying its UID, created by a client
+// under the same name, or carrying a Chronos UID or one a client created an event with update
+// that event; anything else is created as a new event, remembering the client's name and UID for
+// it. issued reports that name is one the server listed the event id under.
+func (s *Server) handlePut(w http.ResponseWriter, r *http.Request, name string, id int, issued bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	parsed, err := s.parser.ParseEvents(string(body))
+	if err != nil {
+		http.Error(w, "Invalid calendar data: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(parsed) != 1 {
+		http.Error(w, "Exactly one VEVENT is required per resource", http.StatusBadRequest)
+		return
+	}
+	incoming := parsed[0]
+
+	if uidId, _ := ics.ParseEventID(incoming.UID); issued && incoming.UID != "" && uidId != id {
+		// A client's own event that happens to share the name, not the event listed under it
+		id = 0
+	}
+	if id == 0 {
+		id, _ = ics.ParseEventID(incoming.UID)
+	}
+	if id == 0 {
+		if id, err = s.uidEventId(incoming.UID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	existing, err := s.lookupEvent(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if existing == nil {
+		if r.Header.Get("If-Match") != "" {
+			http.Error(w, "Event does not exist", http.StatusPreconditionFailed)
+			return
+		}
+		added, err := s.addEvent(incoming.Event)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if issued {
+			// The name is still another event's, so list this one under its own ID
+			name = strconv.Itoa(added.Id) + ".ics"
+		}
+		clients, err := s.rememberResource(name, incoming.UID, added.Id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Location", clients.href(added))
+		w.Header().Set("ETag", s.eventETag(s.mustLookup(added.Id, added)))
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	if r.Header.Get("If-None-Match") == "*" {
+		http.Error(w, "Event already exists", http.StatusPreconditionFailed)
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != "*" && match != s.eventETag(existing) {
+		http.Error(w, "Event has changed", http.StatusPreconditionFailed)
+		return
+	}
+
+	// Clients don't know about Chronos colors, so keep the stored one
+	updated := incoming.Event
+	updated.Color = existing.Color
+	updated.FrequencyDay = existing.FrequencyDay
+	updated.Occurence = existing.Occurence
+	if err := s.updateEvent(id, updated); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	// A name the server listed an event under stays that event's
+	if !issued {
+		if _, err := s.rememberResource(name, incoming.UID, id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("ETag", s.eventETag(s.mustLookup(id, existing)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupEvent returns the event for a resource ID, or nil if the ID is unknown
+func (s *Server) lookupEvent(id int) (*calendar.Event, error) {
+	if id <= 0 {
+		return nil, nil
+	}
+	return s.getEvent(id)
+}
+
+// mustLookup re-reads an event after a write, falling back to the given event on failure
+func (s *Server) mustLookup(id int, fallback *calendar.Event) *calendar.Event {
+	if event, err := s.getEvent(id); err == nil && event != nil {
+		return event
+	}
+	return fallback
+}
+
+// rememberResource saves the name and UID a client PUT an event under, unless they are the
+// event's own, and returns the resources clients created events under
+func (s *Server) rememberResource(name, uid string, id int) (resources, error) {
+	uidId, _ := ics.ParseEventID(uid)
+	if name != strconv.Itoa(id)+".ics" || (uid != "" && uidId != id) {
+		if err := s.saveResource(database.CalDAVResource{Name: name, UID: uid, EventId: id}); err != nil {
+			return nil, err
+		}
+	}
+	return s.clientResources()
+}
+
+// resources holds the resources clients created events under, by event ID
+type resources map[int]database.CalDAVResource
+
+// href returns the CalDAV resource path of an event: the name its client created it under, or
+// else its ID
+func (clients resources) href(event *calendar.Event) string {
+	if resource, ok := clients[event.Id]; ok {
+		return CollectionPath + url.PathEscape(resource.Name)
+	}
+	return CollectionPath + strconv.Itoa(event.Id) + ".ics"
+}
+
+// collectionProps returns the properties of the calendar collection
+func (s *Server) collectionProps(events []*calendar.Event) []string {
+	props := []string{
+		`<d:resourcetype><d:collection/><c:calendar/></d:resourcetype>`,
+		`<d:displayname>Chronos</d:displayname>`,
+		`<c:supported-calendar-component-set><c:comp name="VEVENT"/></c:supported-calendar-component-set>`,
+		`<cs:getctag>` + s.collectionCTag(events) + `</cs:getctag>`,
+	}
+
+	privileges := `<d:privilege><d:read/></d:privilege>`
+	if !config.IsServeReadOnly(s.config) {
+		privileges += `<d:privilege><d:write/></d:privilege>`
+	}
+	props = append(props, `<d:current-user-privilege-set>`+privileges+`</d:current-user-privilege-set>`)
+
+	return props
+}
+
+// resourceProps returns the properties of an event resource, optionally including its data
+func (s *Server) resourceProps(event *calendar.Event, clients resources, withData bool) []string {
+	props := []string{
+		`<d:resourcetype/>`,
+		`<d:getetag>` + xmlEscape(s.eventETag(event)) + `</d:getetag>`,
+		`<d:getcontenttype>text/calendar; charset=utf-8; component=VEVENT</d:getcontenttype>`,
+	}
+	if withData {
+		props = append(props, `<c:calendar-data>`+xmlEscape(s.eventICS(event, clients))+`</c:calendar-data>`)
+	}
+	return props
+}
+
+// multiStatus builds a WebDAV 207 Multi-Status response
+type multiStatus struct {
+	responses []string
+}
+
+// add appends a response with the given successful properties
+func (ms *multiStatus) add(href string, props []string) {
+	ms.responses = append(ms.responses,
+		`<d:response><d:href>`+xmlEscape(href)+`</d:href><d:propstat><d:prop>`+
+			strings.Join(props, "")+
+			`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+}
+
+// addStatus appends a response that only carries a status code
+func (ms *multiStatus) addStatus(href string, status int) {
+	ms.responses = append(ms.responses,
+		fmt.Sprintf(`<d:response><d:href>%s</d:href><d:status>HTTP/1.1 %d %s</d:status></d:response>`,
+			xmlEscape(href), status, http.StatusText(status)))
+}
+
+// write sends the multi-status document
+func (ms *multiStatus) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	fmt.Fprint(w, xml.Header)
+	fmt.Fprint(w, `<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, response := range ms.responses {
+		fmt.Fprint(w, response)
+	}
+	fmt.Fprint(w, `</d:multistatus>`)
+}
+
+// reportQuery is the subset of a REPORT request that the server understands
+type reportQuery struct {
+	multiget bool
+	sync     bool
+	hrefs    []string
+	start    time.Time
+	end      time.Time
+}
+
+// matches reports whether an event overlaps the query's time-range filter (if any)
+func (q reportQuery) matches(event *calendar.Event) bool {
+	eventEnd := event.Time.Add(time.Duration(event.DurationHour * float64(time.Hour)))
+	if !q.start.IsZero() && !eventEnd.After(q.start) {
+		return false
+	}
+	if !q.end.IsZero() && !event.Time.Before(q.end) {
+		return false
+	}
+	return true
+}
+
+// parseReport extracts hrefs and the time-range filter from a REPORT body
+func parseReport(body []byte) (reportQuery, error) {
+	var query reportQuery
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return query, nil
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(string(body)))
+	inHref := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return query, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "calendar-multiget":
+				query.multiget = true
+			case "sync-collection":
+				query.sync = true
+			case "href":
+				inHref = true
+			case "time-range":
+				for _, attr := range t.Attr {
+					value, err := time.Parse("20060102T150405Z", attr.Value)
+					if err != nil {
+						continue
+					}
+					switch attr.Name.Local {
+					case "start":
+						query.start = value
+					case "end":
+						query.end = value
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "href" {
+				inHref = false
+			}
+		case xml.CharData:
+			if inHref {
+				query.hrefs = append(query.hrefs, strings.TrimSpace(string(t)))
+			}
+		}
+	}
+
+	return query, nil
+}
+
+// xmlEscape escapes text for inclusion in an XML document
+func xmlEscape(text string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
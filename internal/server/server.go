@@ -0,0 +1,282 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/ics"
+)
+
+const (
+	// FeedPath serves every event as a single read-only iCalendar feed
+	FeedPath = "/calendar.ics"
+	// PrincipalPath is both the CalDAV principal and its calendar home
+	PrincipalPath = "/caldav/"
+	// CollectionPath is the single calendar collection exposed over CalDAV
+	CollectionPath = "/caldav/chronos/"
+)
+
+// Server exposes the calendar database over HTTP as an .ics feed and a minimal CalDAV collection
+type Server struct {
+	eventManager *eventmanager.EventManager
+	database     *database.Database // remembers the resources clients create events under
+	config       *config.Config
+	exporter     *ics.ICSExporter
+	parser       *ics.ICSParser
+
+	// mu serialises access to the EventManager, which is not safe for concurrent use
+	mu        sync.Mutex
+	lastError string
+}
+
+// NewServer creates a server that routes all writes through the given EventManager, which uses db
+func NewServer(em *eventmanager.EventManager, db *database.Database, cfg *config.Config) *Server {
+	s := &Server{
+		eventManager: em,
+		database:     db,
+		config:       cfg,
+		exporter:     ics.NewICSExporter(),
+		parser:       ics.NewICSParser(),
+	}
+
+	// Capture EventManager errors so they can be returned to HTTP clients
+	em.SetErrorHandler(func(title, message string) {
+		s.lastError = title + ": " + message
+	})
+
+	return s
+}
+
+// Handler returns the HTTP handler for the server, including basic auth if configured
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(FeedPath, s.handleFeed)
+	mux.HandleFunc("/.well-known/caldav", s.handleWellKnown)
+	mux.HandleFunc(PrincipalPath, s.handleCalDAV)
+	mux.HandleFunc("/", s.handleRoot)
+
+	return s.withAuth(mux)
+}
+
+// ListenAndServe starts serving on the configured bind address
+func (s *Server) ListenAndServe() error {
+	addr := config.GetServeAddress(s.config)
+	log.Printf("Serving calendar feed at http://%s%s and CalDAV at http://%s%s", addr, FeedPath, addr, CollectionPath)
+	if config.IsServeReadOnly(s.config) {
+		log.Printf("Read-only mode: PUT and DELETE requests will be rejected")
+	}
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// withAuth wraps a handler with HTTP basic auth when credentials are configured
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	if !config.IsServeAuthEnabled(s.config) {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		userMatch := subtle.ConstantTimeCompare([]byte(username), []byte(s.config.ServeUsername)) == 1
+		passMatch := subtle.ConstantTimeCompare([]byte(password), []byte(s.config.ServePassword)) == 1
+		if !ok || !userMatch || !passMatch {
+			w.Header().Set("WWW-Authenticate", `Basic realm="chronos"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleFeed serves all events as a single iCalendar document
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	events, err := s.allEvents()
+	if err != nil {
+		http.Error(w, "Failed to load events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="chronos.ics"`)
+	fmt.Fprint(w, s.exporter.ExportEvents(events))
+}
+
+// handleWellKnown redirects CalDAV service discovery to the principal
+func (s *Server) handleWellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, PrincipalPath, http.StatusMovedPermanently)
+}
+
+// handleRoot answers principal discovery on "/" and 404s everything else
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/" && (r.Method == "PROPFIND" || r.Method == http.MethodOptions) {
+		s.handleCalDAV(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// allEvents returns every event sorted by time (times are UTC as stored)
+func (s *Server) allEvents() ([]*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.eventManager.GetAllEvents()
+}
+
+// getEvent returns a single event by ID, or nil if it does not exist
+func (s *Server) getEvent(id int) (*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.eventManager.GetEventById(id)
+}
+
+// addEvent adds an event through the EventManager so overlap rules and undo history apply
+func (s *Server) addEvent(event *calendar.Event) (*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastError = ""
+	added, ok := s.eventManager.AddEvent(*event)
+	if !ok {
+		return nil, s.managerError()
+	}
+	log.Printf("caldav: added event %d %q at %s", added.Id, added.Name, added.Time.Format("2006-01-02 15:04"))
+	return added, nil
+}
+
+// updateEvent updates an event through the EventManager
+func (s *Server) updateEvent(id int, event *calendar.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastError = ""
+	event.Id = id
+	if !s.eventManager.UpdateEvent(id, event) {
+		return s.managerError()
+	}
+	log.Printf("caldav: updated event %d %q at %s", id, event.Name, event.Time.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// deleteEvent deletes an event through the EventManager
+func (s *Server) deleteEvent(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.eventManager.DeleteEvent(id); err != nil {
+		return err
+	}
+	log.Printf("caldav: deleted event %d", id)
+	return nil
+}
+
+// resourceEventId returns the ID of the event a resource name refers to: the event a client
+// created under that name, or the event the server lists under that name, which is its ID. It is
+// 0 for neither; issued reports the latter.
+func (s *Server) resourceEventId(name string) (id int, issued bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, err := s.database.GetCalDAVResource(name)
+	if err != nil {
+		return 0, false, err
+	}
+	if resource != nil {
+		return resource.EventId, false, nil
+	}
+
+	id, err = strconv.Atoi(strings.TrimSuffix(name, ".ics"))
+	if err != nil || id <= 0 || name != strconv.Itoa(id)+".ics" {
+		return 0, false, nil
+	}
+	// Events a client created are listed under the client's name instead
+	clients, err := s.database.GetCalDAVResources()
+	if err != nil {
+		return 0, false, err
+	}
+	if _, ok := clients[id]; ok {
+		return 0, false, nil
+	}
+	return id, true, nil
+}
+
+// uidEventId returns the ID of the event a client created with a UID, or 0
+func (s *Server) uidEventId(uid string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, err := s.database.GetCalDAVResourceByUID(uid)
+	if err != nil || resource == nil {
+		return 0, err
+	}
+	return resource.EventId, nil
+}
+
+// saveResource remembers the name and UID a client gave an event
+func (s *Server) saveResource(resource database.CalDAVResource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.database.SaveCalDAVResource(resource)
+}
+
+// clientResources returns the resources clients created events under, by event ID
+func (s *Server) clientResources() (resources, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.database.GetCalDAVResources()
+}
+
+// managerError converts the last error reported by the EventManager into an error value
+func (s *Server) managerError() error {
+	if s.lastError == "" {
+		return errors.New("event rejected")
+	}
+	return errors.New(s.lastError)
+}
+
+// eventETag returns a strong ETag derived from the event's stored fields
+func (s *Server) eventETag(event *calendar.Event) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s|%s|%s|%d|%g|%d",
+		event.Id, event.Name, event.Description, event.Location,
+		event.Time.Unix(), event.DurationHour, int(event.Color))
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// collectionCTag returns a tag that changes whenever any event in the collection changes
+func (s *Server) collectionCTag(events []*calendar.Event) string {
+	tags := make([]string, len(events))
+	for i, event := range events {
+		tags[i] = fmt.Sprintf("%d:%s", event.Id, s.eventETag(event))
+	}
+	sort.Strings(tags)
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(tags, ",")))
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// eventICS renders a single event as a complete iCalendar object, under the UID its client gave it
+func (s *Server) eventICS(event *calendar.Event, clients resources) string {
+	data := s.exporter.ExportEvents([]*calendar.Event{event})
+	if resource, ok := clients[event.Id]; ok && resource.UID != "" {
+		data = strings.Replace(data, "UID:"+ics.EventUID(event)+"\r\n", "UID:"+resource.UID+"\r\n", 1)
+	}
+	return data
+}
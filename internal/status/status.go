@@ -0,0 +1,200 @@
+// Package status formats the next or current event for status bars, as printed by
+// chronos --next and --current.
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jroimartin/gocui"
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// Options controls how --next and --current are printed
+type Options struct {
+	Format   string
+	JSON     bool
+	Width    int
+	Color    bool
+	Watch    bool
+	Interval time.Duration
+
+	tmpl *template.Template
+}
+
+// Event is the value passed to --format templates and printed by --json
+type Event struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	Location     string    `json:"location,omitempty"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Color        string    `json:"color"`
+	Until        string    `json:"until"`         // time left until Start (--next) or End (--current), e.g. "1h 25m"
+	UntilMinutes int       `json:"until_minutes"` // the same in whole minutes
+
+	color gocui.Attribute
+}
+
+// NewEvent converts an event for printing at now. Until counts down to the end of the event when
+// untilEnd is set, otherwise to its start.
+func NewEvent(event *calendar.Event, now time.Time, untilEnd bool) *Event {
+	start := event.Time.Local()
+	end := start.Add(time.Duration(event.DurationHour * float64(time.Hour)))
+	remaining := start.Sub(now)
+	if untilEnd {
+		remaining = end.Sub(now)
+	}
+
+	return &Event{
+		ID:           event.Id,
+		Name:         event.Name,
+		Description:  event.Description,
+		Location:     event.Location,
+		Start:        start,
+		End:          end,
+		Color:        calendar.ColorAttributeToName(event.Color),
+		Until:        FormatUntil(remaining),
+		UntilMinutes: int(remaining.Round(time.Minute) / time.Minute),
+		color:        event.Color,
+	}
+}
+
+// DescribeNext is the default text output of --next
+func DescribeNext(event *Event) string {
+	if event == nil {
+		return "No upcoming events found"
+	}
+	text := fmt.Sprintf("%s at %s", event.Name, event.Start.Format("2006-01-02 15:04"))
+	return text + describeDetails(event)
+}
+
+// DescribeCurrent is the default text output of --current
+func DescribeCurrent(event *Event) string {
+	if event == nil {
+		return "No current event"
+	}
+	text := fmt.Sprintf("%s (until %s)", event.Name, event.End.Format("15:04"))
+	return text + describeDetails(event)
+}
+
+// describeDetails returns the description and location lines of the default text output
+func describeDetails(event *Event) string {
+	var details string
+	if event.Description != "" {
+		details += "\nDescription: " + event.Description
+	}
+	if event.Location != "" {
+		details += "\nLocation: " + event.Location
+	}
+	return details
+}
+
+// FormatUntil formats a duration for status bars, e.g. "45m", "1h 5m" or "2d 3h"
+func FormatUntil(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 0 {
+		minutes = 0
+	}
+	days, hours, mins := minutes/(24*60), minutes/60%24, minutes%60
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && mins > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", mins)
+	}
+}
+
+// Parse validates the options and compiles the --format template
+func (opts *Options) Parse() error {
+	if opts.JSON && opts.Format != "" {
+		return fmt.Errorf("--json and --format cannot be used together")
+	}
+	if opts.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if opts.Format != "" {
+		tmpl, err := template.New("format").Parse(opts.Format)
+		if err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+		opts.tmpl = tmpl
+	}
+	return nil
+}
+
+// Render formats an event, or nil for none, as JSON, through the template or with describe
+func (opts *Options) Render(event *Event, describe func(*Event) string) (string, error) {
+	var output string
+	switch {
+	case opts.JSON:
+		data, err := json.Marshal(event)
+		if err != nil {
+			return "", err
+		}
+		// Colour and truncation would break JSON consumers
+		return string(data), nil
+	case opts.tmpl != nil:
+		if event != nil {
+			var sb strings.Builder
+			if err := opts.tmpl.Execute(&sb, event); err != nil {
+				return "", fmt.Errorf("template: %w", err)
+			}
+			output = sb.String()
+		}
+	default:
+		output = describe(event)
+	}
+
+	output = TruncateLines(output, opts.Width)
+	if opts.Color && event != nil && output != "" {
+		output = calendar.ColorToANSI(event.color) + output + calendar.ANSIReset()
+	}
+	return output, nil
+}
+
+// TruncateLines shortens every line to at most width characters, ending in "…"; width 0 disables it
+func TruncateLines(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) > width {
+			runes := []rune(line)
+			if width == 1 {
+				lines[i] = "…"
+			} else {
+				lines[i] = string(runes[:width-1]) + "…"
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PrintChanges prints the output of render, then renders again on every tick and prints the
+// output only when it changed, until ticks is closed
+func PrintChanges(w io.Writer, ticks <-chan time.Time, render func() string) {
+	last := render()
+	fmt.Fprintln(w, last)
+	for range ticks {
+		if output := render(); output != last {
+			fmt.Fprintln(w, output)
+			last = output
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/samuelstranges/chronos/pkg/views"
+	"github.com/jroimartin/gocui"
+)
+
+// altTimeout is how soon a key must follow Esc to be read as Alt with that key. gocui reads Alt
+// combinations as Esc followed by the key, which terminals send together.
+const altTimeout = 25 * time.Millisecond
+
+// maxCount caps the count typed before an action
+const maxCount = 9999
+
+// escKey is the key Alt combinations start with
+var escKey = keymap.Key{Key: gocui.KeyEsc}
+
+// dispatcher binds each key once per view and runs the action the keymap maps it to, taking the
+// digits typed before a key as a count and, for some actions, the key typed after it
+type dispatcher struct {
+	km       *keymap.Keymap
+	handlers map[string]func(*gocui.Gui, *gocui.View) error
+	// counted are the actions that use a count themselves rather than run that many times
+	counted map[string]func(*gocui.Gui, *gocui.View, int) error
+	// lettered are the actions that run with the character typed after their key, e.g. 'a
+	lettered map[string]func(*gocui.Gui, *gocui.View, rune) error
+	av       *views.AppView
+	hasAlt   bool
+
+	// count is the count typed so far, 0 for none
+	count int
+	// awaiting is the lettered action whose key was pressed last, "" for none
+	awaiting string
+
+	// escPending is set from an Esc press until the next key or altTimeout when Alt keys are
+	// bound; escSeq tells the timeout of an earlier Esc apart
+	escPending bool
+	escSeq     int
+}
+
+func newDispatcher(km *keymap.Keymap, handlers map[string]func(*gocui.Gui, *gocui.View) error, counted map[string]func(*gocui.Gui, *gocui.View, int) error, lettered map[string]func(*gocui.Gui, *gocui.View, rune) error, av *views.AppView) *dispatcher {
+	return &dispatcher{km: km, handlers: handlers, counted: counted, lettered: lettered, av: av, hasAlt: km.HasAltBindings()}
+}
+
+// bind sets a keybinding on every named view for each key bound in scope or, outside the year
+// view, in visual and grab mode, and for the digits and lowercase letters that are not bound, which type a
+// count or follow a lettered action
+func (d *dispatcher) bind(g *gocui.Gui, viewNames []string, scope string) error {
+	keys := d.km.ScopeKeys(scope)
+	if scope != keymap.ScopeYear {
+		for _, mode := range []string{keymap.ScopeVisual, keymap.ScopeGrab} {
+			for _, key := range d.km.ScopeKeys(mode) {
+				if !containsPlainKey(keys, key) {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	if d.hasAlt && !containsPlainKey(keys, escKey) {
+		keys = append(keys, escKey)
+	}
+	for _, chars := range [][2]rune{{'0', '9'}, {'a', 'z'}} {
+		for ch := chars[0]; ch <= chars[1]; ch++ {
+			if plain := (keymap.Key{Ch: ch}); !containsPlainKey(keys, plain) {
+				keys = append(keys, plain)
+			}
+		}
+	}
+
+	for _, viewName := range viewNames {
+		for _, key := range keys {
+			key := key
+			handler := func(g *gocui.Gui, v *gocui.View) error {
+				return d.press(g, v, scope, key)
+			}
+			if err := g.SetKeybinding(viewName, key.Binding(), gocui.ModNone, handler); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// press handles a key. With Alt keys bound, Esc waits briefly to see whether it starts one.
+func (d *dispatcher) press(g *gocui.Gui, v *gocui.View, scope string, key keymap.Key) error {
+	d.av.DismissCommandOutput()
+
+	if d.awaiting != "" {
+		// The character after a lettered action's key goes to the action; other keys cancel it
+		action := d.awaiting
+		d.awaiting = ""
+		d.av.ShowPendingKeys("")
+		if key.Mod == 0 && key.Ch != 0 {
+			return d.lettered[action](g, v, key.Ch)
+		}
+		return nil
+	}
+
+	if d.escPending {
+		d.escPending = false
+		alt := key
+		alt.Mod = gocui.ModAlt
+		if action, ok := d.lookup(scope, alt); ok {
+			return d.runAction(g, v, action, alt)
+		}
+		// Esc was pressed on its own before this key
+		if err := d.run(g, v, scope, escKey); err != nil {
+			return err
+		}
+	}
+
+	if d.hasAlt && key == escKey {
+		d.escPending = true
+		d.escSeq++
+		seq := d.escSeq
+		time.AfterFunc(altTimeout, func() {
+			g.Update(func(g *gocui.Gui) error {
+				if !d.escPending || d.escSeq != seq {
+					return nil
+				}
+				d.escPending = false
+				return d.run(g, v, scope, escKey)
+			})
+		})
+		return nil
+	}
+
+	return d.run(g, v, scope, key)
+}
+
+// run runs the action bound to a key, if any. Unbound digits add to the count, which any other
+// key ends.
+func (d *dispatcher) run(g *gocui.Gui, v *gocui.View, scope string, key keymap.Key) error {
+	if action, ok := d.lookup(scope, key); ok {
+		return d.runAction(g, v, action, key)
+	}
+
+	// A count starts with 1-9, as 0 on its own is a key of its own in vim
+	if key.Mod == 0 && key.Ch >= '0' && key.Ch <= '9' && (key.Ch != '0' || d.count > 0) {
+		count := d.count*10 + int(key.Ch-'0')
+		if count > maxCount {
+			count = maxCount
+		}
+		d.setCount(count)
+		return nil
+	}
+	d.setCount(0)
+	return nil
+}
+
+// lookup returns the action a key runs in a view with the given scope, or in visual mode. Grab
+// mode runs its own keys only.
+func (d *dispatcher) lookup(scope string, key keymap.Key) (string, bool) {
+	if d.av.IsGrabMode() {
+		return d.km.LookupMode(keymap.ScopeGrab, key)
+	}
+	if d.av.IsVisualMode() {
+		if action, ok := d.km.LookupMode(keymap.ScopeVisual, key); ok {
+			return action, true
+		}
+	}
+	return d.km.Lookup(scope, key)
+}
+
+// runAction runs an action with the count typed before it, or waits for the character after
+// key for a lettered action
+func (d *dispatcher) runAction(g *gocui.Gui, v *gocui.View, action string, key keymap.Key) error {
+	count := d.count
+	d.setCount(0)
+
+	if _, ok := d.lettered[action]; ok {
+		d.awaiting = action
+		d.av.ShowPendingKeys(key.String())
+		return nil
+	}
+	// A register named with " is for this action only
+	defer d.av.ClearRegister()
+
+	if handler, ok := d.counted[action]; ok {
+		return handler(g, v, count)
+	}
+	if count > 1 && keymap.Repeats(action) {
+		for i := 0; i < count; i++ {
+			if err := d.handlers[action](g, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return d.handlers[action](g, v)
+}
+
+// setCount sets the count typed so far and shows it
+func (d *dispatcher) setCount(count int) {
+	d.count = count
+	if count == 0 {
+		d.av.ShowPendingKeys("")
+	} else {
+		d.av.ShowPendingKeys(strconv.Itoa(count))
+	}
+}
+
+func containsPlainKey(keys []keymap.Key, key keymap.Key) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
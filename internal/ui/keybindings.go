@@ -2,131 +2,213 @@ package ui
 
 import (
 	"fmt"
-	"os"
+	"github.com/samuelstranges/chronos/internal/keymap"
 	"github.com/samuelstranges/chronos/pkg/views"
 	"github.com/jroimartin/gocui"
 )
 
-type Keybind struct {
-	key     interface{}
-	handler func(*gocui.Gui, *gocui.View) error
-}
-
-// Debug logging function for keybindings
-func debugLogKeybinding(key interface{}, viewName string, av *views.AppView) {
-	f, err := os.OpenFile("/tmp/chronos_keybind_debug.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
-	}
-	defer f.Close()
-	
-	keyStr := fmt.Sprintf("%v", key)
-	if keyStr == "65514" { // Arrow left
-		keyStr = "ArrowLeft"
-	} else if keyStr == "65515" { // Arrow right
-		keyStr = "ArrowRight"
-	} else if keyStr == "65516" { // Arrow up
-		keyStr = "ArrowUp"
-	} else if keyStr == "65517" { // Arrow down
-		keyStr = "ArrowDown"
-	}
-	
-	currentView := "unknown"
-	if av != nil {
-		currentView = av.GetCurrentViewName()
-	}
-	
-	fmt.Fprintf(f, "KEY_PRESS: key=%s, view=%s, currentView=%s, monthMode=%t\n", 
-		keyStr, viewName, currentView, av != nil && av.IsMonthMode())
-}
-
-func InitKeybindings(g *gocui.Gui, av *views.AppView) error {
+func InitKeybindings(g *gocui.Gui, av *views.AppView, km *keymap.Keymap) error {
 	g.InputEsc = true
+	av.SetKeymap(km)
 
-	if err := initMainKeybindings(g, av); err != nil {
+	if err := initMainKeybindings(g, av, km); err != nil {
+		return err
+	}
+	if err := initHelpKeybindings(g, av, km); err != nil {
+		return err
+	}
+	if err := initCommandLineKeybindings(g, av); err != nil {
 		return err
 	}
-	if err := initHelpKeybindings(g, av); err != nil {
+	if err := initMarksKeybindings(g, av, km); err != nil {
+		return err
+	}
+	if err := initFinderKeybindings(g, av); err != nil {
+		return err
+	}
+	if err := initMouseKeybindings(g, av); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func initMainKeybindings(g *gocui.Gui, av *views.AppView) error {
-	mainKeybindings := []Keybind{
-		{'a', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('a', v.Name(), av); return av.ShowNewEventPopup(g) }},
-		{'c', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('c', v.Name(), av); return av.ShowEditEventPopup(g) }},
-		{'C', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('C', v.Name(), av); return av.ShowColorPicker(g) }},
-		{'d', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('d', v.Name(), av); return av.ShowDurationPopup(g) }},
-		{'h', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('h', v.Name(), av); av.UpdateToPrevDay(g); return nil }},
-		{'l', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('l', v.Name(), av); av.UpdateToNextDay(g); return nil }},
-		{'j', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('j', v.Name(), av); av.UpdateToNextTime(g); return nil }},
-		{'k', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('k', v.Name(), av); av.UpdateToPrevTime(g); return nil }},
-		{gocui.KeyArrowLeft, func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding(gocui.KeyArrowLeft, v.Name(), av); av.UpdateToPrevDay(g); return nil }},
-		{gocui.KeyArrowRight, func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding(gocui.KeyArrowRight, v.Name(), av); av.UpdateToNextDay(g); return nil }},
-		{gocui.KeyArrowDown, func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding(gocui.KeyArrowDown, v.Name(), av); av.UpdateToNextTime(g); return nil }},
-		{gocui.KeyArrowUp, func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding(gocui.KeyArrowUp, v.Name(), av); av.UpdateToPrevTime(g); return nil }},
-		{'t', func(g *gocui.Gui, v *gocui.View) error { av.JumpToToday(); av.UpdateCurrentView(g); return nil }},
-		{'H', func(g *gocui.Gui, v *gocui.View) error { av.UpdateToPrevWeek(); return nil }},
-		{'L', func(g *gocui.Gui, v *gocui.View) error { av.UpdateToNextWeek(); return nil }},
-		{'x', func(g *gocui.Gui, v *gocui.View) error { av.DeleteEvent(g); return nil }},
-		{'B', func(g *gocui.Gui, v *gocui.View) error { av.DeleteEvents(g); return nil }},
-		{'y', func(g *gocui.Gui, v *gocui.View) error { av.CopyEvent(g); return nil }},
-		{'p', func(g *gocui.Gui, v *gocui.View) error { return av.PasteEvent(g) }},
-		{'u', func(g *gocui.Gui, v *gocui.View) error { return av.Undo(g) }},
-		{'r', func(g *gocui.Gui, v *gocui.View) error { return av.Redo(g) }},
-		{'T', func(g *gocui.Gui, v *gocui.View) error { return av.ShowGotoPopup(g) }},
-		{'D', func(g *gocui.Gui, v *gocui.View) error { return av.ShowDatePopup(g) }},
-		{'w', func(g *gocui.Gui, v *gocui.View) error { av.JumpToNextEvent(); av.UpdateCurrentView(g); return nil }},
-		{'b', func(g *gocui.Gui, v *gocui.View) error { av.JumpToPrevEvent(); av.UpdateCurrentView(g); return nil }},
-		{'e', func(g *gocui.Gui, v *gocui.View) error { av.JumpToEndOfEvent(); av.UpdateCurrentView(g); return nil }},
-		{'g', func(g *gocui.Gui, v *gocui.View) error { av.JumpToStartOfDay(); av.UpdateCurrentView(g); return nil }},
-		{'G', func(g *gocui.Gui, v *gocui.View) error { av.JumpToEndOfDay(); av.UpdateCurrentView(g); return nil }},
-		{'/', func(g *gocui.Gui, v *gocui.View) error { return av.StartSearch(g) }},
-		{'n', func(g *gocui.Gui, v *gocui.View) error { av.GoToNextMatch(); av.UpdateCurrentView(g); return nil }},
-		{'N', func(g *gocui.Gui, v *gocui.View) error { av.GoToPrevMatch(); av.UpdateCurrentView(g); return nil }},
-		{'m', func(g *gocui.Gui, v *gocui.View) error { av.UpdateToNextMonth(); return nil }},
-		{'M', func(g *gocui.Gui, v *gocui.View) error { av.UpdateToPrevMonth(); return nil }},
-		{'v', func(g *gocui.Gui, v *gocui.View) error { debugLogKeybinding('v', v.Name(), av); err := av.ToggleView(g); av.UpdateCurrentView(g); return err }},
-		{gocui.KeyEsc, func(g *gocui.Gui, v *gocui.View) error { av.ClearSearch(); return nil }},
-		{'?', func(g *gocui.Gui, v *gocui.View) error { return av.ShowKeybinds(g) }},
-		{'q', func(g *gocui.Gui, v *gocui.View) error { return quit(g, v) }},
-	}
-	
-	// Set keybindings for weekday views (needed for week view)
-	for _, viewName := range views.WeekdayNames {
-		for _, kb := range mainKeybindings {
-			if err := g.SetKeybinding(viewName, kb.key, gocui.ModNone, kb.handler); err != nil {
-				return err
-			}
+// actionHandlers returns what every action in the keymap registry does
+func actionHandlers(av *views.AppView) map[string]func(*gocui.Gui, *gocui.View) error {
+	return map[string]func(*gocui.Gui, *gocui.View) error{
+		"app.quit":                 quit,
+		"app.help":                 func(g *gocui.Gui, v *gocui.View) error { return av.ShowKeybinds(g) },
+		"app.command":              func(g *gocui.Gui, v *gocui.View) error { return av.ShowCommandLine(g) },
+		"view.cycle":               func(g *gocui.Gui, v *gocui.View) error { err := av.ToggleView(g); av.UpdateCurrentView(g); return err },
+		"view.more":                func(g *gocui.Gui, v *gocui.View) error { return av.ResizeLayout(g, 1) },
+		"view.fewer":               func(g *gocui.Gui, v *gocui.View) error { return av.ResizeLayout(g, -1) },
+		"agenda.toggle_empty_days": func(g *gocui.Gui, v *gocui.View) error { av.ToggleAgendaEmptyDays(g); return nil },
+		"detail.toggle":            func(g *gocui.Gui, v *gocui.View) error { av.ToggleDetailPane(); return nil },
+		"detail.move":              func(g *gocui.Gui, v *gocui.View) error { av.ToggleDetailPanePosition(); return nil },
+		"detail.scroll_down":       func(g *gocui.Gui, v *gocui.View) error { av.ScrollDetailPane(1); return nil },
+		"detail.scroll_up":         func(g *gocui.Gui, v *gocui.View) error { av.ScrollDetailPane(-1); return nil },
+		"year.next_month":          func(g *gocui.Gui, v *gocui.View) error { av.ShiftMonth(g, 1); return nil },
+		"year.prev_month":          func(g *gocui.Gui, v *gocui.View) error { av.ShiftMonth(g, -1); return nil },
+		"year.open_week":           func(g *gocui.Gui, v *gocui.View) error { err := av.SwitchToWeekView(g); av.UpdateCurrentView(g); return err },
+		"year.open_month":          func(g *gocui.Gui, v *gocui.View) error { err := av.SwitchToMonthView(g); av.UpdateCurrentView(g); return err },
+
+		"nav.prev_day":   func(g *gocui.Gui, v *gocui.View) error { av.UpdateToPrevDay(g); return nil },
+		"nav.next_day":   func(g *gocui.Gui, v *gocui.View) error { av.UpdateToNextDay(g); return nil },
+		"nav.prev_week":  func(g *gocui.Gui, v *gocui.View) error { av.UpdateToPrevWeek(); return nil },
+		"nav.next_week":  func(g *gocui.Gui, v *gocui.View) error { av.UpdateToNextWeek(); return nil },
+		"nav.next_month": func(g *gocui.Gui, v *gocui.View) error { av.UpdateToNextMonth(); return nil },
+		"nav.prev_month": func(g *gocui.Gui, v *gocui.View) error { av.UpdateToPrevMonth(); return nil },
+		"nav.next_time":  func(g *gocui.Gui, v *gocui.View) error { av.UpdateToNextTime(g); return nil },
+		"nav.prev_time":  func(g *gocui.Gui, v *gocui.View) error { av.UpdateToPrevTime(g); return nil },
+		"nav.today":      func(g *gocui.Gui, v *gocui.View) error { av.JumpToToday(); av.UpdateCurrentView(g); return nil },
+		"nav.goto_date":  func(g *gocui.Gui, v *gocui.View) error { return av.ShowDatePopup(g) },
+		"nav.goto_time":  func(g *gocui.Gui, v *gocui.View) error { return av.ShowGotoPopup(g) },
+		"nav.next_event": func(g *gocui.Gui, v *gocui.View) error { av.JumpToNextEvent(); av.UpdateCurrentView(g); return nil },
+		"nav.prev_event": func(g *gocui.Gui, v *gocui.View) error { av.JumpToPrevEvent(); av.UpdateCurrentView(g); return nil },
+		"nav.event_end":  func(g *gocui.Gui, v *gocui.View) error { av.JumpToEndOfEvent(); av.UpdateCurrentView(g); return nil },
+		"nav.day_start":  func(g *gocui.Gui, v *gocui.View) error { av.JumpToStartOfDay(); av.UpdateCurrentView(g); return nil },
+		"nav.day_end":    func(g *gocui.Gui, v *gocui.View) error { av.JumpToEndOfDay(); av.UpdateCurrentView(g); return nil },
+
+		"event.add":              func(g *gocui.Gui, v *gocui.View) error { return av.ShowNewEventPopup(g) },
+		"event.quick_add":        func(g *gocui.Gui, v *gocui.View) error { return av.ShowQuickAddPopup(g) },
+		"event.find_free_slot":   func(g *gocui.Gui, v *gocui.View) error { return av.ShowFreeSlotPopup(g) },
+		"event.edit":             func(g *gocui.Gui, v *gocui.View) error { return av.ShowEditEventPopup(g) },
+		"event.edit_description": func(g *gocui.Gui, v *gocui.View) error { return av.EditDescriptionInEditor(g) },
+		"event.edit_document":    func(g *gocui.Gui, v *gocui.View) error { return av.EditEventInEditor(g) },
+		"event.color":            func(g *gocui.Gui, v *gocui.View) error { return av.ShowColorPicker(g) },
+		"event.duration":         func(g *gocui.Gui, v *gocui.View) error { return av.ShowDurationPopup(g) },
+		"event.yank":             func(g *gocui.Gui, v *gocui.View) error { av.CopyEvent(g); return nil },
+		"event.paste":            func(g *gocui.Gui, v *gocui.View) error { return av.PasteEvents(g, 1, 1) },
+		"event.paste_weekly":     func(g *gocui.Gui, v *gocui.View) error { return av.PasteEvents(g, 1, 7) },
+		"event.delete":           func(g *gocui.Gui, v *gocui.View) error { av.DeleteEvent(g); return nil },
+		"event.delete_all":       func(g *gocui.Gui, v *gocui.View) error { av.DeleteEvents(g); return nil },
+		"event.repeat":           func(g *gocui.Gui, v *gocui.View) error { return av.RepeatLastChange(g, 0) },
+		// The dispatcher runs " with the register name after it, through letteredHandlers
+		"register.select": func(g *gocui.Gui, v *gocui.View) error { return nil },
+
+		// The dispatcher runs the mark keys with the letter after them, through letteredHandlers
+		"mark.set":     func(g *gocui.Gui, v *gocui.View) error { return nil },
+		"mark.jump":    func(g *gocui.Gui, v *gocui.View) error { return nil },
+		"mark.list":    func(g *gocui.Gui, v *gocui.View) error { return av.ShowMarks(g) },
+		"jump.back":    func(g *gocui.Gui, v *gocui.View) error { av.JumpBack(g); return nil },
+		"jump.forward": func(g *gocui.Gui, v *gocui.View) error { av.JumpForward(g); return nil },
+
+		"visual.start":         func(g *gocui.Gui, v *gocui.View) error { return av.StartVisual(g) },
+		"visual.exit":          func(g *gocui.Gui, v *gocui.View) error { av.ExitVisual(); return nil },
+		"visual.delete":        func(g *gocui.Gui, v *gocui.View) error { return av.VisualDelete(g) },
+		"visual.yank":          func(g *gocui.Gui, v *gocui.View) error { return av.VisualYank(g) },
+		"visual.color":         func(g *gocui.Gui, v *gocui.View) error { return av.VisualColor(g) },
+		"visual.shift_later":   func(g *gocui.Gui, v *gocui.View) error { return av.VisualShift(g, 1, 0) },
+		"visual.shift_earlier": func(g *gocui.Gui, v *gocui.View) error { return av.VisualShift(g, -1, 0) },
+		"visual.shift_down":    func(g *gocui.Gui, v *gocui.View) error { return av.VisualShift(g, 0, 1) },
+		"visual.shift_up":      func(g *gocui.Gui, v *gocui.View) error { return av.VisualShift(g, 0, -1) },
+		"visual.duplicate":     func(g *gocui.Gui, v *gocui.View) error { return av.VisualDuplicate(g, 1) },
+
+		"grab.start":    func(g *gocui.Gui, v *gocui.View) error { return av.StartGrab(g) },
+		"grab.commit":   func(g *gocui.Gui, v *gocui.View) error { return av.CommitGrab(g) },
+		"grab.cancel":   func(g *gocui.Gui, v *gocui.View) error { return av.CancelGrab(g) },
+		"grab.down":     func(g *gocui.Gui, v *gocui.View) error { return av.GrabMove(g, 0, 1) },
+		"grab.up":       func(g *gocui.Gui, v *gocui.View) error { return av.GrabMove(g, 0, -1) },
+		"grab.next_day": func(g *gocui.Gui, v *gocui.View) error { return av.GrabMove(g, 1, 0) },
+		"grab.prev_day": func(g *gocui.Gui, v *gocui.View) error { return av.GrabMove(g, -1, 0) },
+		"grab.grow":     func(g *gocui.Gui, v *gocui.View) error { return av.GrabResize(g, 1) },
+		"grab.shrink":   func(g *gocui.Gui, v *gocui.View) error { return av.GrabResize(g, -1) },
+
+		"search.start": func(g *gocui.Gui, v *gocui.View) error { return av.StartSearch(g) },
+		"search.next":  func(g *gocui.Gui, v *gocui.View) error { av.GoToNextMatch(); av.UpdateCurrentView(g); return nil },
+		"search.prev":  func(g *gocui.Gui, v *gocui.View) error { av.GoToPrevMatch(); av.UpdateCurrentView(g); return nil },
+		"search.clear": func(g *gocui.Gui, v *gocui.View) error { av.ClearSearch(); return nil },
+		"search.find":  func(g *gocui.Gui, v *gocui.View) error { return av.ShowFinder(g) },
+
+		"filter.toggle": func(g *gocui.Gui, v *gocui.View) error { return av.ToggleFilter(g) },
+
+		"history.undo": func(g *gocui.Gui, v *gocui.View) error { return av.Undo(g) },
+		"history.redo": func(g *gocui.Gui, v *gocui.View) error { return av.Redo(g) },
+	}
+}
+
+// countedHandlers returns the actions that use the count typed before their key themselves;
+// the actions keymap.Repeats names are run count times instead
+func countedHandlers(av *views.AppView) map[string]func(*gocui.Gui, *gocui.View, int) error {
+	return map[string]func(*gocui.Gui, *gocui.View, int) error{
+		"event.delete": func(g *gocui.Gui, v *gocui.View, count int) error { av.DeleteEventsFromCursor(g, count); return nil },
+		"event.repeat": func(g *gocui.Gui, v *gocui.View, count int) error { return av.RepeatLastChange(g, count) },
+
+		// A count pastes on that many days or weeks, as a single undo step
+		"event.paste":        func(g *gocui.Gui, v *gocui.View, count int) error { return av.PasteEvents(g, max(count, 1), 1) },
+		"event.paste_weekly": func(g *gocui.Gui, v *gocui.View, count int) error { return av.PasteEvents(g, max(count, 1), 7) },
+
+		// A count shifts or duplicates the selection once, as a single undo step
+		"visual.shift_later":   func(g *gocui.Gui, v *gocui.View, count int) error { return av.VisualShift(g, max(count, 1), 0) },
+		"visual.shift_earlier": func(g *gocui.Gui, v *gocui.View, count int) error { return av.VisualShift(g, -max(count, 1), 0) },
+		"visual.shift_down":    func(g *gocui.Gui, v *gocui.View, count int) error { return av.VisualShift(g, 0, max(count, 1)) },
+		"visual.shift_up":      func(g *gocui.Gui, v *gocui.View, count int) error { return av.VisualShift(g, 0, -max(count, 1)) },
+		"visual.duplicate":     func(g *gocui.Gui, v *gocui.View, count int) error { return av.VisualDuplicate(g, count) },
+	}
+}
+
+// letteredHandlers returns the actions that take the character typed after their key
+func letteredHandlers(av *views.AppView) map[string]func(*gocui.Gui, *gocui.View, rune) error {
+	return map[string]func(*gocui.Gui, *gocui.View, rune) error{
+		"mark.set":  func(g *gocui.Gui, v *gocui.View, name rune) error { return av.SetMark(g, name) },
+		"mark.jump": func(g *gocui.Gui, v *gocui.View, name rune) error { return av.JumpToMark(g, name) },
+
+		"register.select": func(g *gocui.Gui, v *gocui.View, name rune) error { return av.SelectRegister(g, name) },
+	}
+}
+
+func initMainKeybindings(g *gocui.Gui, av *views.AppView, km *keymap.Keymap) error {
+	handlers := actionHandlers(av)
+	for _, action := range keymap.Actions {
+		if handlers[action.Name] == nil {
+			return fmt.Errorf("no handler for action %s", action.Name)
 		}
 	}
-	
-	// Set keybindings for month day views (needed for month view)
+
+	av.SetActions(handlers)
+
+	d := newDispatcher(km, handlers, countedHandlers(av), letteredHandlers(av), av)
+	if err := d.bind(g, calendarViewNames(), keymap.ScopeCalendar); err != nil {
+		return err
+	}
+	// The year view and the agenda also have keys of their own
+	if err := d.bind(g, []string{"year"}, keymap.ScopeYear); err != nil {
+		return err
+	}
+	return d.bind(g, []string{"agenda"}, keymap.ScopeAgenda)
+}
+
+// calendarViewNames lists the views that take focus while browsing the timeline and month
+// views: the day columns and the month grid cells
+func calendarViewNames() []string {
+	names := append([]string{}, views.DayColumnNames...)
 	for i := 0; i < 42; i++ {
-		viewName := fmt.Sprintf("monthday_%d", i)
-		for _, kb := range mainKeybindings {
-			if err := g.SetKeybinding(viewName, kb.key, gocui.ModNone, kb.handler); err != nil {
-				return err
-			}
+		names = append(names, fmt.Sprintf("monthday_%d", i))
+	}
+	return names
+}
+
+func initHelpKeybindings(g *gocui.Gui, av *views.AppView, km *keymap.Keymap) error {
+	closeHelp := func(g *gocui.Gui, v *gocui.View) error { return av.ShowKeybinds(g) }
+
+	// Esc and the help keys close the help; each key is bound once so it does not toggle twice
+	keys := []interface{}{gocui.KeyEsc}
+	handlers := []func(*gocui.Gui, *gocui.View) error{closeHelp}
+	for _, key := range km.Keys("app.help") {
+		if key.Mod == 0 && key.Binding() != interface{}(gocui.KeyEsc) {
+			keys = append(keys, key.Binding())
+			handlers = append(handlers, closeHelp)
 		}
 	}
-	
-	// Set keybindings for agenda event views (needed for agenda view)
-	// We'll set up a large number to handle all possible events
-	for i := 0; i < 100; i++ {
-		viewName := fmt.Sprintf("agenda_event_%d", i)
-		for _, kb := range mainKeybindings {
-			if err := g.SetKeybinding(viewName, kb.key, gocui.ModNone, kb.handler); err != nil {
-				return err
-			}
+	for _, key := range km.Keys("app.quit") {
+		if key.Mod == 0 && key.Binding() != interface{}(gocui.KeyEsc) {
+			keys = append(keys, key.Binding())
+			handlers = append(handlers, quit)
 		}
 	}
-	
-	// Set keybindings for the main agenda view
-	for _, kb := range mainKeybindings {
-		if err := g.SetKeybinding("agenda", kb.key, gocui.ModNone, kb.handler); err != nil {
+
+	for i, key := range keys {
+		if err := g.SetKeybinding("keybinds", key, gocui.ModNone, handlers[i]); err != nil {
 			return err
 		}
 	}
@@ -134,22 +216,54 @@ func initMainKeybindings(g *gocui.Gui, av *views.AppView) error {
 	return nil
 }
 
-
-func initHelpKeybindings(g *gocui.Gui, av *views.AppView) error {
-	helpKeybindings := []Keybind{
-		{gocui.KeyEsc, func(g *gocui.Gui, v *gocui.View) error { return av.ShowKeybinds(g) }},
-		{'?', func(g *gocui.Gui, v *gocui.View) error { return av.ShowKeybinds(g) }},
-		{'q', func(g *gocui.Gui, v *gocui.View) error { return quit(g, v) }},
+// initCommandLineKeybindings runs or cancels the command line; the view's editor handles the
+// other keys
+func initCommandLineKeybindings(g *gocui.Gui, av *views.AppView) error {
+	if err := g.SetKeybinding("commandline", gocui.KeyEnter, gocui.ModNone, av.ExecuteCommandLine); err != nil {
+		return err
 	}
-	for _, kb := range helpKeybindings {
-		if err := g.SetKeybinding("keybinds", kb.key, gocui.ModNone, kb.handler); err != nil {
+	return g.SetKeybinding("commandline", gocui.KeyEsc, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		return av.CloseCommandLine(g)
+	})
+}
+
+// initMarksKeybindings jumps to the mark typed while the marks are listed; Esc and the list's
+// own keys close the list
+func initMarksKeybindings(g *gocui.Gui, av *views.AppView, km *keymap.Keymap) error {
+	for ch := 'a'; ch <= 'z'; ch++ {
+		name := ch
+		if err := g.SetKeybinding("marks", ch, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+			return av.JumpToListedMark(g, name)
+		}); err != nil {
 			return err
 		}
 	}
 
+	closeMarks := func(g *gocui.Gui, v *gocui.View) error { return av.ShowMarks(g) }
+	keys := []interface{}{gocui.KeyEsc}
+	for _, key := range km.Keys("mark.list") {
+		if key.Mod == 0 && key.Binding() != interface{}(gocui.KeyEsc) && !(key.Ch >= 'a' && key.Ch <= 'z') {
+			keys = append(keys, key.Binding())
+		}
+	}
+	for _, key := range keys {
+		if err := g.SetKeybinding("marks", key, gocui.ModNone, closeMarks); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// initFinderKeybindings jumps to the event selected in the finder or closes it; the view's
+// editor handles the other keys
+func initFinderKeybindings(g *gocui.Gui, av *views.AppView) error {
+	if err := g.SetKeybinding("finder", gocui.KeyEnter, gocui.ModNone, av.JumpToFoundEvent); err != nil {
+		return err
+	}
+	return g.SetKeybinding("finder", gocui.KeyEsc, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		return av.CloseFinder(g)
+	})
+}
 
 func quit(g *gocui.Gui, v *gocui.View) error {
 	return gocui.ErrQuit
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"github.com/samuelstranges/chronos/pkg/views"
+	"github.com/jroimartin/gocui"
+	"github.com/nsf/termbox-go"
+)
+
+// modMotion is the modifier gocui passes on with the left button while the mouse moves with it
+// held down
+const modMotion = gocui.Modifier(termbox.ModMotion)
+
+// initMouseKeybindings handles the mouse on every view; the handlers leave popups and prompts
+// alone and act on the calendar views under the pointer
+func initMouseKeybindings(g *gocui.Gui, av *views.AppView) error {
+	bindings := []struct {
+		key     gocui.Key
+		mod     gocui.Modifier
+		handler func(*gocui.Gui, *gocui.View) error
+	}{
+		{gocui.MouseLeft, gocui.ModNone, av.MouseDown},
+		{gocui.MouseLeft, modMotion, av.MouseDrag},
+		{gocui.MouseRelease, gocui.ModNone, av.MouseUp},
+		{gocui.MouseWheelDown, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error { return av.MouseWheel(g, v, 1) }},
+		{gocui.MouseWheelUp, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error { return av.MouseWheel(g, v, -1) }},
+	}
+	for _, binding := range bindings {
+		if err := g.SetKeybinding("", binding.key, binding.mod, binding.handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
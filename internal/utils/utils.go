@@ -273,6 +273,15 @@ func ValidateOptionalDate(value string) bool {
 	return ValidateDate(value)
 }
 
+// ValidateOptionalSearchDate accepts the search form's dates: empty, YYYYMMDD, 't' for today or
+// 'w' for this week
+func ValidateOptionalSearchDate(value string) bool {
+	if strings.TrimSpace(value) == "w" {
+		return true
+	}
+	return ValidateOptionalDate(value)
+}
+
 func ValidateOptionalEventTime(value string) bool {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -335,3 +344,40 @@ func IsWeekday(t time.Time) bool {
 	day := t.Weekday()
 	return day >= time.Monday && day <= time.Friday
 }
+
+// WrapText word-wraps text to lines of at most width characters, keeping its line breaks. Words
+// longer than a line are split across lines of their own.
+func WrapText(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := []rune{}
+		for _, word := range strings.Fields(paragraph) {
+			runes := []rune(word)
+			for len(runes) > width {
+				if len(line) > 0 {
+					lines = append(lines, string(line))
+					line = []rune{}
+				}
+				lines = append(lines, string(runes[:width]))
+				runes = runes[width:]
+			}
+			if len(runes) == 0 {
+				continue
+			}
+			if len(line) > 0 && len(line)+1+len(runes) > width {
+				lines = append(lines, string(line))
+				line = []rune{}
+			}
+			if len(line) > 0 {
+				line = append(line, ' ')
+			}
+			line = append(line, runes...)
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
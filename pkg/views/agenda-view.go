@@ -2,24 +2,47 @@ package views
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/database"
 	"github.com/samuelstranges/chronos/internal/eventmanager"
 	"github.com/samuelstranges/chronos/internal/utils"
 	"github.com/jroimartin/gocui"
 )
 
+// AgendaRanges are the number of days the agenda can span, stepped through with +/-
+var AgendaRanges = []int{1, 7, 14, 30}
+
+// agendaRow is one line of the agenda body: a date header, an empty-day note or an event
+type agendaRow struct {
+	text       string
+	eventIndex int // index into Events, or -1 for lines that are not events
+}
+
 type AgendaView struct {
 	*BaseView
-	
+
 	Calendar       *calendar.Calendar
 	EventManager   *eventmanager.EventManager
 	CurrentDate    time.Time
 	Events         []*calendar.Event
 	SelectedIndex  int
 	HeaderHeight   int
+
+	// RangeDays is how many days the agenda lists. Ranges of whole weeks start on the calendar's
+	// first day of the week, others on the current date.
+	RangeDays int
+	// SkipEmptyDays leaves out the dates that have no events
+	SkipEmptyDays bool
+	// Search, when set, lists the search results from the current date on instead of a date range
+	Search *database.SearchCriteria
+
+	days         []calendar.AgendaDay
+	syncedDate   time.Time // the calendar date the agenda last followed
+	selectDate   time.Time // date to select an event at on the next refresh
+	scrollOffset int       // first body row shown
+	rows         []agendaRow // the body as last drawn
 }
 
 func NewAgendaView(c *calendar.Calendar, em *eventmanager.EventManager) *AgendaView {
@@ -30,9 +53,10 @@ func NewAgendaView(c *calendar.Calendar, em *eventmanager.EventManager) *AgendaV
 		CurrentDate:   c.CurrentDay.Date,
 		Events:        make([]*calendar.Event, 0),
 		SelectedIndex: 0,
-		HeaderHeight:  3, // Space for header and column titles
+		HeaderHeight:  2, // Space for column titles and separator
+		RangeDays:     7,
 	}
-	
+
 	return av
 }
 
@@ -41,7 +65,7 @@ func (av *AgendaView) Update(g *gocui.Gui) error {
 	if av.W <= 0 || av.H <= 0 {
 		return nil
 	}
-	
+
 	v, err := g.SetView(
 		av.Name,
 		av.X,
@@ -54,103 +78,184 @@ func (av *AgendaView) Update(g *gocui.Gui) error {
 			return err
 		}
 	}
-	
+
 	v.Frame = true
 	v.Clear()
-	
+
 	// Draw header
 	av.drawHeader(v)
-	
-	// Load events for current date
-	if err := av.loadEventsForDate(); err != nil {
+
+	if err := av.Refresh(); err != nil {
 		fmt.Fprintf(v, "\nError loading events: %v", err)
 		return nil
 	}
-	
-	// Calculate available space for events
-	maxEvents := av.H - av.HeaderHeight - 1 // Leave space for header and potential bottom border
-	if maxEvents < 0 {
-		maxEvents = 0
-	}
-	
-	// Determine which events to show and if overflow message is needed
-	var eventsToShow []*calendar.Event
-	var hasOverflow bool
-	
-	if len(av.Events) > maxEvents && maxEvents > 0 {
-		// More events than can fit - reserve last line for overflow message
-		eventsToShow = av.Events[:maxEvents-1]
-		hasOverflow = true
-	} else {
-		// All events fit, or no space at all
-		eventsToShow = av.Events
-		hasOverflow = false
-	}
-	
-	// Write events directly to main view with full details
-	for i, event := range eventsToShow {
-		startTime := utils.FormatHourFromTime(event.Time)
-		duration := time.Duration(event.DurationHour * float64(time.Hour))
-		endTime := event.Time.Add(duration)
-		endTimeStr := utils.FormatHourFromTime(endTime)
-		
-		// Format duration
-		durationStr := fmt.Sprintf("%.1fh", event.DurationHour)
-		if event.DurationHour == float64(int(event.DurationHour)) {
-			durationStr = fmt.Sprintf("%.0fh", event.DurationHour)
+
+	// Calculate available space for rows below the header
+	_, height := v.Size()
+	for _, line := range av.Layout(height - av.HeaderHeight) {
+		fmt.Fprintln(v, line)
+	}
+
+	// Show the position in the frame when the agenda has events
+	v.Title = ""
+	if len(av.Events) > 0 {
+		v.Title = fmt.Sprintf(" %d/%d ", av.SelectedIndex+1, len(av.Events))
+	}
+
+	return nil
+}
+
+// Layout lays out the date headers and events, scrolls to show the selected event in visibleRows
+// rows and returns the lines shown, which EventIndexAt then tells the events of
+func (av *AgendaView) Layout(visibleRows int) []string {
+	if visibleRows < 1 {
+		visibleRows = 1
+	}
+	rows, selectedRow := av.buildRows()
+	av.scrollTo(selectedRow, visibleRows, len(rows))
+	av.rows = rows
+
+	end := av.scrollOffset + visibleRows
+	if end > len(rows) {
+		end = len(rows)
+	}
+	var lines []string
+	for _, row := range rows[av.scrollOffset:end] {
+		lines = append(lines, row.text)
+	}
+	return lines
+}
+
+// scrollTo moves the window of visible rows as little as possible to show the selected row, and
+// the date header above it when the selection is the first event of its day
+func (av *AgendaView) scrollTo(selectedRow, visibleRows, totalRows int) {
+	if selectedRow >= 0 {
+		top := selectedRow
+		if top > 0 && av.Events[av.SelectedIndex] == av.firstEventOfDay(av.SelectedIndex) {
+			top--
 		}
-		
-		// Truncate fields to fit on screen with new column sizes
-		name := av.truncateField(event.Name, 20)
-		location := av.truncateField(event.Location, 37)  // 2.5x larger (15 * 2.5 ≈ 37)
-		description := av.truncateField(event.Description, 25)
-		
-		// Apply ANSI color to the event name
-		coloredName := calendar.WrapTextWithColor(name, event.Color)
-		
-		// Format the complete event line with reordered columns: Time, Event, Duration, Location, Description
-		// Note: We need to pad the colored name manually since printf can't handle ANSI codes in width calculations
-		paddedColoredName := coloredName
-		// Add padding to reach 20 characters (visible length)
-		namePadding := 20 - len(name) // Use original name length for padding calculation
-		for j := 0; j < namePadding; j++ {
-			paddedColoredName += " "
+		if top < av.scrollOffset {
+			av.scrollOffset = top
 		}
-		
-		eventLine := fmt.Sprintf(" %-11s %s %-8s %-37s %s", 
-			fmt.Sprintf("%s-%s", startTime, endTimeStr),
-			paddedColoredName,
-			durationStr,
-			location,
-			description)
-		
-		// Add selection indicator at the front
-		if i == av.SelectedIndex {
-			eventLine = fmt.Sprintf("→%s", eventLine[1:])  // Replace first space with arrow
+		if selectedRow >= av.scrollOffset+visibleRows {
+			av.scrollOffset = selectedRow - visibleRows + 1
 		}
-		
-		fmt.Fprintln(v, eventLine)
 	}
-	
-	// If there are more events than can be displayed, show count
-	if hasOverflow {
-		remaining := len(av.Events) - len(eventsToShow)
-		fmt.Fprintf(v, " & %d more events", remaining)
+
+	// Don't leave blank space below the last row
+	if av.scrollOffset > totalRows-visibleRows {
+		av.scrollOffset = totalRows - visibleRows
+	}
+	if av.scrollOffset < 0 {
+		av.scrollOffset = 0
+	}
+}
+
+// firstEventOfDay returns the first event on the same day as the event at index
+func (av *AgendaView) firstEventOfDay(index int) *calendar.Event {
+	for _, day := range av.days {
+		for _, event := range day.Events {
+			if event == av.Events[index] {
+				return day.Events[0]
+			}
+		}
 	}
-	
 	return nil
 }
 
+// buildRows lays out the date headers and events, returning the row of the selected event
+func (av *AgendaView) buildRows() ([]agendaRow, int) {
+	var rows []agendaRow
+	selectedRow := -1
+	today := time.Now()
+	index := 0
+	first, last, visual := av.visualRange()
+
+	for _, day := range av.days {
+		header := fmt.Sprintf(" \x1b[1m%s\x1b[0m", day.Date.Format("Monday, January 2"))
+		if day.Date.Year() == today.Year() && day.Date.YearDay() == today.YearDay() {
+			header += " (today)"
+		}
+		rows = append(rows, agendaRow{text: header, eventIndex: -1})
+
+		if len(day.Events) == 0 {
+			rows = append(rows, agendaRow{text: "   No events", eventIndex: -1})
+		}
+		for _, event := range day.Events {
+			if index == av.SelectedIndex {
+				selectedRow = len(rows)
+			}
+			text := av.formatEventLine(event, index == av.SelectedIndex)
+			if visual && index >= first && index <= last && index != av.SelectedIndex {
+				text = visualMarker + text[1:]
+			}
+			rows = append(rows, agendaRow{text: text, eventIndex: index})
+			index++
+		}
+	}
+
+	if len(rows) == 0 {
+		if av.Search != nil {
+			rows = append(rows, agendaRow{text: " No more search results", eventIndex: -1})
+		} else {
+			rows = append(rows, agendaRow{text: " No events", eventIndex: -1})
+		}
+	}
+
+	return rows, selectedRow
+}
+
+// formatEventLine formats an event with the columns: Time, Event, Duration, Location, Description
+func (av *AgendaView) formatEventLine(event *calendar.Event, selected bool) string {
+	startTime := utils.FormatHourFromTime(event.Time)
+	duration := time.Duration(event.DurationHour * float64(time.Hour))
+	endTime := event.Time.Add(duration)
+	endTimeStr := utils.FormatHourFromTime(endTime)
+
+	// Format duration
+	durationStr := fmt.Sprintf("%.1fh", event.DurationHour)
+	if event.DurationHour == float64(int(event.DurationHour)) {
+		durationStr = fmt.Sprintf("%.0fh", event.DurationHour)
+	}
+
+	// Truncate fields to fit on screen with new column sizes
+	name := av.truncateField(event.Name, 20)
+	location := av.truncateField(event.Location, 37)  // 2.5x larger (15 * 2.5 ≈ 37)
+	description := av.truncateField(event.Description, 25)
+
+	// Apply ANSI color to the event name
+	coloredName := calendar.WrapTextWithColor(name, event.Color)
+
+	// Note: We need to pad the colored name manually since printf can't handle ANSI codes in width calculations
+	paddedColoredName := coloredName
+	// Add padding to reach 20 characters (visible length)
+	namePadding := 20 - len(name) // Use original name length for padding calculation
+	for j := 0; j < namePadding; j++ {
+		paddedColoredName += " "
+	}
+
+	eventLine := fmt.Sprintf(" %-11s %s %-8s %-37s %s",
+		fmt.Sprintf("%s-%s", startTime, endTimeStr),
+		paddedColoredName,
+		durationStr,
+		location,
+		description)
+
+	// Add selection indicator at the front
+	if selected {
+		eventLine = fmt.Sprintf("→%s", eventLine[1:])  // Replace first space with arrow
+	}
+
+	return eventLine
+}
+
 func (av *AgendaView) drawHeader(v *gocui.View) {
-	// Line 1: Empty for spacing
-	fmt.Fprintln(v, "")
-	
-	// Line 2: Column headers - reordered: Time, Event, Duration, Location, Description
+	// Line 1: Column headers - reordered: Time, Event, Duration, Location, Description
 	// Add space at start for arrow positioning
-	fmt.Fprintf(v, " %-11s %-20s %-8s %-37s %s\n", 
+	fmt.Fprintf(v, " %-11s %-20s %-8s %-37s %s\n",
 		"Time", "Event", "Duration", "Location", "Description")
-	
-	// Line 3: Separator
+
+	// Line 2: Separator
 	separator := " "  // Start with space for arrow positioning
 	for i := 0; i < av.W-3; i++ {
 		separator += "-"
@@ -158,28 +263,30 @@ func (av *AgendaView) drawHeader(v *gocui.View) {
 	fmt.Fprintln(v, separator)
 }
 
-func (av *AgendaView) loadEventsForDate() error {
-	// Get events for the current date
-	events, err := av.EventManager.GetEventsByDate(av.CurrentDate)
-	if err != nil {
+// RangeStart returns midnight on the first day listed by the agenda
+func (av *AgendaView) RangeStart() time.Time {
+	if av.Search == nil && av.RangeDays%7 == 0 {
+		return calendar.StartOfWeek(av.CurrentDate, av.Calendar.WeekStart)
+	}
+	return time.Date(av.CurrentDate.Year(), av.CurrentDate.Month(), av.CurrentDate.Day(), 0, 0, 0, 0, av.CurrentDate.Location())
+}
+
+// Refresh follows the calendar to a new date if it moved elsewhere and reloads the events
+func (av *AgendaView) Refresh() error {
+	if !av.Calendar.CurrentDay.Date.Equal(av.syncedDate) {
+		av.SetCurrentDate(av.Calendar.CurrentDay.Date)
+	}
+
+	if err := av.loadEvents(); err != nil {
 		return err
 	}
-	
-	// Convert UTC events to local time for display
-	localEvents := make([]*calendar.Event, len(events))
-	for i, event := range events {
-		localEvent := *event
-		localEvent.Time = event.Time.In(time.Local)
-		localEvents[i] = &localEvent
-	}
-	
-	// Sort events by time
-	sort.Slice(localEvents, func(i, j int) bool {
-		return localEvents[i].Time.Before(localEvents[j].Time)
-	})
-	
-	av.Events = localEvents
-	
+
+	// Select the event at the new date, else the first one on or after its day
+	if !av.selectDate.IsZero() {
+		av.SelectedIndex = av.indexAtDate(av.selectDate)
+		av.selectDate = time.Time{}
+	}
+
 	// Ensure selected index is valid
 	if av.SelectedIndex >= len(av.Events) {
 		av.SelectedIndex = len(av.Events) - 1
@@ -187,73 +294,162 @@ func (av *AgendaView) loadEventsForDate() error {
 	if av.SelectedIndex < 0 {
 		av.SelectedIndex = 0
 	}
-	
+
 	return nil
 }
 
-func (av *AgendaView) updateEventViews(g *gocui.Gui) error {
-	// Clear existing event views
-	av.clearEventViews(g)
-	
-	// Create event views for each event
-	for i, event := range av.Events {
-		eventViewName := fmt.Sprintf("agenda_event_%d", i)
-		
-		// Calculate position relative to parent view (not screen coordinates)
-		// The event views should be positioned within the main agenda view
-		x := av.X + 1  // 1 pixel from left edge of parent
-		y := av.Y + av.HeaderHeight + i  // After header + event index
-		w := av.W - 2  // 2 pixels narrower than parent (1 pixel margin on each side)
-		h := 1         // 1 line height per event
-		
-		// Skip if outside view bounds
-		if y >= av.Y+av.H-1 {
-			break
+func (av *AgendaView) loadEvents() error {
+	start := av.RangeStart()
+
+	var events []*calendar.Event
+	var err error
+	if av.Search != nil {
+		events, err = av.EventManager.SearchEventsWithFilters(*av.Search)
+	} else {
+		events, err = av.EventManager.GetEventsByDateRange(start, start.AddDate(0, 0, av.RangeDays))
+	}
+	if err != nil {
+		return err
+	}
+	events = av.Calendar.Filtered(events)
+
+	// Convert UTC events to local time for display
+	localEvents := make([]*calendar.Event, 0, len(events))
+	for _, event := range events {
+		localEvent := *event
+		localEvent.Time = event.Time.In(time.Local)
+		// Search results before the current date have already been passed
+		if av.Search != nil && localEvent.Time.Before(start) {
+			continue
 		}
-		
-		// Create event view
-		eventView := NewAgendaEventView(eventViewName, event)
-		eventView.SetProperties(x, y, w, h)
-		eventView.SetSelected(i == av.SelectedIndex)
-		
-		av.AddChild(eventViewName, eventView)
-	}
-	
+		localEvents = append(localEvents, &localEvent)
+	}
+
+	if av.Search != nil {
+		av.days = calendar.GroupEventsByDay(localEvents, start, 0, true)
+	} else {
+		av.days = calendar.GroupEventsByDay(localEvents, start, av.RangeDays, av.SkipEmptyDays)
+	}
+
+	// Events are listed in agenda order, which the selection index follows
+	av.Events = av.Events[:0]
+	for _, day := range av.days {
+		av.Events = append(av.Events, day.Events...)
+	}
+
 	return nil
 }
 
-func (av *AgendaView) clearEventViews(g *gocui.Gui) {
-	// Remove all agenda_event_* views
-	childrenToRemove := make([]string, 0)
-	
-	for pair := av.children.Oldest(); pair != nil; pair = pair.Next() {
-		viewName := pair.Key
-		if len(viewName) >= 13 && viewName[:13] == "agenda_event_" {
-			childrenToRemove = append(childrenToRemove, viewName)
-			g.DeleteView(viewName)
+// indexAtDate returns the index of the event starting exactly at date, else of the first event on
+// or after date's day, else of the last event
+func (av *AgendaView) indexAtDate(date time.Time) int {
+	for i, event := range av.Events {
+		if event.Time.Equal(date) {
+			return i
 		}
 	}
-	
-	for _, viewName := range childrenToRemove {
-		av.children.Delete(viewName)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	for i, event := range av.Events {
+		if !event.Time.Before(day) {
+			return i
+		}
 	}
+	return len(av.Events) - 1
+}
+
+// visualMarker marks the rows visual mode selects besides the selected one
+const visualMarker = "┃"
+
+// visualRange returns the indexes of the first and last events selected in visual mode, from the
+// event at the anchor to the selected event
+func (av *AgendaView) visualRange() (int, int, bool) {
+	if av.Calendar.VisualAnchor == nil || len(av.Events) == 0 {
+		return 0, 0, false
+	}
+	first, last := av.indexAtDate(*av.Calendar.VisualAnchor), av.SelectedIndex
+	if last < first {
+		first, last = last, first
+	}
+	return first, last, true
+}
+
+// VisualEvents returns the events selected in visual mode
+func (av *AgendaView) VisualEvents() []*calendar.Event {
+	first, last, ok := av.visualRange()
+	if !ok {
+		return nil
+	}
+	return av.Events[first : last+1]
 }
 
 func (av *AgendaView) MoveSelection(direction int) {
 	if len(av.Events) == 0 {
 		return
 	}
-	
+
 	newIndex := av.SelectedIndex + direction
-	
+
 	// Bounds checking
 	if newIndex < 0 {
 		newIndex = 0
 	} else if newIndex >= len(av.Events) {
 		newIndex = len(av.Events) - 1
 	}
-	
+
 	av.SelectedIndex = newIndex
+
+	// The calendar follows the selection, so other views and new events use its date, without
+	// moving the agenda's range
+	av.Calendar.CurrentDay.Date = av.Events[newIndex].Time
+	av.Calendar.UpdateWeek()
+	av.syncedDate = av.Calendar.CurrentDay.Date
+}
+
+// EventIndexAt returns the index into Events of the event drawn on a line of the view
+func (av *AgendaView) EventIndexAt(line int) (int, bool) {
+	row := line - av.HeaderHeight + av.scrollOffset
+	if line < av.HeaderHeight || row >= len(av.rows) || av.rows[row].eventIndex < 0 {
+		return 0, false
+	}
+	return av.rows[row].eventIndex, true
+}
+
+// StepRange switches to the next longer (step > 0) or shorter (step < 0) of the AgendaRanges
+func (av *AgendaView) StepRange(step int) {
+	current := 0
+	for i, days := range AgendaRanges {
+		if days <= av.RangeDays {
+			current = i
+		}
+	}
+	next := current + step
+	if next < 0 {
+		next = 0
+	} else if next >= len(AgendaRanges) {
+		next = len(AgendaRanges) - 1
+	}
+	av.RangeDays = AgendaRanges[next]
+	av.keepSelection()
+}
+
+// ToggleSkipEmptyDays shows or hides the dates without events
+func (av *AgendaView) ToggleSkipEmptyDays() {
+	av.SkipEmptyDays = !av.SkipEmptyDays
+}
+
+// SetSearch lists the given search results instead of a date range, or the range again when nil
+func (av *AgendaView) SetSearch(criteria *database.SearchCriteria) {
+	av.Search = criteria
+	av.keepSelection()
+}
+
+// keepSelection reselects the selected event after the listed events change
+func (av *AgendaView) keepSelection() {
+	if event := av.GetSelectedEvent(); event != nil {
+		av.selectDate = event.Time
+	} else {
+		av.selectDate = av.CurrentDate
+	}
 }
 
 func (av *AgendaView) GetSelectedEvent() *calendar.Event {
@@ -263,9 +459,11 @@ func (av *AgendaView) GetSelectedEvent() *calendar.Event {
 	return nil
 }
 
+// SetCurrentDate moves the agenda's range to date and selects the event there on the next refresh
 func (av *AgendaView) SetCurrentDate(date time.Time) {
 	av.CurrentDate = date
-	av.SelectedIndex = 0 // Reset selection when date changes
+	av.syncedDate = date
+	av.selectDate = date
 }
 
 func (av *AgendaView) GetSelectedEventViewName() string {
@@ -278,9 +476,9 @@ func (av *AgendaView) truncateField(text string, maxWidth int) string {
 	if len(text) <= maxWidth {
 		return text
 	}
-	
+
 	if maxWidth > 3 {
 		return text[:maxWidth-3] + "..."
 	}
 	return text[:maxWidth]
-}
\ No newline at end of file
+}
@@ -0,0 +1,440 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/command"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/ics"
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/samuelstranges/chronos/internal/quickadd"
+	"github.com/jroimartin/gocui"
+)
+
+// SetActions sets what every keymap action does, so the command line can run actions by name
+func (av *AppView) SetActions(actions map[string]func(*gocui.Gui, *gocui.View) error) {
+	av.actions = actions
+}
+
+// commandLine returns the ':' prompt
+func (av *AppView) commandLine() *CommandLineView {
+	if view, ok := av.GetChild("commandline"); ok {
+		if clv, ok := view.(*CommandLineView); ok {
+			return clv
+		}
+	}
+	return nil
+}
+
+// ShowCommandLine opens the ':' prompt
+func (av *AppView) ShowCommandLine(g *gocui.Gui) error {
+	if clv := av.commandLine(); clv != nil {
+		clv.Open()
+		return av.focusCommandLine(g, clv)
+	}
+	return nil
+}
+
+// CloseCommandLine closes the ':' prompt without running the line
+func (av *AppView) CloseCommandLine(g *gocui.Gui) error {
+	if clv := av.commandLine(); clv != nil {
+		clv.Close("")
+		return av.focusCommandLine(g, clv)
+	}
+	return nil
+}
+
+// focusCommandLine draws the prompt and moves the focus to or from it straight away, as keys
+// typed before the next layout go to the focused view
+func (av *AppView) focusCommandLine(g *gocui.Gui, clv *CommandLineView) error {
+	if err := clv.Update(g); err != nil {
+		return err
+	}
+	return av.UpdateCurrentView(g)
+}
+
+// ExecuteCommandLine closes the ':' prompt and runs the line typed in it
+func (av *AppView) ExecuteCommandLine(g *gocui.Gui, v *gocui.View) error {
+	clv := av.commandLine()
+	if clv == nil {
+		return nil
+	}
+	line := CommandLineText(v)
+	clv.Close(line)
+	if err := av.ExecuteCommand(g, line); err != nil {
+		return err
+	}
+	return av.focusCommandLine(g, clv)
+}
+
+// ShowPendingKeys shows the keys typed towards an action, a count or a mark key, at the right of
+// the bottom row, or hides them for ""
+func (av *AppView) ShowPendingKeys(keys string) {
+	if clv := av.commandLine(); clv != nil {
+		clv.Pending = av.pendingRegister() + keys
+	}
+}
+
+// DismissCommandOutput hides the one-line output of the last command
+func (av *AppView) DismissCommandOutput() {
+	if clv := av.commandLine(); clv != nil {
+		clv.DismissOutput()
+	}
+}
+
+// showCommandOutput shows what a command printed below the calendar
+func (av *AppView) showCommandOutput(lines ...string) {
+	if clv := av.commandLine(); clv != nil {
+		clv.SetOutput(lines, false)
+	}
+}
+
+// showCommandError shows why a command failed below the calendar
+func (av *AppView) showCommandError(err error) {
+	if clv := av.commandLine(); clv != nil {
+		clv.SetOutput([]string{err.Error()}, true)
+	}
+}
+
+// ExecuteCommand runs a command line such as "goto 2026-11-03 14:00" or the name of a keymap
+// action. Mistakes in the line are shown below the calendar rather than returned.
+func (av *AppView) ExecuteCommand(g *gocui.Gui, line string) error {
+	name, args := command.Split(line)
+	if name == "" {
+		return nil
+	}
+
+	// Commands act on the calendar view, which the prompt took the focus from
+	av.UpdateCurrentView(g)
+
+	if _, ok := keymap.FindAction(name); ok {
+		if len(args) > 0 {
+			av.showCommandError(fmt.Errorf("%s takes no arguments", name))
+			return nil
+		}
+		if handler := av.actions[name]; handler != nil {
+			return handler(g, g.CurrentView())
+		}
+		return nil
+	}
+
+	cmd, err := command.Find(name)
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+
+	switch cmd.Name {
+	case "goto":
+		err = av.gotoCommand(g, args)
+	case "add":
+		err = av.addCommand(g, args)
+	case "delete":
+		err = av.deleteCommand(g, args)
+	case "color":
+		err = av.colorCommand(g, args)
+	case "export":
+		err = av.exportCommand(args)
+	case "set":
+		err = av.setCommand(g, args)
+	case "view":
+		err = av.viewCommand(g, args)
+	case "mark":
+		err = av.markCommand(g, args)
+	case "marks":
+		err = av.ShowMarks(g)
+	case "registers":
+		err = av.registersCommand(args)
+	case "filter":
+		err = av.filterCommand(g, args)
+	case "help":
+		av.showCommandOutput(commandHelp()...)
+	case "quit":
+		return gocui.ErrQuit
+	}
+
+	if err != nil {
+		av.showCommandError(err)
+	}
+	return nil
+}
+
+// commandHelp lists the commands for :help
+func commandHelp() []string {
+	lines := []string{"Commands (Tab completes, ↑↓ recall earlier lines):"}
+	for _, cmd := range command.Commands {
+		lines = append(lines, fmt.Sprintf("  :%-22s %s", strings.TrimSpace(cmd.Name+" "+cmd.Usage), cmd.Description))
+	}
+	lines = append(lines, fmt.Sprintf("  :%-22s %s", "ACTION", "Run a keymap action, e.g. :event.duration"))
+	return lines
+}
+
+// jumpTo selects a date and time, showing it in the current view
+func (av *AppView) jumpTo(g *gocui.Gui, t time.Time) {
+	oldMonth := av.Calendar.CurrentDay.Date.Month()
+	av.Calendar.CurrentDay.Date = t
+	av.Calendar.UpdateWeek()
+	if av.IsMonthMode() {
+		av.handleMonthChange(g, oldMonth)
+	} else if av.IsAgendaMode() {
+		av.updateAgendaDate()
+	}
+	av.UpdateCurrentView(g)
+}
+
+func (av *AppView) markCommand(g *gocui.Gui, args []string) error {
+	if len(args) != 1 || len(args[0]) != 1 || !isMarkName(rune(args[0][0])) {
+		return fmt.Errorf("usage: mark LETTER (a-z)")
+	}
+	return av.SetMark(g, rune(args[0][0]))
+}
+
+func (av *AppView) gotoCommand(g *gocui.Gui, args []string) error {
+	t, err := command.ParseGoto(args, av.Calendar.CurrentDay.Date)
+	if err != nil {
+		return err
+	}
+	av.jumpFrom(g, t)
+	return nil
+}
+
+// addCommand adds the events of a quick add phrase. A phrase with a time but no date is on the
+// selected day.
+func (av *AppView) addCommand(g *gocui.Gui, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: add PHRASE")
+	}
+
+	day := av.Calendar.CurrentDay.Date
+	parser := quickadd.NewParser(time.Now(), day, config.GetDefaultEventLength(av.Config))
+	result, err := parser.Parse(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if result.HasTime && !result.HasDate {
+		start := result.Start
+		result.Start = time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, day.Location())
+	}
+	if result.Color == 0 {
+		if color := config.GetDefaultColor(av.Config); color != "" {
+			result.Color = calendar.ColorNameToAttribute(color)
+		}
+	}
+
+	events := result.Event().GetReccuringEvents()
+	if view, ok := av.GetChild("popup"); ok {
+		if popupView, ok := view.(*EventPopupView); ok {
+			// Events that overlap get the conflict dialog instead of an error
+			if shown, err := popupView.ShowAddConflicts(g, events); shown || err != nil {
+				return err
+			}
+		}
+	}
+
+	// A series is added as one change, or not at all when an occurrence is refused
+	var ok bool
+	_, message := av.EventManager.CaptureErrors(func() {
+		_, ok = av.EventManager.AddEvents(events, fmt.Sprintf("add %d %s", len(events), pluralEvents(len(events))))
+	})
+	if !ok {
+		av.showCommandError(errors.New(message))
+		return nil
+	}
+
+	av.jumpTo(g, result.Start)
+	if message != "" {
+		// Overlaps allowed with a warning
+		av.showCommandOutput("Added "+result.Describe(), message)
+		return nil
+	}
+	av.showCommandOutput("Added " + result.Describe())
+	return nil
+}
+
+// selectedEvent returns the event under the cursor
+func (av *AppView) selectedEvent(g *gocui.Gui) (*calendar.Event, error) {
+	if eventView, ok := av.GetHoveredOnView(g).(*EventView); ok && eventView.Event != nil {
+		return eventView.Event, nil
+	}
+	return nil, fmt.Errorf("no event selected")
+}
+
+func (av *AppView) deleteCommand(g *gocui.Gui, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: delete")
+	}
+	if _, err := av.selectedEvent(g); err != nil {
+		return err
+	}
+	av.DeleteEvent(g)
+	return nil
+}
+
+func (av *AppView) colorCommand(g *gocui.Gui, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: color COLOR")
+	}
+	color, err := command.ParseColor(args[0])
+	if err != nil {
+		return err
+	}
+	event, err := av.selectedEvent(g)
+	if err != nil {
+		return err
+	}
+
+	updated := *event
+	updated.Color = color
+	if av.EventManager.UpdateEvent(event.Id, &updated) {
+		av.EventManager.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangeColor, ColorName: calendar.ColorAttributeToName(color)})
+	}
+	return nil
+}
+
+// exportCommand writes every event to an iCalendar file, expanding a leading ~ in the path
+func (av *AppView) exportCommand(args []string) error {
+	if len(args) < 2 || strings.ToLower(args[0]) != "ics" {
+		return fmt.Errorf("usage: export ics PATH")
+	}
+	path := strings.Join(args[1:], " ")
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	events, err := av.Database.GetAllEvents()
+	if err != nil {
+		return err
+	}
+	if err := ics.NewICSExporter().ExportToFile(events, path); err != nil {
+		return err
+	}
+	av.showCommandOutput(fmt.Sprintf("Exported %d events to %s", len(events), path))
+	return nil
+}
+
+// setCommand changes a setting until chronos exits, shows one, or lists them all
+func (av *AppView) setCommand(g *gocui.Gui, args []string) error {
+	if len(args) == 0 {
+		lines := []string{"Settings (until chronos exits):"}
+		for _, setting := range command.Settings {
+			lines = append(lines, fmt.Sprintf("  %-29s %s", setting.Name+"="+av.settingValue(setting.Name), setting.Description))
+		}
+		av.showCommandOutput(lines...)
+		return nil
+	}
+
+	setting, value, err := command.ParseSetting(args)
+	if err != nil {
+		return err
+	}
+	if value != "" {
+		av.applySetting(g, setting.Name, value)
+	}
+	av.showCommandOutput(setting.Name + "=" + av.settingValue(setting.Name))
+	return nil
+}
+
+// settingValue returns the value a :set option has
+func (av *AppView) settingValue(name string) string {
+	switch name {
+	case "week_start":
+		return strings.ToLower(av.Calendar.WeekStart.String())
+	case "overlap_policy":
+		return string(av.EventManager.GetOverlapPolicy())
+	case "default_color":
+		if color := config.GetDefaultColor(av.Config); color != "" {
+			return strings.ToLower(color)
+		}
+		return "auto"
+	case "default_event_length":
+		return strconv.FormatFloat(config.GetDefaultEventLength(av.Config), 'f', -1, 64)
+	case "detail_pane":
+		if detail := av.detailView(); detail != nil {
+			return detail.Position
+		}
+	case "agenda_skip_empty_days":
+		if agenda := av.agendaView(); agenda != nil {
+			return strconv.FormatBool(agenda.SkipEmptyDays)
+		}
+	}
+	return ""
+}
+
+// applySetting changes a :set option, in the config and in the views that read it at startup
+func (av *AppView) applySetting(g *gocui.Gui, name, value string) {
+	switch name {
+	case "week_start":
+		av.Config.WeekStart = value
+		av.Calendar.SetWeekStart(config.GetWeekStart(av.Config))
+		// The month grid starts on the first day of the week
+		if mainView, ok := av.GetChild("main"); ok {
+			if mv, ok := mainView.(*MainView); ok && mv.CalendarView != nil && mv.CalendarView.MonthView != nil {
+				mv.CalendarView.MonthView.SetWeeks(mv.CalendarView.MonthView.Weeks)
+			}
+		}
+	case "overlap_policy":
+		av.Config.OverlapPolicy = value
+		av.EventManager.SetOverlapPolicy(eventmanager.OverlapPolicy(value))
+	case "default_color":
+		av.Config.DefaultColor = ""
+		if color, err := command.ParseColor(value); err == nil {
+			av.Config.DefaultColor = calendar.ColorAttributeToName(color)
+		}
+	case "default_event_length":
+		av.Config.DefaultEventLength, _ = strconv.ParseFloat(value, 64)
+	case "detail_pane":
+		av.Config.DetailPane = value
+		if detail := av.detailView(); detail != nil {
+			detail.Position = value
+		}
+	case "agenda_skip_empty_days":
+		av.Config.AgendaSkipEmptyDays = value == "true"
+		if agenda := av.agendaView(); agenda != nil {
+			agenda.SkipEmptyDays = av.Config.AgendaSkipEmptyDays
+		}
+	}
+	av.UpdateCurrentView(g)
+}
+
+func (av *AppView) viewCommand(g *gocui.Gui, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: view NAME")
+	}
+
+	var err error
+	switch strings.ToLower(args[0]) {
+	case "week":
+		err = av.SwitchToWeekView(g)
+	case "day":
+		err = av.SwitchToDayView(g)
+	case "days":
+		err = av.SwitchToDaysView(g)
+	case "month":
+		err = av.SwitchToMonthView(g)
+	case "weeks":
+		err = av.SwitchToWeeksView(g)
+	case "year":
+		err = av.SwitchToYearView(g)
+	case "agenda":
+		err = av.SwitchToAgendaView(g)
+	default:
+		return fmt.Errorf("unknown view %q (%s)", args[0], strings.Join(command.ViewNames, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	av.UpdateCurrentView(g)
+	return nil
+}
@@ -1,25 +1,63 @@
 package views
 
 import (
-	"fmt"
-	"os"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/freetime"
 	"github.com/jroimartin/gocui"
 )
 
 // DeleteEvent deletes a single event at the cursor position
 func (av *AppView) DeleteEvent(g *gocui.Gui) {
+	av.DeleteEventsFromCursor(g, 1)
+}
+
+// DeleteEventsFromCursor deletes the event at the cursor and the events after it on the same
+// day, count in all, as a single undo step
+func (av *AppView) DeleteEventsFromCursor(g *gocui.Gui, count int) {
 	hoveredView := av.GetHoveredOnView(g)
-	if eventView, ok := hoveredView.(*EventView); ok {
-		// Copy event to yank buffer before deleting (vim-like behavior)
-		copiedEvent := *eventView.Event
-		av.copiedEvent = &copiedEvent
-		
-		// Delete the event
-		av.EventManager.DeleteEvent(eventView.Event.Id)
+	eventView, ok := hoveredView.(*EventView)
+	if !ok {
+		return
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	deleted := []*calendar.Event{eventView.Event}
+	if count > 1 {
+		events := av.getLocalEventsInOrder()
+		for i, event := range events {
+			if event.Id != eventView.Event.Id {
+				continue
+			}
+			for _, next := range events[i+1:] {
+				if len(deleted) == count || !isSameDay(next.Time, event.Time) {
+					break
+				}
+				deleted = append(deleted, next)
+			}
+			break
+		}
+	}
+
+	// Copy the events to the yank history before deleting (vim-like behavior)
+	if !av.yankEvents(deleted) {
+		return
+	}
+
+	ids := make([]int, len(deleted))
+	for i, event := range deleted {
+		ids[i] = event.Id
 	}
+	if err := av.EventManager.DeleteEvents(ids); err != nil {
+		return
+	}
+	av.EventManager.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangeDelete, Count: count})
 }
 
 // DeleteEvents deletes all events with the same name as the event at cursor position
@@ -46,8 +84,75 @@ func (av *AppView) ShowNewEventPopup(g *gocui.Gui) error {
 	return nil
 }
 
+// ShowFreeSlotPopup displays the free slot finder; picking a slot moves the cursor there and
+// opens the new event form with the searched-for duration
+func (av *AppView) ShowFreeSlotPopup(g *gocui.Gui) error {
+	if view, ok := av.GetChild("popup"); ok {
+		if popupView, ok := view.(*EventPopupView); ok {
+			popupView.FreeSlotCallback = func(slot freetime.Slot, duration time.Duration) error {
+				return av.addEventInFreeSlot(g, slot, duration)
+			}
+
+			view.SetProperties(
+				av.X+(av.W-PopupWidth)/2,
+				av.Y+(av.H-PopupHeight)/2,
+				PopupWidth,
+				PopupHeight,
+			)
+			return popupView.ShowFreeSlotPopup(g)
+		}
+	}
+	return nil
+}
+
+// addEventInFreeSlot jumps to the start of a free slot and opens the new event form there
+func (av *AppView) addEventInFreeSlot(g *gocui.Gui, slot freetime.Slot, duration time.Duration) error {
+	av.jumpTo(g, slot.Start)
+
+	// Event durations are stored in half hours
+	hours := math.Ceil(duration.Hours()*2) / 2
+
+	if view, ok := av.GetChild("popup"); ok {
+		if popupView, ok := view.(*EventPopupView); ok {
+			view.SetProperties(
+				av.X+(av.W-PopupWidth)/2,
+				av.Y+(av.H-PopupHeight)/2,
+				PopupWidth,
+				PopupHeight,
+			)
+			return popupView.ShowNewEventPopupWithDuration(g, hours)
+		}
+	}
+	return nil
+}
+
+// ShowQuickAddPopup displays the natural-language quick add prompt
+func (av *AppView) ShowQuickAddPopup(g *gocui.Gui) error {
+	if view, ok := av.GetChild("popup"); ok {
+		if popupView, ok := view.(*EventPopupView); ok {
+			view.SetProperties(
+				av.X+(av.W-QuickAddWidth)/2,
+				av.Y+(av.H-PopupHeight)/2,
+				QuickAddWidth,
+				PopupHeight,
+			)
+			return popupView.ShowQuickAddPopup(g)
+		}
+	}
+	return nil
+}
+
 // ShowEditEventPopup displays the edit event popup for the event at cursor position
 func (av *AppView) ShowEditEventPopup(g *gocui.Gui) error {
+	hoveredView := av.GetHoveredOnView(g)
+	if eventView, ok := hoveredView.(*EventView); ok {
+		return av.showEditPopupFor(g, eventView)
+	}
+	return nil
+}
+
+// showEditPopupFor displays the edit event popup for an event view
+func (av *AppView) showEditPopupFor(g *gocui.Gui, eventView *EventView) error {
 	if view, ok := av.GetChild("popup"); ok {
 		if popupView, ok := view.(*EventPopupView); ok {
 			view.SetProperties(
@@ -56,13 +161,7 @@ func (av *AppView) ShowEditEventPopup(g *gocui.Gui) error {
 				PopupWidth,
 				PopupHeight,
 			)
-			hoveredView := av.GetHoveredOnView(g)
-			if eventView, ok := hoveredView.(*EventView); ok {
-				err := popupView.ShowEditEventPopup(g, eventView)
-				if err != nil {
-					return err
-				}
-			}
+			return popupView.ShowEditEventPopup(g, eventView)
 		}
 	}
 	return nil
@@ -90,6 +189,7 @@ func (av *AppView) ShowColorPicker(g *gocui.Gui) error {
 						// Error is handled by EventManager internally
 						return nil
 					}
+					av.EventManager.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangeColor, ColorName: colorName})
 					av.CloseColorPicker(g)
 					return nil
 				}
@@ -128,6 +228,7 @@ func (av *AppView) ShowDurationPopup(g *gocui.Gui) error {
 						// Error is handled by EventManager internally
 						return nil
 					}
+					av.EventManager.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangeDuration, Duration: duration})
 					av.CloseDurationPopup(g)
 					return nil
 				}
@@ -169,116 +270,42 @@ func (av *AppView) CloseDurationPopup(g *gocui.Gui) error {
 	return nil
 }
 
-// CopyEvent copies the event at cursor position to the yank buffer
-func (av *AppView) CopyEvent(g *gocui.Gui) {
-	_, y := g.CurrentView().Cursor()
-
-	if view, ok := av.FindChildView(WeekdayNames[av.Calendar.CurrentDay.Date.Weekday()]); ok {
-		if dayView, ok := view.(*DayView); ok {
-			if eventView, ok := dayView.IsOnEvent(y); ok {
-				// Create a copy of the event
-				copiedEvent := *eventView.Event
-				av.copiedEvent = &copiedEvent
-			}
-		}
-	}
-}
-
-// PasteEvent pastes the copied event to the current day/time
-func (av *AppView) PasteEvent(g *gocui.Gui) error {
-	if av.copiedEvent == nil {
-		return nil // Nothing to paste
+// RepeatLastChange makes the last paste, duration change, colour change or delete again at the
+// cursor (vim's '.'). A count replaces the number of events a delete removes or of copies a
+// paste makes.
+func (av *AppView) RepeatLastChange(g *gocui.Gui, count int) error {
+	change, ok := av.EventManager.LastChange()
+	if !ok {
+		return nil
 	}
 
-	var debugInfo string
-	if av.DebugMode {
-		// DEBUGGING: Let's see what's happening
-		currentView := g.CurrentView()
-		currentViewName := ""
-		if currentView != nil {
-			currentViewName = currentView.Name()
+	switch change.Kind {
+	case eventmanager.ChangePaste:
+		if count == 0 {
+			count = change.Count
 		}
-		
-		calendarWeekday := av.Calendar.CurrentDay.Date.Weekday()
-		calendarWeekdayName := WeekdayNames[calendarWeekday]
-		calendarDate := av.Calendar.CurrentDay.Date
-		
-		// Print debug info to a file
-		debugInfo = fmt.Sprintf("PASTE DEBUG:\n")
-		debugInfo += fmt.Sprintf("  Current View Name: %s\n", currentViewName)
-		debugInfo += fmt.Sprintf("  Calendar Weekday(): %d (%s)\n", calendarWeekday, calendarWeekdayName)
-		debugInfo += fmt.Sprintf("  Calendar Date: %s\n", calendarDate.Format("2006-01-02 15:04:05"))
-		debugInfo += fmt.Sprintf("  Calendar Date Weekday: %s\n", calendarDate.Weekday().String())
-		
-		// Print all week days for reference
-		debugInfo += fmt.Sprintf("  Week Days:\n")
-		for i, day := range av.Calendar.CurrentWeek.Days {
-			debugInfo += fmt.Sprintf("    [%d] %s: %s\n", i, WeekdayNames[i], day.Date.Format("2006-01-02"))
+		return av.paste(g, change.Events, count, change.EveryDays)
+	case eventmanager.ChangeDelete:
+		if count == 0 {
+			count = change.Count
 		}
+		av.DeleteEventsFromCursor(g, count)
+		return nil
 	}
 
-	// Use the exact same logic as DeleteEvent
-	if view, ok := av.FindChildView(WeekdayNames[av.Calendar.CurrentDay.Date.Weekday()]); ok {
-		if _, ok := view.(*DayView); ok {
-			// Create a new event based on the copied one
-			newEvent := *av.copiedEvent
-			newEvent.Id = 0 // Reset ID so database will assign a new one
-			
-			// DEBUG: Check current view vs calendar date
-			currentView := g.CurrentView()
-			currentViewName := ""
-			if currentView != nil {
-				currentViewName = currentView.Name()
-			}
-			
-			// Get the actual date from the current view name
-			var targetDate time.Time
-			for i, dayName := range WeekdayNames {
-				if dayName == currentViewName {
-					// Found the matching day, get the date from the week
-					if i < len(av.Calendar.CurrentWeek.Days) {
-						targetDate = av.Calendar.CurrentWeek.Days[i].Date
-						break
-					}
-				}
-			}
-			
-			// If we couldn't determine target date, fall back to calendar current day
-			if targetDate.IsZero() {
-				targetDate = av.Calendar.CurrentDay.Date
-			}
-			
-			// Use the target date with current time
-			newEvent.Time = targetDate
-			
-			if av.DebugMode {
-				// Add final time to debug
-				finalDebug := fmt.Sprintf("\nDEBUG CALENDAR vs VIEW:\n")
-				finalDebug += fmt.Sprintf("  Current View: %s\n", currentViewName)
-				finalDebug += fmt.Sprintf("  Calendar.CurrentDay.Date: %s\n", av.Calendar.CurrentDay.Date.Format("2006-01-02 15:04:05"))
-				finalDebug += fmt.Sprintf("  Target Date from View: %s\n", targetDate.Format("2006-01-02 15:04:05"))
-				finalDebug += fmt.Sprintf("  FINAL EVENT TIME: %s\n", newEvent.Time.Format("2006-01-02 15:04:05"))
-				os.WriteFile("/tmp/chronos_debug.txt", []byte(debugInfo + finalDebug), 0644)
-			}
-
-			// Add to database
-			if _, success := av.EventManager.AddEvent(newEvent); !success {
-				// Error is handled by EventManager internally
-				return nil
-			}
-
-			// Refresh events from database to ensure proper display
-			if err := av.updateEventsFromDatabase(); err != nil {
-				return err
-			}
-
-			// Force a view update to refresh the display
-			if err := av.UpdateChildren(g); err != nil {
-				return err
-			}
-		}
+	eventView, ok := av.GetHoveredOnView(g).(*EventView)
+	if !ok || eventView.Event == nil {
+		return nil
+	}
+	updated := *eventView.Event
+	if change.Kind == eventmanager.ChangeDuration {
+		updated.DurationHour = change.Duration
+	} else {
+		updated.Color = calendar.ColorNameToAttribute(change.ColorName)
+	}
+	if av.EventManager.UpdateEvent(updated.Id, &updated) {
+		av.EventManager.RememberChange(change)
 	}
-
 	return nil
 }
 
@@ -302,4 +329,57 @@ func (av *AppView) Redo(g *gocui.Gui) error {
 	}
 	
 	return err
-}
\ No newline at end of file
+}
+// EditDescriptionInEditor opens the description of the event at the cursor in $EDITOR and saves
+// the edited text as an undoable change
+func (av *AppView) EditDescriptionInEditor(g *gocui.Gui) error {
+	eventView, ok := av.GetHoveredOnView(g).(*EventView)
+	if !ok || eventView.Event == nil {
+		return nil
+	}
+	event := eventView.Event
+
+	text, err := editInExternalEditor(g, event.Description, "chronos-description-*.txt")
+	if err != nil {
+		av.ShowErrorMessage(g, "Editor Error", err.Error())
+		return nil
+	}
+
+	description := strings.TrimRight(text, " \t\n")
+	if description == event.Description {
+		return nil
+	}
+
+	updated := *event
+	updated.Description = description
+	av.EventManager.UpdateEvent(event.Id, &updated)
+	return nil
+}
+
+// EditEventInEditor opens the whole event at the cursor in $EDITOR as a front matter document
+// and saves the edited fields and description as an undoable change
+func (av *AppView) EditEventInEditor(g *gocui.Gui) error {
+	eventView, ok := av.GetHoveredOnView(g).(*EventView)
+	if !ok || eventView.Event == nil {
+		return nil
+	}
+	event := eventView.Event
+
+	text, err := editInExternalEditor(g, calendar.FormatEventDocument(event), "chronos-event-*.md")
+	if err != nil {
+		av.ShowErrorMessage(g, "Editor Error", err.Error())
+		return nil
+	}
+
+	updated, err := calendar.ParseEventDocument(text, event)
+	if err != nil {
+		av.ShowErrorMessage(g, "Cannot Edit Event", err.Error())
+		return nil
+	}
+	if updated.Equal(event) {
+		return nil
+	}
+
+	av.EventManager.UpdateEvent(event.Id, updated)
+	return nil
+}
@@ -0,0 +1,181 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/jroimartin/gocui"
+)
+
+// filters returns the filters defined in the config followed by those saved from the search popup
+func (av *AppView) filters() ([]calendar.Filter, error) {
+	filters, err := config.GetFilters(av.Config)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := av.Database.GetFilters()
+	if err != nil {
+		return nil, err
+	}
+	return append(filters, saved...), nil
+}
+
+// findFilter returns the filter with the given name
+func (av *AppView) findFilter(name string) (*calendar.Filter, error) {
+	filters, err := av.filters()
+	if err != nil {
+		return nil, err
+	}
+	for i := range filters {
+		if filters[i].Name == name {
+			return &filters[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no filter named %s", name)
+}
+
+// isConfigFilter reports whether a filter is defined in the config rather than saved
+func (av *AppView) isConfigFilter(name string) bool {
+	filters, _ := config.GetFilters(av.Config)
+	for _, filter := range filters {
+		if filter.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ActivateFilter shows only the events the named filter matches
+func (av *AppView) ActivateFilter(g *gocui.Gui, name string) error {
+	filter, err := av.findFilter(name)
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	av.setFilter(g, filter)
+	av.showCommandOutput(fmt.Sprintf("Filter %s: %s", filter.Name, filter.Describe()))
+	return nil
+}
+
+// ClearFilter shows every event again
+func (av *AppView) ClearFilter(g *gocui.Gui) error {
+	if av.Calendar.Filter == nil {
+		return nil
+	}
+	av.setFilter(g, nil)
+	av.showCommandOutput("Filter off")
+	return nil
+}
+
+// ToggleFilter turns the active filter off, or turns the last one used back on, or the first one
+func (av *AppView) ToggleFilter(g *gocui.Gui) error {
+	if av.Calendar.Filter != nil {
+		return av.ClearFilter(g)
+	}
+
+	filters, err := av.filters()
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	if len(filters) == 0 {
+		av.showCommandError(fmt.Errorf("no filters: define them in the config or save one from the search popup"))
+		return nil
+	}
+	name := filters[0].Name
+	for _, filter := range filters {
+		if filter.Name == av.lastFilter {
+			name = filter.Name
+		}
+	}
+	return av.ActivateFilter(g, name)
+}
+
+// SaveFilter saves a filter made in the search popup and turns it on
+func (av *AppView) SaveFilter(g *gocui.Gui, filter calendar.Filter) error {
+	if err := filter.Validate(); err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	if av.isConfigFilter(filter.Name) {
+		av.showCommandError(fmt.Errorf("filter %s is defined in the config", filter.Name))
+		return nil
+	}
+	if err := av.Database.SaveFilter(filter); err != nil {
+		av.showCommandError(fmt.Errorf("could not save filter %s: %v", filter.Name, err))
+		return nil
+	}
+	return av.ActivateFilter(g, filter.Name)
+}
+
+// setFilter changes the active filter, nil for none, and redraws the views it narrows
+func (av *AppView) setFilter(g *gocui.Gui, filter *calendar.Filter) {
+	av.Calendar.Filter = filter
+	if filter != nil {
+		av.lastFilter = filter.Name
+	}
+	// The events under the selection may be hidden or shown
+	av.Calendar.SelectedEventId = 0
+	av.UpdateCurrentView(g)
+}
+
+// filterCommand lists the filters, turns one on or off, or deletes a saved one
+func (av *AppView) filterCommand(g *gocui.Gui, args []string) error {
+	switch {
+	case len(args) == 0:
+		return av.listFilters()
+	case len(args) == 1 && strings.ToLower(args[0]) == "off":
+		return av.ClearFilter(g)
+	case len(args) == 1:
+		return av.ActivateFilter(g, args[0])
+	case len(args) == 2 && strings.ToLower(args[0]) == "delete":
+		return av.deleteFilter(g, args[1])
+	}
+	return fmt.Errorf("usage: filter [NAME | off | delete NAME]")
+}
+
+func (av *AppView) listFilters() error {
+	filters, err := av.filters()
+	if err != nil {
+		return err
+	}
+	if len(filters) == 0 {
+		av.showCommandOutput("No filters: define them in the config or save one from the search popup")
+		return nil
+	}
+
+	lines := []string{"Filters (* is on, F toggles):"}
+	for _, filter := range filters {
+		active := " "
+		if av.Calendar.Filter != nil && av.Calendar.Filter.Name == filter.Name {
+			active = "*"
+		}
+		source := ""
+		if av.isConfigFilter(filter.Name) {
+			source = " (config)"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %-16s %s%s", active, filter.Name, filter.Describe(), source))
+	}
+	av.showCommandOutput(lines...)
+	return nil
+}
+
+// deleteFilter deletes a filter saved from the search popup, turning it off if it is on
+func (av *AppView) deleteFilter(g *gocui.Gui, name string) error {
+	if av.isConfigFilter(name) {
+		return fmt.Errorf("filter %s is defined in the config", name)
+	}
+	if _, err := av.findFilter(name); err != nil {
+		return err
+	}
+	if err := av.Database.DeleteFilter(name); err != nil {
+		return err
+	}
+	if av.Calendar.Filter != nil && av.Calendar.Filter.Name == name {
+		av.setFilter(g, nil)
+	}
+	av.showCommandOutput("Deleted filter " + name)
+	return nil
+}
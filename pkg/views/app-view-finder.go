@@ -0,0 +1,79 @@
+package views
+
+import (
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/finder"
+	"github.com/jroimartin/gocui"
+)
+
+// ShowFinder opens the event finder over every event. The events are read once, when it opens,
+// so that typing only ranks them again.
+func (av *AppView) ShowFinder(g *gocui.Gui) error {
+	fv := av.finderView()
+	if fv == nil {
+		return nil
+	}
+
+	events, err := av.EventManager.GetAllEvents()
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	localEvents := make([]*calendar.Event, len(events))
+	for i, event := range events {
+		localEvent := *event
+		localEvent.Time = event.Time.In(time.Local)
+		localEvents[i] = &localEvent
+	}
+	fv.Open(finder.Entries(localEvents, time.Now()))
+
+	width := min(FinderWidth, av.W-4)
+	height := min(FinderMaxHeight, av.H-4)
+	fv.SetProperties(av.X+(av.W-width)/2, av.Y+(av.H-height)/2, width, height)
+	if err := fv.Update(g); err != nil {
+		return err
+	}
+	return av.UpdateCurrentView(g)
+}
+
+// CloseFinder closes the event finder without jumping
+func (av *AppView) CloseFinder(g *gocui.Gui) error {
+	if fv := av.finderView(); fv != nil {
+		fv.Close()
+		if err := fv.Update(g); err != nil {
+			return err
+		}
+	}
+	return av.UpdateCurrentView(g)
+}
+
+// JumpToFoundEvent closes the event finder and jumps to the selected event, or to the next
+// occurrence of the selected series
+func (av *AppView) JumpToFoundEvent(g *gocui.Gui, v *gocui.View) error {
+	fv := av.finderView()
+	if fv == nil {
+		return nil
+	}
+	entry := fv.SelectedEntry()
+	if err := av.CloseFinder(g); err != nil {
+		return err
+	}
+	if entry == nil {
+		return nil
+	}
+
+	av.Calendar.SelectedEventId = entry.Event.Id
+	av.jumpFrom(g, entry.Event.Time)
+	return nil
+}
+
+func (av *AppView) finderView() *FinderView {
+	if view, ok := av.GetChild("finder"); ok {
+		if fv, ok := view.(*FinderView); ok {
+			return fv
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/jroimartin/gocui"
+)
+
+// grabModeLabel is shown at the left of the bottom row in grab mode
+const grabModeLabel = "-- GRAB --"
+
+// IsGrabMode reports whether an event is held in grab mode, which works in the week, day and
+// rolling days views
+func (av *AppView) IsGrabMode() bool {
+	return av.Calendar.Grab != nil && av.isTimelineMode()
+}
+
+// StartGrab holds the event under the cursor so that the keys move and resize it
+func (av *AppView) StartGrab(g *gocui.Gui) error {
+	if !av.isTimelineMode() {
+		av.showCommandError(errors.New("grab mode works in the week, day and days views"))
+		return nil
+	}
+	eventView, ok := av.GetHoveredOnView(g).(*EventView)
+	if !ok || eventView.Event == nil {
+		av.showCommandError(errors.New("no event under the cursor"))
+		return nil
+	}
+
+	av.ExitVisual()
+	av.Calendar.SelectedEventId = eventView.Event.Id
+	av.Calendar.Grab = calendar.NewGrab(eventView.Event)
+	av.updateGrabConflicts()
+	return nil
+}
+
+// GrabMove moves the held event days later and slots half hours later, earlier when negative.
+// The cursor moves with it.
+func (av *AppView) GrabMove(g *gocui.Gui, days, slots int) error {
+	grab := av.Calendar.Grab
+	if grab == nil {
+		return nil
+	}
+
+	grab.Move(days, slots)
+	av.Calendar.CurrentDay.Date = calendar.ApplyOffset(av.Calendar.CurrentDay.Date, days, time.Duration(slots)*calendar.SlotDuration)
+	av.Calendar.UpdateWeek()
+	av.updateGrabConflicts()
+	return av.UpdateCurrentView(g)
+}
+
+// GrabResize makes the held event slots half hours longer, shorter when negative. The cursor
+// stays on the event.
+func (av *AppView) GrabResize(g *gocui.Gui, slots int) error {
+	grab := av.Calendar.Grab
+	if grab == nil {
+		return nil
+	}
+
+	grab.Resize(slots)
+	if end := grab.Event.EndTime(); !av.Calendar.CurrentDay.Date.Before(end) {
+		av.Calendar.CurrentDay.Date = end.Add(-calendar.SlotDuration)
+	}
+	av.updateGrabConflicts()
+	return av.UpdateCurrentView(g)
+}
+
+// CommitGrab saves the held event's new time and length as a single undo step. When the overlap
+// policy refuses it the event stays held to be moved somewhere else.
+func (av *AppView) CommitGrab(g *gocui.Gui) error {
+	grab := av.Calendar.Grab
+	if grab == nil {
+		return nil
+	}
+
+	if grab.Changed() {
+		updated := grab.Event
+		if !av.EventManager.UpdateEvent(updated.Id, &updated) {
+			// Error is handled by EventManager internally
+			return nil
+		}
+	}
+	av.Calendar.Grab = nil
+	return nil
+}
+
+// CancelGrab drops the held event where it was grabbed and moves the cursor back with it
+func (av *AppView) CancelGrab(g *gocui.Gui) error {
+	grab := av.Calendar.Grab
+	if grab == nil {
+		return nil
+	}
+
+	days, clock := calendar.Offset(grab.Event.Time, grab.Original.Time)
+	av.Calendar.CurrentDay.Date = calendar.ApplyOffset(av.Calendar.CurrentDay.Date, days, clock)
+	if end := grab.Original.EndTime(); !av.Calendar.CurrentDay.Date.Before(end) {
+		av.Calendar.CurrentDay.Date = end.Add(-calendar.SlotDuration)
+	}
+	av.Calendar.Grab = nil
+	av.Calendar.UpdateWeek()
+	return av.UpdateCurrentView(g)
+}
+
+// updateGrabConflicts finds the events the held event's preview overlaps
+func (av *AppView) updateGrabConflicts() {
+	grab := av.Calendar.Grab
+	grab.Conflicts = nil
+	overlapping, err := av.EventManager.FindOverlappingEvents(&grab.Event, grab.Event.Id)
+	if err != nil {
+		av.showCommandError(err)
+		return
+	}
+	for _, event := range overlapping {
+		grab.Conflicts = append(grab.Conflicts, event.Id)
+	}
+}
+
+// updateGrabMode drops the held event when the view no longer supports grab mode and shows the
+// mode with the preview's time
+func (av *AppView) updateGrabMode() {
+	if av.Calendar.Grab != nil && !av.IsGrabMode() {
+		av.Calendar.Grab = nil
+	}
+	clv := av.commandLine()
+	if clv == nil || !av.IsGrabMode() {
+		return
+	}
+
+	event := av.Calendar.Grab.Event
+	clv.Mode = fmt.Sprintf("%s %s %s-%s", grabModeLabel, event.Time.Format("Mon 2"), event.Time.Format("15:04"), event.EndTime().Format("15:04"))
+	if n := len(av.Calendar.Grab.Conflicts); n > 0 {
+		clv.Mode += fmt.Sprintf(" overlaps %d %s", n, pluralEvents(n))
+	}
+}
+
+// grabPreview replaces the held event among a day's events with its preview, which shows on the
+// day it now starts on
+func (av *AppView) grabPreview(day *calendar.Day) {
+	grab := av.Calendar.Grab
+	if grab == nil {
+		return
+	}
+
+	events := day.Events[:0:0]
+	for _, event := range day.Events {
+		if event.Id != grab.Event.Id {
+			events = append(events, event)
+		}
+	}
+	if grab.StartsOn(day.Date) {
+		preview := grab.Event
+		events = append(events, &preview)
+	}
+	day.Events = events
+	day.SortEventsByTime()
+}
@@ -0,0 +1,136 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jroimartin/gocui"
+)
+
+// isMarkName reports whether a character names a mark
+func isMarkName(name rune) bool {
+	return name >= 'a' && name <= 'z'
+}
+
+// SetMark saves the selected date and time as the mark named by a letter
+func (av *AppView) SetMark(g *gocui.Gui, name rune) error {
+	if !isMarkName(name) {
+		av.showCommandError(fmt.Errorf("marks are named a-z, not %q", name))
+		return nil
+	}
+
+	t := av.Calendar.CurrentDay.Date
+	if err := av.Database.SetMark(string(name), t); err != nil {
+		av.showCommandError(fmt.Errorf("could not set mark %c: %v", name, err))
+		return nil
+	}
+	av.showCommandOutput(fmt.Sprintf("Mark %c set at %s", name, t.Format("Mon Jan 2 2006 15:04")))
+	return nil
+}
+
+// JumpToMark jumps to the mark named by a letter, or with ' back to where the last jump started
+func (av *AppView) JumpToMark(g *gocui.Gui, name rune) error {
+	if name == '\'' || name == '`' {
+		if t, ok := av.jumps.Last(); ok {
+			av.jumpFrom(g, t)
+		}
+		return nil
+	}
+	if !isMarkName(name) {
+		av.showCommandError(fmt.Errorf("marks are named a-z, not %q", name))
+		return nil
+	}
+
+	mark, err := av.Database.GetMark(string(name))
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	if mark == nil {
+		av.showCommandError(fmt.Errorf("mark %c is not set", name))
+		return nil
+	}
+	av.jumpFrom(g, mark.Time.In(time.Local))
+	return nil
+}
+
+// ShowMarks shows or hides the list of marks
+func (av *AppView) ShowMarks(g *gocui.Gui) error {
+	mv := av.marksView()
+	if mv == nil {
+		return nil
+	}
+	if mv.IsVisible {
+		return av.closeMarks(g)
+	}
+
+	marks, err := av.Database.GetMarks()
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	mv.Marks = marks
+	mv.IsVisible = true
+
+	height := mv.GetRequiredHeight()
+	if maxHeight := av.H - 4; height > maxHeight {
+		height = maxHeight
+	}
+	mv.SetProperties(av.X+(av.W-MarksWidth)/2, av.Y+(av.H-height)/2, MarksWidth, height)
+	if err := mv.Update(g); err != nil {
+		return err
+	}
+	return av.UpdateCurrentView(g)
+}
+
+// JumpToListedMark closes the list of marks and jumps to the mark named by a letter
+func (av *AppView) JumpToListedMark(g *gocui.Gui, name rune) error {
+	if err := av.closeMarks(g); err != nil {
+		return err
+	}
+	return av.JumpToMark(g, name)
+}
+
+func (av *AppView) closeMarks(g *gocui.Gui) error {
+	if mv := av.marksView(); mv != nil {
+		mv.IsVisible = false
+		if err := mv.Update(g); err != nil {
+			return err
+		}
+	}
+	return av.UpdateCurrentView(g)
+}
+
+func (av *AppView) marksView() *MarksView {
+	if view, ok := av.GetChild("marks"); ok {
+		if mv, ok := view.(*MarksView); ok {
+			return mv
+		}
+	}
+	return nil
+}
+
+// recordJump remembers the selected date and time before a big jump, for JumpBack
+func (av *AppView) recordJump() {
+	av.jumps.Push(av.Calendar.CurrentDay.Date)
+}
+
+// jumpFrom records the selected date and time in the jump list and jumps to t
+func (av *AppView) jumpFrom(g *gocui.Gui, t time.Time) {
+	av.recordJump()
+	av.jumpTo(g, t)
+}
+
+// JumpBack returns to where the selection was before the last jump (vim's Ctrl-o)
+func (av *AppView) JumpBack(g *gocui.Gui) {
+	if t, ok := av.jumps.Back(av.Calendar.CurrentDay.Date); ok {
+		av.jumpTo(g, t)
+	}
+}
+
+// JumpForward undoes JumpBack (vim's Ctrl-i)
+func (av *AppView) JumpForward(g *gocui.Gui) {
+	if t, ok := av.jumps.Forward(); ok {
+		av.jumpTo(g, t)
+	}
+}
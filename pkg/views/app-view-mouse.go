@@ -0,0 +1,290 @@
+package views
+
+import (
+	"strings"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/jroimartin/gocui"
+)
+
+// doubleClickTime is how soon a second click on the same place must follow the first to open
+// the event there
+const doubleClickTime = 400 * time.Millisecond
+
+// mouseDrag is a press of the left button in the week, day and days views. The cursor moves to
+// the slot pressed when the button is released, as moving it sooner would scroll the time column
+// under the pointer. An event pressed on is moved or resized through grab mode once the pointer
+// leaves that slot.
+type mouseDrag struct {
+	event  *calendar.Event // the event pressed on, nil for an empty slot
+	from   time.Time       // the slot pressed
+	to     time.Time       // the slot the cursor moves to when the button is released
+	resize bool            // pressed on the event's bottom edge, which changes its duration
+}
+
+// DoubleClicks tells double clicks from the clicks before them. The zero value is ready to use.
+type DoubleClicks struct {
+	at   time.Time
+	view string
+	row  int
+}
+
+// Click remembers a click on a screen row of a view at a time and reports whether it closely
+// follows one on the same row
+func (d *DoubleClicks) Click(view string, row int, at time.Time) bool {
+	double := d.view == view && d.row == row && !d.at.IsZero() && at.Sub(d.at) < doubleClickTime
+	if double {
+		// Forget it so that a third click starts over
+		*d = DoubleClicks{}
+	} else {
+		*d = DoubleClicks{at: at, view: view, row: row}
+	}
+	return double
+}
+
+// MouseDown moves the cursor to the day, slot or agenda row clicked, picks up the event clicked
+// in the week, day and days views to drag it, and opens the event clicked twice
+func (av *AppView) MouseDown(g *gocui.Gui, v *gocui.View) error {
+	if !av.calendarFocused(g) || av.IsGrabMode() {
+		return nil
+	}
+	x, y, ok := screenPosition(g, v)
+	if !ok {
+		return nil
+	}
+	double := av.clicks.Click(v.Name(), y, time.Now())
+
+	switch {
+	case av.isTimelineMode():
+		if double {
+			// The first click selected the event; the rows may have scrolled since
+			return av.ShowEditEventPopup(g)
+		}
+		return av.pressTimeline(x, y)
+	case av.isGridMode():
+		return av.clickMonthDay(g, v, double)
+	case av.IsAgendaMode():
+		if double {
+			return av.ShowEditEventPopup(g)
+		}
+		return av.clickAgenda(g, v)
+	}
+	return nil
+}
+
+// MouseDrag moves or resizes the event picked up to the slot under the pointer, previewing it as
+// grab mode does
+func (av *AppView) MouseDrag(g *gocui.Gui, v *gocui.View) error {
+	drag := av.drag
+	if drag == nil || drag.event == nil || !av.isTimelineMode() {
+		return nil
+	}
+	x, y, ok := screenPosition(g, v)
+	if !ok {
+		return nil
+	}
+	slot, ok := av.timelineSlotAt(x, y)
+	if !ok || (av.Calendar.Grab == nil && slot.Equal(drag.from)) {
+		return nil
+	}
+
+	if av.Calendar.Grab == nil {
+		av.Calendar.SelectedEventId = drag.event.Id
+		av.Calendar.Grab = calendar.NewGrab(drag.event)
+	}
+	grab := av.Calendar.Grab
+	if drag.resize {
+		// The bottom edge follows the pointer's time of day on the event's own day
+		days, _ := calendar.Offset(grab.Event.Time, slot)
+		grab.ResizeTo(calendar.ApplyOffset(slot, -days, calendar.SlotDuration))
+		drag.to = grab.Event.EndTime().Add(-calendar.SlotDuration)
+	} else {
+		days, clock := calendar.Offset(drag.from, slot)
+		grab.Event.Time = calendar.ApplyOffset(drag.event.Time, days, clock)
+		drag.to = slot
+	}
+	av.updateGrabConflicts()
+	return nil
+}
+
+// MouseUp moves the cursor to the slot pressed, or saves the event dragged as a single undo step
+// and moves the cursor onto it, putting it back when the overlap policy refuses it
+func (av *AppView) MouseUp(g *gocui.Gui, v *gocui.View) error {
+	drag := av.drag
+	av.drag = nil
+	if drag == nil {
+		return nil
+	}
+
+	if av.Calendar.Grab != nil {
+		if err := av.CommitGrab(g); err != nil {
+			return err
+		}
+		if av.Calendar.Grab != nil {
+			// Refused: the event goes back where it was, and the cursor never left it
+			av.Calendar.Grab = nil
+			return nil
+		}
+	} else if drag.event != nil {
+		av.Calendar.SelectedEventId = drag.event.Id
+	}
+	av.Calendar.CurrentDay.Date = drag.to
+	av.Calendar.UpdateWeek()
+	return av.UpdateCurrentView(g)
+}
+
+// MouseWheel scrolls the detail pane under the pointer, and otherwise moves the cursor as j and
+// k do: through the time slots, scrolling the time column, the month's weeks or the agenda list
+func (av *AppView) MouseWheel(g *gocui.Gui, v *gocui.View, direction int) error {
+	if v.Name() == "detail" {
+		av.ScrollDetailPane(direction)
+		return nil
+	}
+	if !av.calendarFocused(g) {
+		return nil
+	}
+	if av.IsGrabMode() {
+		if av.drag != nil {
+			return nil
+		}
+		return av.GrabMove(g, 0, direction)
+	}
+
+	if direction > 0 {
+		av.UpdateToNextTime(g)
+	} else {
+		av.UpdateToPrevTime(g)
+	}
+	return av.UpdateCurrentView(g)
+}
+
+// pressTimeline starts a press on the slot under the pointer, or on the time of the selected day
+// when the time column is pressed, and on the event there
+func (av *AppView) pressTimeline(x, y int) error {
+	slot, ok := av.timelineSlotAt(x, y)
+	if !ok {
+		return nil
+	}
+	drag := &mouseDrag{from: slot, to: slot}
+
+	if dv, ok := av.weekView().DayAt(x, y); ok {
+		if eventView, ok := dv.EventAt(x, y); ok {
+			event := *eventView.Event
+			drag.event = &event
+			drag.resize = calendar.OnBottomEdge(&event, slot)
+		}
+	}
+	av.drag = drag
+	return nil
+}
+
+// clickMonthDay selects the day of the month or weeks grid clicked, or the event listed there
+func (av *AppView) clickMonthDay(g *gocui.Gui, v *gocui.View, double bool) error {
+	view, ok := av.FindChildView(v.Name())
+	if !ok {
+		return nil
+	}
+	mdv, ok := view.(*MonthDayView)
+	if !ok {
+		return nil
+	}
+
+	oldMonth := av.Calendar.CurrentDay.Date.Month()
+	_, row := v.Cursor()
+	if event, ok := mdv.EventAtRow(row); ok {
+		av.Calendar.CurrentDay.Date = event.Time
+		if double {
+			return av.showEditPopupFor(g, NewEvenView("mouse_selected_event", event))
+		}
+	} else {
+		current := av.Calendar.CurrentDay.Date
+		av.Calendar.CurrentDay.Date = time.Date(mdv.Date.Year(), mdv.Date.Month(), mdv.Date.Day(), current.Hour(), current.Minute(), 0, 0, current.Location())
+	}
+	av.Calendar.UpdateWeek()
+	av.handleMonthChange(g, oldMonth)
+	return av.UpdateCurrentView(g)
+}
+
+// clickAgenda selects the agenda row clicked
+func (av *AppView) clickAgenda(g *gocui.Gui, v *gocui.View) error {
+	agenda := av.agendaView()
+	if agenda == nil || v.Name() != agenda.Name {
+		return nil
+	}
+	_, line := v.Cursor()
+	index, ok := agenda.EventIndexAt(line)
+	if !ok {
+		return nil
+	}
+
+	agenda.MoveSelection(index - agenda.SelectedIndex)
+	return av.UpdateCurrentView(g)
+}
+
+// timelineSlotAt returns the date and slot drawn at a screen position in the week, day and days
+// views. In the time column it is the slot on the selected day.
+func (av *AppView) timelineSlotAt(x, y int) (time.Time, bool) {
+	wv := av.weekView()
+	if wv == nil {
+		return time.Time{}, false
+	}
+	if dv, ok := wv.DayAt(x, y); ok {
+		return dv.SlotAt(y)
+	}
+
+	tv := wv.TimeView
+	if x <= tv.X || x >= tv.X+tv.W {
+		return time.Time{}, false
+	}
+	if view, ok := av.FindChildView(av.selectedColumnName()); ok {
+		if dv, ok := view.(*DayView); ok {
+			return dv.SlotAt(y)
+		}
+	}
+	return time.Time{}, false
+}
+
+// weekView returns the view of the week, day and days layouts, or nil before the main view exists
+func (av *AppView) weekView() *WeekView {
+	if mainView, ok := av.GetChild("main"); ok {
+		if mv, ok := mainView.(*MainView); ok && mv.CalendarView != nil {
+			return mv.CalendarView.WeekView
+		}
+	}
+	return nil
+}
+
+// calendarFocused reports whether the calendar has the keyboard, rather than a popup, prompt or
+// list that the mouse should leave alone. A click dismisses command output as a key does.
+func (av *AppView) calendarFocused(g *gocui.Gui) bool {
+	if clv := av.commandLine(); clv != nil && clv.HasFocusedOutput() {
+		av.DismissCommandOutput()
+		return false
+	}
+	current := g.CurrentView()
+	if current == nil {
+		return false
+	}
+	name := current.Name()
+	if name == "year" || name == "agenda" || strings.HasPrefix(name, "monthday_") {
+		return true
+	}
+	for _, column := range DayColumnNames {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// screenPosition returns the screen position of the mouse event on view v, which gocui gives as
+// the view's cursor
+func screenPosition(g *gocui.Gui, v *gocui.View) (int, int, bool) {
+	x0, y0, _, _, err := g.ViewPosition(v.Name())
+	if err != nil {
+		return 0, 0, false
+	}
+	cx, cy := v.Cursor()
+	return x0 + 1 + cx, y0 + 1 + cy, true
+}
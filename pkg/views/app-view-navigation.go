@@ -10,49 +10,62 @@ import (
 	"github.com/jroimartin/gocui"
 )
 
-// JumpToNextEvent navigates to the next event chronologically
+// JumpToNextEvent navigates to the next event chronologically. Events starting at the same
+// time are visited one by one, selecting each of the overlapping events in turn.
 func (av *AppView) JumpToNextEvent() {
-	// Get all events from EventManager and convert to local time
-	allEvents, err := av.EventManager.GetAllEvents()
-	if err != nil || len(allEvents) == 0 {
+	localEvents := av.getLocalEventsInOrder()
+	if len(localEvents) == 0 {
 		return
 	}
 
-	// Convert UTC events to local time for comparison
-	localEvents := make([]*calendar.Event, len(allEvents))
-	for i, event := range allEvents {
-		localEvent := *event
-		localEvent.Time = event.Time.In(time.Local)
-		localEvents[i] = &localEvent
-	}
-
 	currentTime := av.Calendar.CurrentDay.Date
+	currentId := av.selectedEventIdAt(localEvents, currentTime)
 	
-	// Find the next event after current time
+	// Find the next event after the current time and selection
 	for _, event := range localEvents {
-		if event.Time.After(currentTime) {
-			av.Calendar.CurrentDay.Date = event.Time
-			av.Calendar.UpdateWeek()
+		if event.Time.After(currentTime) || (event.Time.Equal(currentTime) && event.Id > currentId) {
+			av.selectEvent(event)
 			return
 		}
 	}
 	
 	// If no event found after current time, wrap to first event
-	if len(localEvents) > 0 {
-		av.Calendar.CurrentDay.Date = localEvents[0].Time
-		av.Calendar.UpdateWeek()
-	}
+	av.selectEvent(localEvents[0])
 }
 
 // JumpToPrevEvent navigates to the previous event chronologically
 func (av *AppView) JumpToPrevEvent() {
+	localEvents := av.getLocalEventsInOrder()
+	if len(localEvents) == 0 {
+		return
+	}
+
+	currentTime := av.Calendar.CurrentDay.Date
+	currentId := av.selectedEventIdAt(localEvents, currentTime)
+	
+	// Find the previous event before the current time and selection (iterate backwards)
+	for i := len(localEvents) - 1; i >= 0; i-- {
+		event := localEvents[i]
+		if event.Time.Before(currentTime) || (event.Time.Equal(currentTime) && event.Id < currentId) {
+			av.selectEvent(event)
+			return
+		}
+	}
+	
+	// If no event found before current time, wrap to last event
+	av.selectEvent(localEvents[len(localEvents)-1])
+}
+
+// getLocalEventsInOrder returns all events in local time, ordered by start time and then ID
+// (the order overlapping events are laid out from left to right)
+func (av *AppView) getLocalEventsInOrder() []*calendar.Event {
 	// Get all events from EventManager and convert to local time
 	allEvents, err := av.EventManager.GetAllEvents()
-	if err != nil || len(allEvents) == 0 {
-		return
+	if err != nil {
+		return nil
 	}
+	allEvents = av.Calendar.Filtered(allEvents)
 
-	// Convert UTC events to local time for comparison
 	localEvents := make([]*calendar.Event, len(allEvents))
 	for i, event := range allEvents {
 		localEvent := *event
@@ -60,31 +73,52 @@ func (av *AppView) JumpToPrevEvent() {
 		localEvents[i] = &localEvent
 	}
 
-	currentTime := av.Calendar.CurrentDay.Date
-	
-	// Find the previous event before current time (iterate backwards)
-	for i := len(localEvents) - 1; i >= 0; i-- {
-		event := localEvents[i]
-		if event.Time.Before(currentTime) {
-			av.Calendar.CurrentDay.Date = event.Time
-			av.Calendar.UpdateWeek()
-			return
+	sort.SliceStable(localEvents, func(i, j int) bool {
+		if !localEvents[i].Time.Equal(localEvents[j].Time) {
+			return localEvents[i].Time.Before(localEvents[j].Time)
+		}
+		return localEvents[i].Id < localEvents[j].Id
+	})
+	return localEvents
+}
+
+// selectedEventIdAt returns the ID of the event the cursor is on if it starts at t, so that
+// w/b continue with the other events starting in the same slot. It returns 0 otherwise.
+func (av *AppView) selectedEventIdAt(events []*calendar.Event, t time.Time) int {
+	hoveredId := av.Calendar.SelectedEventId
+	if view, ok := av.FindChildView(av.columnNameForDate(t)); ok && av.isTimelineMode() {
+		if dayView, ok := view.(*DayView); ok {
+			hoveredId = 0
+			if eventView, ok := dayView.IsOnEvent(av.GetCursorY()); ok {
+				hoveredId = eventView.Event.Id
+			}
 		}
 	}
-	
-	// If no event found before current time, wrap to last event
-	if len(localEvents) > 0 {
-		lastEvent := localEvents[len(localEvents)-1]
-		av.Calendar.CurrentDay.Date = lastEvent.Time
-		av.Calendar.UpdateWeek()
+
+	for _, event := range events {
+		if event.Id == hoveredId && event.Time.Equal(t) {
+			return event.Id
+		}
 	}
+	return 0
+}
+
+// selectEvent moves the cursor to the start of an event and selects it among overlapping events
+func (av *AppView) selectEvent(event *calendar.Event) {
+	av.Calendar.CurrentDay.Date = event.Time
+	av.Calendar.SelectedEventId = event.Id
+	av.Calendar.UpdateWeek()
 }
 
 // JumpToEndOfEvent navigates to end of current event, or end of next event if not in one
 func (av *AppView) JumpToEndOfEvent() {
 	// Get all events from EventManager and convert to local time
 	allEvents, err := av.EventManager.GetAllEvents()
-	if err != nil || len(allEvents) == 0 {
+	if err != nil {
+		return
+	}
+	allEvents = av.Calendar.Filtered(allEvents)
+	if len(allEvents) == 0 {
 		return
 	}
 
@@ -190,6 +224,9 @@ func (av *AppView) StartSearch(g *gocui.Gui) error {
 			popupView.SearchCallback = func(criteria database.SearchCriteria) error {
 				return av.executeSearchQuery(criteria)
 			}
+			popupView.SaveFilterCallback = func(filter calendar.Filter) error {
+				return av.SaveFilter(g, filter)
+			}
 			
 			popup.SetProperties(
 				av.X+(av.W-PopupWidth)/2,
@@ -210,10 +247,16 @@ func (av *AppView) executeSearchQuery(criteria database.SearchCriteria) error {
 	av.currentMatchIndex = 0
 	av.isSearchActive = true
 	
+	// The agenda lists the results from the current match on
+	if agenda := av.agendaView(); agenda != nil {
+		agenda.SetSearch(&criteria)
+	}
+	
 	if len(av.searchMatches) > 0 {
 		// Jump to first match
 		firstMatch := av.searchMatches[0]
 		
+		av.recordJump()
 		av.Calendar.CurrentDay.Date = firstMatch.Time
 		av.Calendar.UpdateWeek()
 	}
@@ -228,6 +271,8 @@ func (av *AppView) findMatches(criteria database.SearchCriteria) []*calendar.Eve
 	if err != nil {
 		return []*calendar.Event{}
 	}
+	// Matches the filter hides would jump to empty slots
+	matches = av.Calendar.Filtered(matches)
 	
 	// Convert UTC events to local time for display
 	localMatches := make([]*calendar.Event, len(matches))
@@ -246,6 +291,7 @@ func (av *AppView) GoToNextMatch() error {
 		return nil
 	}
 	
+	av.recordJump()
 	av.currentMatchIndex = (av.currentMatchIndex + 1) % len(av.searchMatches)
 	match := av.searchMatches[av.currentMatchIndex]
 	
@@ -261,6 +307,7 @@ func (av *AppView) GoToPrevMatch() error {
 		return nil
 	}
 	
+	av.recordJump()
 	av.currentMatchIndex = (av.currentMatchIndex - 1 + len(av.searchMatches)) % len(av.searchMatches)
 	match := av.searchMatches[av.currentMatchIndex]
 	
@@ -276,6 +323,10 @@ func (av *AppView) ClearSearch() {
 	av.searchQuery = ""
 	av.searchMatches = nil
 	av.currentMatchIndex = 0
+	
+	if agenda := av.agendaView(); agenda != nil {
+		agenda.SetSearch(nil)
+	}
 }
 
 // GetSearchStatus returns the current search status string
@@ -358,6 +409,7 @@ func (av *AppView) ShowGotoPopup(g *gocui.Gui) error {
 func (av *AppView) ShowDatePopup(g *gocui.Gui) error {
 	if popup, ok := av.FindChildView("popup"); ok {
 		if popupView, ok := popup.(*EventPopupView); ok {
+			popupView.JumpCallback = av.recordJump
 			popup.SetProperties(
 				av.X+(av.W-PopupWidth)/2,
 				av.Y+(av.H-PopupHeight)/2,
@@ -0,0 +1,187 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/registers"
+	"github.com/jroimartin/gocui"
+)
+
+// SelectRegister names the register the next yank, delete or paste uses, as vim's "a does, or
+// the latest yank for "
+func (av *AppView) SelectRegister(g *gocui.Gui, name rune) error {
+	if name == '"' {
+		name = registers.Unnamed
+	} else if !registers.IsName(name) {
+		av.showCommandError(fmt.Errorf("registers are named a-z or 0-9, not %q", name))
+		return nil
+	}
+	av.register = name
+	av.ShowPendingKeys("")
+	return nil
+}
+
+// ClearRegister forgets the register named with " once the command after it has run
+func (av *AppView) ClearRegister() {
+	if av.register != registers.Unnamed {
+		av.register = registers.Unnamed
+		av.ShowPendingKeys("")
+	}
+}
+
+// pendingRegister returns the register named for the next command as typed, e.g. "a
+func (av *AppView) pendingRegister() string {
+	if av.register == registers.Unnamed {
+		return ""
+	}
+	return fmt.Sprintf("\"%c", av.register)
+}
+
+// yankEvents puts copies of events in the yank history and in the register named with ", which
+// it then forgets. Several events are pasted keeping their offsets from the first.
+func (av *AppView) yankEvents(events []*calendar.Event) bool {
+	name := av.register
+	av.ClearRegister()
+	if err := av.registers.Yank(name, events); err != nil {
+		av.showCommandError(err)
+		return false
+	}
+	return true
+}
+
+// CopyEvent yanks the selected event: the one under the cursor, the agenda's selected row, or
+// in the month, weeks and year views the event at the selected time
+func (av *AppView) CopyEvent(g *gocui.Gui) {
+	event := av.yankableEvent(g)
+	if event == nil {
+		av.ClearRegister()
+		av.showCommandError(fmt.Errorf("no event selected"))
+		return
+	}
+	av.yankEvents([]*calendar.Event{event})
+}
+
+// yankableEvent returns the event CopyEvent yanks, or nil
+func (av *AppView) yankableEvent(g *gocui.Gui) *calendar.Event {
+	if av.isTimelineMode() || av.IsAgendaMode() {
+		if eventView, ok := av.GetHoveredOnView(g).(*EventView); ok {
+			return eventView.Event
+		}
+		return nil
+	}
+
+	// The month cells list events without a cursor of their own; w/b and clicks select an
+	// event by moving to its start
+	selected := av.Calendar.CurrentDay.Date
+	day := time.Date(selected.Year(), selected.Month(), selected.Day(), 0, 0, 0, 0, selected.Location())
+	events, err := av.EventManager.GetEventsByDate(day)
+	if err != nil {
+		return nil
+	}
+	var found *calendar.Event
+	for _, event := range av.Calendar.Filtered(events) {
+		localEvent := *event
+		localEvent.Time = event.Time.In(time.Local)
+		if selected.Before(localEvent.Time) || !selected.Before(localEvent.EndTime()) {
+			continue
+		}
+		if found == nil || localEvent.Id == av.Calendar.SelectedEventId {
+			found = &localEvent
+		}
+	}
+	return found
+}
+
+// PasteEvents pastes the register named with ", or the latest yank, copies times at the cursor
+// and everyDays days after it. The month, weeks, year and agenda views ask for the time first.
+func (av *AppView) PasteEvents(g *gocui.Gui, copies, everyDays int) error {
+	name := av.register
+	av.ClearRegister()
+	events, err := av.registers.Get(name)
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	return av.paste(g, events, copies, everyDays)
+}
+
+// paste adds the copies of events as a single undo step, at the cursor in the views with time
+// slots and at a time typed in the others, which starts from the first event's time
+func (av *AppView) paste(g *gocui.Gui, events []calendar.Event, copies, everyDays int) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if av.isTimelineMode() {
+		av.pasteAt(events, av.Calendar.CurrentDay.Date, copies, everyDays)
+		return nil
+	}
+
+	popup, ok := av.FindChildView("popup")
+	if !ok {
+		return nil
+	}
+	popupView, ok := popup.(*EventPopupView)
+	if !ok {
+		return nil
+	}
+
+	day := av.pasteDay()
+	popupView.PasteTimeCallback = func(hour, minute int) error {
+		av.pasteAt(events, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()), copies, everyDays)
+		return av.UpdateCurrentView(g)
+	}
+	popup.SetProperties(
+		av.X+(av.W-PopupWidth)/2,
+		av.Y+(av.H-PopupHeight)/2,
+		PopupWidth,
+		PopupHeight,
+	)
+	return popupView.ShowPasteTimePopup(g, events[0].Time.Format("15:04"))
+}
+
+// pasteDay returns the day a paste without time slots goes to: the agenda's selected event's, or
+// the selected day
+func (av *AppView) pasteDay() time.Time {
+	if av.IsAgendaMode() {
+		if agenda := av.agendaView(); agenda != nil {
+			if event := agenda.GetSelectedEvent(); event != nil {
+				return event.Time
+			}
+		}
+	}
+	return av.Calendar.CurrentDay.Date
+}
+
+func (av *AppView) pasteAt(events []calendar.Event, target time.Time, copies, everyDays int) {
+	pasted := registers.Paste(events, target, copies, everyDays)
+	if _, ok := av.EventManager.AddEvents(pasted, fmt.Sprintf("paste %d %s", len(pasted), pluralEvents(len(pasted)))); !ok {
+		// Error is handled by EventManager internally
+		return
+	}
+	av.EventManager.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangePaste, Events: events, Count: copies, EveryDays: everyDays})
+	if copies > 1 {
+		av.showCommandOutput(fmt.Sprintf("Pasted %d %s", len(pasted), pluralEvents(len(pasted))))
+	}
+}
+
+// registersCommand lists the yank history and the named registers
+func (av *AppView) registersCommand(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: registers")
+	}
+	list := av.registers.List()
+	if len(list) == 0 {
+		av.showCommandOutput("Nothing yanked")
+		return nil
+	}
+
+	lines := []string{"Registers (\" and a name before y, x or p uses one):"}
+	for _, register := range list {
+		lines = append(lines, fmt.Sprintf("  \"%c  %s", register.Name, registers.Describe(register.Events)))
+	}
+	av.showCommandOutput(lines...)
+	return nil
+}
@@ -0,0 +1,242 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/registers"
+	"github.com/jroimartin/gocui"
+)
+
+// visualModeLabel is shown at the left of the bottom row in visual mode
+const visualModeLabel = "-- VISUAL --"
+
+// IsVisualMode reports whether the keys select in visual mode, which works in the week, day and
+// rolling days views and in the agenda
+func (av *AppView) IsVisualMode() bool {
+	return av.Calendar.VisualAnchor != nil && (av.isTimelineMode() || av.IsAgendaMode())
+}
+
+// StartVisual starts selecting from the cursor: time slots and days in the timeline views, rows
+// in the agenda
+func (av *AppView) StartVisual(g *gocui.Gui) error {
+	if !av.isTimelineMode() && !av.IsAgendaMode() {
+		av.showCommandError(errors.New("visual mode works in the week, day, days and agenda views"))
+		return nil
+	}
+	anchor := av.Calendar.CurrentDay.Date
+	av.Calendar.VisualAnchor = &anchor
+	return nil
+}
+
+// ExitVisual leaves visual mode
+func (av *AppView) ExitVisual() {
+	av.Calendar.VisualAnchor = nil
+}
+
+// updateVisualMode leaves visual mode when the view no longer supports it and shows the mode
+func (av *AppView) updateVisualMode() {
+	if av.Calendar.VisualAnchor != nil && !av.IsVisualMode() {
+		av.ExitVisual()
+	}
+	if clv := av.commandLine(); clv != nil {
+		clv.Mode = ""
+		if av.IsVisualMode() {
+			clv.Mode = visualModeLabel
+		}
+	}
+}
+
+// visualEvents returns the events selected in visual mode in local time, in order
+func (av *AppView) visualEvents() []*calendar.Event {
+	if av.IsAgendaMode() {
+		if agenda := av.agendaView(); agenda != nil {
+			return agenda.VisualEvents()
+		}
+		return nil
+	}
+
+	selection, ok := av.Calendar.Visual()
+	if !ok {
+		return nil
+	}
+	first := selection.FirstDay()
+	events, err := av.EventManager.GetEventsByDateRange(first, first.AddDate(0, 0, selection.Days()))
+	if err != nil {
+		av.showCommandError(err)
+		return nil
+	}
+	events = av.Calendar.Filtered(events)
+
+	var selected []*calendar.Event
+	for _, event := range events {
+		localEvent := *event
+		localEvent.Time = event.Time.In(time.Local)
+		if selection.Overlaps(&localEvent) {
+			selected = append(selected, &localEvent)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Time.Before(selected[j].Time)
+	})
+	return selected
+}
+
+// VisualDelete deletes the selected events as a single undo step, yanking them first
+func (av *AppView) VisualDelete(g *gocui.Gui) error {
+	events := av.visualEvents()
+	av.ExitVisual()
+	if len(events) == 0 {
+		return nil
+	}
+
+	if !av.yankEvents(events) {
+		return nil
+	}
+	ids := make([]int, len(events))
+	for i, event := range events {
+		ids[i] = event.Id
+	}
+	if err := av.EventManager.DeleteEvents(ids); err != nil {
+		av.showCommandError(err)
+	}
+	return nil
+}
+
+// VisualYank copies the selected events to the register named with ", or the yank history
+func (av *AppView) VisualYank(g *gocui.Gui) error {
+	events := av.visualEvents()
+	av.ExitVisual()
+	if len(events) == 0 {
+		return nil
+	}
+
+	name := av.register
+	if !av.yankEvents(events) {
+		return nil
+	}
+	output := fmt.Sprintf("%d %s yanked", len(events), pluralEvents(len(events)))
+	if name != registers.Unnamed {
+		output += fmt.Sprintf(" into \"%c", name)
+	}
+	av.showCommandOutput(output)
+	return nil
+}
+
+// VisualColor shows the color picker and recolors the selected events as a single undo step
+func (av *AppView) VisualColor(g *gocui.Gui) error {
+	events := av.visualEvents()
+	if len(events) == 0 {
+		return nil
+	}
+
+	popup, ok := av.FindChildView("popup")
+	if !ok {
+		return nil
+	}
+	popupView, ok := popup.(*EventPopupView)
+	if !ok {
+		return nil
+	}
+
+	av.colorPickerActive = true
+	popupView.ColorPickerCallback = func(colorName string) error {
+		color := calendar.ColorNameToAttribute(colorName)
+		recolored := make([]*calendar.Event, len(events))
+		for i, event := range events {
+			updated := *event
+			updated.Color = color
+			recolored[i] = &updated
+		}
+		if !av.EventManager.UpdateEvents(recolored, fmt.Sprintf("recolor %d %s", len(events), pluralEvents(len(events)))) {
+			// Error is handled by EventManager internally
+			return nil
+		}
+		av.ExitVisual()
+		av.CloseColorPicker(g)
+		return nil
+	}
+
+	popup.SetProperties(
+		av.X+(av.W-PopupWidth)/2,
+		av.Y+(av.H-PopupHeight)/2,
+		PopupWidth,
+		PopupHeight,
+	)
+	return popupView.ShowColorPickerPopup(g)
+}
+
+// VisualShift moves the selected events days later and slots half hours later (earlier when
+// negative) as a single undo step. The selection moves with them.
+func (av *AppView) VisualShift(g *gocui.Gui, days, slots int) error {
+	events := av.visualEvents()
+	if len(events) == 0 || (days == 0 && slots == 0) {
+		return nil
+	}
+
+	clock := time.Duration(slots) * calendar.SlotDuration
+	shifted := make([]*calendar.Event, len(events))
+	for i, event := range events {
+		moved := *event
+		moved.Time = calendar.ApplyOffset(event.Time, days, clock)
+		shifted[i] = &moved
+	}
+	if !av.EventManager.UpdateEvents(shifted, fmt.Sprintf("shift %d %s", len(events), pluralEvents(len(events)))) {
+		// Error is handled by EventManager internally
+		return nil
+	}
+
+	anchor := calendar.ApplyOffset(*av.Calendar.VisualAnchor, days, clock)
+	av.Calendar.VisualAnchor = &anchor
+	av.Calendar.CurrentDay.Date = calendar.ApplyOffset(av.Calendar.CurrentDay.Date, days, clock)
+	av.Calendar.UpdateWeek()
+	return av.UpdateCurrentView(g)
+}
+
+// VisualDuplicate adds count copies of the selected events, each copy the length of the
+// selection after the one before, as a single undo step
+func (av *AppView) VisualDuplicate(g *gocui.Gui, count int) error {
+	events := av.visualEvents()
+	if len(events) == 0 {
+		av.ExitVisual()
+		return nil
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	// The selected days in the timeline views, the days from the first to the last selected
+	// event in the agenda
+	span, _ := calendar.Offset(events[0].Time, events[len(events)-1].Time)
+	span++
+	if selection, ok := av.Calendar.Visual(); ok && !av.IsAgendaMode() {
+		span = selection.Days()
+	}
+
+	var copies []calendar.Event
+	for n := 1; n <= count; n++ {
+		for _, event := range events {
+			duplicate := *event
+			duplicate.Id = 0
+			duplicate.Time = calendar.ApplyOffset(event.Time, span*n, 0)
+			copies = append(copies, duplicate)
+		}
+	}
+	if _, ok := av.EventManager.AddEvents(copies, fmt.Sprintf("duplicate %d %s", len(copies), pluralEvents(len(copies)))); !ok {
+		// Error is handled by EventManager internally
+		return nil
+	}
+	av.ExitVisual()
+	return nil
+}
+
+// pluralEvents returns "event" or "events" to follow a count
+func pluralEvents(n int) string {
+	if n == 1 {
+		return "event"
+	}
+	return "events"
+}
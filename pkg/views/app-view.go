@@ -8,6 +8,9 @@ import (
 	"github.com/samuelstranges/chronos/internal/config"
 	"github.com/samuelstranges/chronos/internal/database"
 	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/jumplist"
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/samuelstranges/chronos/internal/registers"
 	"github.com/samuelstranges/chronos/internal/utils"
 	"github.com/samuelstranges/chronos/internal/weather"
 	"github.com/jroimartin/gocui"
@@ -31,13 +34,23 @@ type AppView struct {
 	colorPickerActive bool
 	durationEvent *EventView
 	durationPopupActive bool
-	copiedEvent       *calendar.Event
+	// registers hold what yank and delete copied; register is the one named with " for the
+	// next yank, delete or paste, 0 for none
+	registers registers.Registers
+	register  rune
+
+	// drag is the mouse button held down in the calendar, clicks the click before
+	drag   *mouseDrag
+	clicks DoubleClicks
 	
 	// Search functionality
 	searchQuery       string
 	searchMatches     []*calendar.Event
 	currentMatchIndex int
 	isSearchActive    bool
+
+	// lastFilter names the filter F turns back on
+	lastFilter string
 	
 	// View initialization
 	initialViewMode   string
@@ -45,6 +58,12 @@ type AppView struct {
 	
 	// Weather functionality
 	weatherCache      *weather.WeatherCache
+
+	// actions holds what every keymap action does, for running them from the command line
+	actions map[string]func(*gocui.Gui, *gocui.View) error
+
+	// jumps remembers where big jumps started, for Ctrl-o and Ctrl-i
+	jumps jumplist.List
 }
 
 
@@ -53,7 +72,9 @@ func NewAppView(g *gocui.Gui, db *database.Database, cfg *config.Config) *AppVie
 	t := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
 
 	c := calendar.NewCalendar(calendar.NewDay(t))
+	c.SetWeekStart(config.GetWeekStart(cfg))
 	em := eventmanager.NewEventManager(db)
+	em.SetOverlapPolicy(eventmanager.OverlapPolicy(config.GetOverlapPolicy(cfg)))
 
 	av := &AppView{
 		BaseView:     NewBaseView("app"),
@@ -71,7 +92,16 @@ func NewAppView(g *gocui.Gui, db *database.Database, cfg *config.Config) *AppVie
 	titleView.SetViewMode(defaultView)
 	av.AddChild("title", titleView)
 	av.AddChild("popup", NewEvenPopup(g, c, db, av.EventManager, cfg))
-	av.AddChild("main", NewMainView(c, db, av.EventManager))
+	mainView := NewMainView(c, db, av.EventManager)
+	mainView.CalendarView.RollingDays = config.GetRollingDays(cfg)
+	mainView.CalendarView.CompactWeeks = config.GetCompactWeeks(cfg)
+	mainView.CalendarView.AgendaView.RangeDays = config.GetAgendaDays(cfg)
+	mainView.CalendarView.AgendaView.SkipEmptyDays = config.IsAgendaSkipEmptyDays(cfg)
+	av.AddChild("main", mainView)
+	detailView := NewDetailView()
+	detailView.Position = config.GetDetailPanePosition(cfg)
+	detailView.IsVisible = config.IsDetailPaneShown(cfg)
+	av.AddChild("detail", detailView)
 	
 	// Set up error handler for EventManager after popup is created
 	av.setupErrorHandler(g)
@@ -81,6 +111,9 @@ func NewAppView(g *gocui.Gui, db *database.Database, cfg *config.Config) *AppVie
 	av.initialViewMode = defaultView
 	
 	av.AddChild("keybinds", NewKeybindsView())
+	av.AddChild("marks", NewMarksView())
+	av.AddChild("finder", NewFinderView())
+	av.AddChild("commandline", NewCommandLineView())
 	
 	// Preload weather data if enabled to avoid lag when switching views
 	av.preloadWeatherData()
@@ -91,14 +124,19 @@ func NewAppView(g *gocui.Gui, db *database.Database, cfg *config.Config) *AppVie
 // setupErrorHandler configures the EventManager to show error messages via popup
 func (av *AppView) setupErrorHandler(g *gocui.Gui) {
 	av.EventManager.SetErrorHandler(func(title, message string) {
-		if popup, ok := av.GetChild("popup"); ok {
-			if popupView, ok := popup.(*EventPopupView); ok {
-				popupView.ShowErrorMessage(g, title, message)
-			}
-		}
+		av.ShowErrorMessage(g, title, message)
 	})
 }
 
+// ShowErrorMessage displays an error in the popup view
+func (av *AppView) ShowErrorMessage(g *gocui.Gui, title, message string) {
+	if popup, ok := av.GetChild("popup"); ok {
+		if popupView, ok := popup.(*EventPopupView); ok {
+			popupView.ShowErrorMessage(g, title, message)
+		}
+	}
+}
+
 func (av *AppView) Layout(g *gocui.Gui) error {
 	return av.Update(g)
 }
@@ -145,6 +183,7 @@ func (av *AppView) Update(g *gocui.Gui) error {
 	
 	// Update current event in title
 	av.updateCurrentEvent()
+	av.updateTitleRange()
 
 	// Initialize the view mode on first update
 	if !av.viewInitialized {
@@ -153,7 +192,10 @@ func (av *AppView) Update(g *gocui.Gui) error {
 	}
 
 
+	av.updateVisualMode()
+	av.updateGrabMode()
 	av.updateChildViewProperties()
+	av.updateDetailEvent(g)
 
 	if err = av.UpdateChildren(g); err != nil {
 		return err
@@ -173,8 +215,16 @@ func (av *AppView) Update(g *gocui.Gui) error {
 // initializeViewMode sets up the initial view mode after GUI is initialized
 func (av *AppView) initializeViewMode(g *gocui.Gui) {
 	switch av.initialViewMode {
+	case "day":
+		av.SwitchToDayView(g)
+	case "days":
+		av.SwitchToDaysView(g)
 	case "month":
 		av.SwitchToMonthView(g)
+	case "weeks":
+		av.SwitchToWeeksView(g)
+	case "year":
+		av.SwitchToYearView(g)
 	case "agenda":
 		av.SwitchToAgendaView(g)
 	default:
@@ -191,6 +241,7 @@ func (av *AppView) updateEventsFromDatabase() error {
 		if err != nil {
 			return err
 		}
+		events = av.Calendar.Filtered(events)
 
 		// Convert UTC events to local time for display
 		localEvents := make([]*calendar.Event, len(events))
@@ -202,6 +253,7 @@ func (av *AppView) updateEventsFromDatabase() error {
 
 		v.Events = localEvents
 		v.SortEventsByTime()
+		av.grabPreview(v)
 	}
 
 	return nil
@@ -209,6 +261,7 @@ func (av *AppView) updateEventsFromDatabase() error {
 
 
 func (av *AppView) JumpToToday() {
+	av.recordJump()
 	av.Calendar.JumpToToday()
 }
 
@@ -221,6 +274,7 @@ func (av *AppView) UpdateToPrevWeek() {
 }
 
 func (av *AppView) UpdateToNextMonth() {
+	av.recordJump()
 	av.Calendar.UpdateToNextMonth()
 	
 	// Also update the month view's current month if we're in month mode
@@ -234,6 +288,7 @@ func (av *AppView) UpdateToNextMonth() {
 }
 
 func (av *AppView) UpdateToPrevMonth() {
+	av.recordJump()
 	av.Calendar.UpdateToPrevMonth()
 	
 	// Also update the month view's current month if we're in month mode
@@ -246,11 +301,45 @@ func (av *AppView) UpdateToPrevMonth() {
 	}
 }
 
+// ShiftMonth moves the selected date by n months, keeping the day of the month where possible
+func (av *AppView) ShiftMonth(g *gocui.Gui, n int) {
+	oldMonth := av.Calendar.CurrentDay.Date.Month()
+	av.Calendar.ShiftMonth(n)
+	if av.IsMonthMode() {
+		av.handleMonthChange(g, oldMonth)
+	}
+	av.UpdateCurrentView(g)
+}
+
 func (av *AppView) SwitchToWeekView(g *gocui.Gui) error {
+	return av.switchToTimeline(g, "week")
+}
+
+// SwitchToDayView shows the selected day as a single wide column
+func (av *AppView) SwitchToDayView(g *gocui.Gui) error {
+	return av.switchToTimeline(g, "day")
+}
+
+// SwitchToDaysView shows a rolling number of days starting with the selected one
+func (av *AppView) SwitchToDaysView(g *gocui.Gui) error {
+	return av.switchToTimeline(g, "days")
+}
+
+// switchToTimeline switches to the week, day or rolling days view, which share the time column
+// and keybindings
+func (av *AppView) switchToTimeline(g *gocui.Gui, mode string) error {
 	if mainView, ok := av.GetChild("main"); ok {
 		if mv, ok := mainView.(*MainView); ok {
 			// Switch the view mode - this will cause the week view to be recreated properly
-			err := mv.SwitchToWeekView(g)
+			var err error
+			switch mode {
+			case "day":
+				err = mv.SwitchToDayView(g)
+			case "days":
+				err = mv.SwitchToDaysView(g)
+			default:
+				err = mv.SwitchToWeekView(g)
+			}
 			if err != nil {
 				return err
 			}
@@ -258,7 +347,7 @@ func (av *AppView) SwitchToWeekView(g *gocui.Gui) error {
 			// Update title view mode
 			if titleView, ok := av.GetChild("title"); ok {
 				if tv, ok := titleView.(*TitleView); ok {
-					tv.SetViewMode("week")
+					tv.SetViewMode(mode)
 				}
 			}
 			
@@ -278,6 +367,45 @@ func (av *AppView) SwitchToWeekView(g *gocui.Gui) error {
 	return nil
 }
 
+// SwitchToWeeksView shows a compact grid of a few weeks around the selected day
+func (av *AppView) SwitchToWeeksView(g *gocui.Gui) error {
+	if mainView, ok := av.GetChild("main"); ok {
+		if mv, ok := mainView.(*MainView); ok {
+			if err := mv.SwitchToWeeksView(g); err != nil {
+				return err
+			}
+			
+			// Update title view mode
+			if titleView, ok := av.GetChild("title"); ok {
+				if tv, ok := titleView.(*TitleView); ok {
+					tv.SetViewMode("weeks")
+				}
+			}
+			
+			return nil
+		}
+	}
+	return nil
+}
+
+// ResizeLayout adds delta days to the rolling days view or delta weeks to the compact weeks grid
+func (av *AppView) ResizeLayout(g *gocui.Gui, delta int) error {
+	if mainView, ok := av.GetChild("main"); ok {
+		if mv, ok := mainView.(*MainView); ok && mv.CalendarView != nil {
+			cv := mv.CalendarView
+			switch cv.ViewMode {
+			case "days":
+				return cv.SetRollingDays(g, cv.RollingDays+delta)
+			case "weeks":
+				return cv.SetCompactWeeks(g, cv.CompactWeeks+delta)
+			case "agenda":
+				cv.AgendaView.StepRange(delta)
+			}
+		}
+	}
+	return nil
+}
+
 func (av *AppView) SwitchToMonthView(g *gocui.Gui) error {
 	if mainView, ok := av.GetChild("main"); ok {
 		if mv, ok := mainView.(*MainView); ok {
@@ -286,6 +414,11 @@ func (av *AppView) SwitchToMonthView(g *gocui.Gui) error {
 				return err
 			}
 			
+			// Show the month of the selected date, which may have changed in another view
+			if mv.CalendarView.MonthView != nil {
+				mv.CalendarView.MonthView.SetCurrentMonth(av.Calendar.CurrentDay.Date)
+			}
+			
 			// Update title view mode
 			if titleView, ok := av.GetChild("title"); ok {
 				if tv, ok := titleView.(*TitleView); ok {
@@ -299,6 +432,28 @@ func (av *AppView) SwitchToMonthView(g *gocui.Gui) error {
 	return nil
 }
 
+// SwitchToYearView shows the selected year as twelve mini-months shaded by booked hours
+func (av *AppView) SwitchToYearView(g *gocui.Gui) error {
+	if mainView, ok := av.GetChild("main"); ok {
+		if mv, ok := mainView.(*MainView); ok {
+			err := mv.SwitchToYearView(g)
+			if err != nil {
+				return err
+			}
+			
+			// Update title view mode
+			if titleView, ok := av.GetChild("title"); ok {
+				if tv, ok := titleView.(*TitleView); ok {
+					tv.SetViewMode("year")
+				}
+			}
+			
+			return nil
+		}
+	}
+	return nil
+}
+
 func (av *AppView) SwitchToAgendaView(g *gocui.Gui) error {
 	if mainView, ok := av.GetChild("main"); ok {
 		if mv, ok := mainView.(*MainView); ok {
@@ -324,10 +479,22 @@ func (av *AppView) ToggleView(g *gocui.Gui) error {
 	currentMode := av.GetViewMode()
 	switch currentMode {
 	case "week":
-		// Week → Month
+		// Week → Day
+		return av.SwitchToDayView(g)
+	case "day":
+		// Day → Days
+		return av.SwitchToDaysView(g)
+	case "days":
+		// Days → Month
 		return av.SwitchToMonthView(g)
 	case "month":
-		// Month → Agenda
+		// Month → Weeks
+		return av.SwitchToWeeksView(g)
+	case "weeks":
+		// Weeks → Year
+		return av.SwitchToYearView(g)
+	case "year":
+		// Year → Agenda
 		return av.SwitchToAgendaView(g)
 	case "agenda":
 		// Agenda → Week
@@ -343,6 +510,24 @@ func (av *AppView) IsMonthMode() bool {
 	return av.GetViewMode() == "month"
 }
 
+// isTimelineMode returns true in the week, day and rolling days views, which show time columns
+func (av *AppView) isTimelineMode() bool {
+	mode := av.GetViewMode()
+	return mode == "week" || mode == "day" || mode == "days"
+}
+
+// isGridMode returns true in the month view and the compact weeks grid, which share the month
+// day cells
+func (av *AppView) isGridMode() bool {
+	mode := av.GetViewMode()
+	return mode == "month" || mode == "weeks"
+}
+
+// IsYearMode returns true if currently in year view mode
+func (av *AppView) IsYearMode() bool {
+	return av.GetViewMode() == "year"
+}
+
 // IsAgendaMode returns true if currently in agenda view mode
 func (av *AppView) IsAgendaMode() bool {
 	return av.GetViewMode() == "agenda"
@@ -370,38 +555,29 @@ func (av *AppView) GetCurrentViewName() string {
 func (av *AppView) calculateMonthDayViewName() string {
 	currentDate := av.Calendar.CurrentDay.Date
 	
-	// Get the current month being displayed in month view
-	var currentMonth time.Time
+	// Get the first cell of the grid displayed in the month view
+	startOfGrid := calendar.StartOfWeek(time.Date(currentDate.Year(), currentDate.Month(), 1, 0, 0, 0, 0, currentDate.Location()), av.Calendar.WeekStart)
+	cells := 42
 	if mainView, ok := av.GetChild("main"); ok {
 		if mv, ok := mainView.(*MainView); ok {
 			if mv.CalendarView != nil && mv.CalendarView.MonthView != nil {
-				currentMonth = mv.CalendarView.MonthView.CurrentMonth
+				monthView := mv.CalendarView.MonthView
+				monthView.syncWeeks(currentDate)
+				startOfGrid = monthView.GridStart()
+				cells = monthView.cellCount()
 			}
 		}
 	}
 	
-	// If we can't find the month view, default to current date's month
-	if currentMonth.IsZero() {
-		currentMonth = currentDate
-	}
+	// Calculate the index of the current date in the grid
+	// (rounded, as days around a DST change are not 24 hours long)
+	daysDiff := int((time.Date(currentDate.Year(), currentDate.Month(), currentDate.Day(), 0, 0, 0, 0, currentDate.Location()).Sub(startOfGrid).Hours() + 12) / 24)
 	
-	// Get the first day of the displayed month
-	firstDay := time.Date(currentMonth.Year(), currentMonth.Month(), 1, 0, 0, 0, 0, currentMonth.Location())
-	
-	// Find the Sunday of the week containing the first day (start of month grid)
-	startOfGrid := firstDay
-	for startOfGrid.Weekday() != time.Sunday {
-		startOfGrid = startOfGrid.AddDate(0, 0, -1)
-	}
-	
-	// Calculate the index (0-41) of the current date in the month grid
-	daysDiff := int(currentDate.Sub(startOfGrid).Hours() / 24)
-	
-	// Ensure the index is within bounds (0-41)
+	// Ensure the index is within bounds
 	if daysDiff < 0 {
 		daysDiff = 0
-	} else if daysDiff > 41 {
-		daysDiff = 41
+	} else if daysDiff > cells-1 {
+		daysDiff = cells - 1
 	}
 	
 	return fmt.Sprintf("monthday_%d", daysDiff)
@@ -439,15 +615,29 @@ func (av *AppView) handleMonthChange(g *gocui.Gui, oldMonth time.Month) {
 	}
 }
 
-// moveAgendaSelection moves the selection in agenda view
-func (av *AppView) moveAgendaSelection(direction int) {
+// agendaView returns the agenda view, or nil before the main view exists
+func (av *AppView) agendaView() *AgendaView {
 	if mainView, ok := av.GetChild("main"); ok {
-		if mv, ok := mainView.(*MainView); ok {
-			if mv.CalendarView != nil && mv.CalendarView.AgendaView != nil {
-				mv.CalendarView.AgendaView.MoveSelection(direction)
-			}
+		if mv, ok := mainView.(*MainView); ok && mv.CalendarView != nil {
+			return mv.CalendarView.AgendaView
 		}
 	}
+	return nil
+}
+
+// moveAgendaSelection moves the selection in agenda view
+func (av *AppView) moveAgendaSelection(direction int) {
+	if agenda := av.agendaView(); agenda != nil {
+		agenda.MoveSelection(direction)
+	}
+}
+
+// ToggleAgendaEmptyDays shows or hides the agenda's days without events
+func (av *AppView) ToggleAgendaEmptyDays(g *gocui.Gui) {
+	if agenda := av.agendaView(); agenda != nil {
+		agenda.ToggleSkipEmptyDays()
+	}
+	av.UpdateCurrentView(g)
 }
 
 // updateAgendaDate updates the agenda view when the date changes
@@ -487,8 +677,8 @@ func (av *AppView) UpdateToPrevDay(g *gocui.Gui) {
 
 func (av *AppView) UpdateToNextTime(g *gocui.Gui) {
 	
-	if av.IsMonthMode() {
-		// In month mode, j/down should move down one week (7 days)
+	if av.isGridMode() || av.IsYearMode() {
+		// In month, weeks and year mode, j/down should move down one week (7 days)
 		oldMonth := av.Calendar.CurrentDay.Date.Month()
 		for i := 0; i < 7; i++ {
 			av.Calendar.UpdateToNextDay()
@@ -520,8 +710,8 @@ func (av *AppView) UpdateToNextTime(g *gocui.Gui) {
 
 func (av *AppView) UpdateToPrevTime(g *gocui.Gui) {
 	
-	if av.IsMonthMode() {
-		// In month mode, k/up should move up one week (7 days)
+	if av.isGridMode() || av.IsYearMode() {
+		// In month, weeks and year mode, k/up should move up one week (7 days)
 		oldMonth := av.Calendar.CurrentDay.Date.Month()
 		for i := 0; i < 7; i++ {
 			av.Calendar.UpdateToPrevDay()
@@ -554,11 +744,53 @@ func (av *AppView) UpdateToPrevTime(g *gocui.Gui) {
 
 
 func (av *AppView) ReturnToMainView(g *gocui.Gui) error {
-	viewName := WeekdayNames[av.Calendar.CurrentDay.Date.Weekday()]
+	viewName := av.selectedColumnName()
 	g.SetCurrentView(viewName)
 	return av.UpdateCurrentView(g)
 }
 
+// updateTitleRange tells the title view which days the compact weeks grid shows
+func (av *AppView) updateTitleRange() {
+	mainView, ok := av.GetChild("main")
+	if !ok {
+		return
+	}
+	mv, ok := mainView.(*MainView)
+	if !ok || mv.CalendarView == nil {
+		return
+	}
+	if mv.CalendarView.ViewMode == "agenda" {
+		av.updateTitleAgenda(mv.CalendarView.AgendaView)
+		return
+	}
+	if mv.CalendarView.ViewMode != "weeks" {
+		return
+	}
+	monthView := mv.CalendarView.MonthView
+	monthView.syncWeeks(av.Calendar.CurrentDay.Date)
+	if titleView, ok := av.GetChild("title"); ok {
+		if tv, ok := titleView.(*TitleView); ok {
+			tv.RangeStart = monthView.GridStart()
+			tv.RangeEnd = tv.RangeStart.AddDate(0, 0, monthView.cellCount()-1)
+		}
+	}
+}
+
+// updateTitleAgenda shows the agenda's date range, or the number of search results it lists
+func (av *AppView) updateTitleAgenda(agenda *AgendaView) {
+	if err := agenda.Refresh(); err != nil {
+		return
+	}
+	if titleView, ok := av.GetChild("title"); ok {
+		if tv, ok := titleView.(*TitleView); ok {
+			tv.RangeStart = agenda.RangeStart()
+			tv.RangeEnd = tv.RangeStart.AddDate(0, 0, agenda.RangeDays-1)
+			tv.AgendaSearch = agenda.Search != nil
+			tv.AgendaResults = len(agenda.Events)
+		}
+	}
+}
+
 // updateCurrentEvent finds and sets the current event in the title view
 func (av *AppView) updateCurrentEvent() {
 	if titleView, ok := av.GetChild("title"); ok {
@@ -596,6 +828,15 @@ func (av *AppView) findCurrentEvent() string {
 
 
 
+// SetKeymap sets the keymap the help is generated from
+func (av *AppView) SetKeymap(km *keymap.Keymap) {
+	if view, ok := av.GetChild("keybinds"); ok {
+		if keybindsView, ok := view.(*KeybindsView); ok {
+			keybindsView.Keymap = km
+		}
+	}
+}
+
 func (av *AppView) ShowKeybinds(g *gocui.Gui) error {
 	if view, ok := av.GetChild("keybinds"); ok {
 		if keybindsView, ok := view.(*KeybindsView); ok {
@@ -645,22 +886,88 @@ func (av *AppView) updateChildViewProperties() {
 		)
 	}
 
+	y := av.Y + titleHeight + 1
+	mainHeight := av.H - y
+
+	// The detail pane takes its room from the right or bottom of the calendar
+	if detail := av.detailView(); detail != nil && detail.IsVisible {
+		if detail.Position == "bottom" {
+			mainHeight -= DetailPaneHeight + 1
+			detail.SetProperties(av.X+sideViewWidth+1, y+mainHeight+1, mainViewWidth, DetailPaneHeight)
+		} else {
+			mainViewWidth -= DetailPaneWidth + 1
+			detail.SetProperties(av.X+sideViewWidth+mainViewWidth+2, y, DetailPaneWidth, mainHeight)
+		}
+	}
+
 	if mainView, ok := av.GetChild("main"); ok {
-		y := av.Y + titleHeight + 1
 		mainView.SetProperties(
 			av.X+sideViewWidth+1,
 			y,
 			mainViewWidth,
-			av.H-y,
+			mainHeight,
 		)
 	}
 
 }
 
+// detailView returns the event detail pane
+func (av *AppView) detailView() *DetailView {
+	if view, ok := av.GetChild("detail"); ok {
+		if detail, ok := view.(*DetailView); ok {
+			return detail
+		}
+	}
+	return nil
+}
+
+// updateDetailEvent shows the hovered event in the detail pane
+func (av *AppView) updateDetailEvent(g *gocui.Gui) {
+	detail := av.detailView()
+	if detail == nil || !detail.IsVisible {
+		return
+	}
+	if eventView, ok := av.GetHoveredOnView(g).(*EventView); ok {
+		detail.SetEvent(eventView.Event)
+	} else {
+		detail.SetEvent(nil)
+	}
+}
+
+// ToggleDetailPane shows or hides the event detail pane
+func (av *AppView) ToggleDetailPane() {
+	if detail := av.detailView(); detail != nil {
+		detail.IsVisible = !detail.IsVisible
+	}
+}
+
+// ToggleDetailPanePosition moves the event detail pane between the right and the bottom
+func (av *AppView) ToggleDetailPanePosition() {
+	if detail := av.detailView(); detail != nil {
+		if detail.Position == "bottom" {
+			detail.Position = "right"
+		} else {
+			detail.Position = "bottom"
+		}
+		detail.IsVisible = true
+	}
+}
+
+// ScrollDetailPane scrolls the event detail pane by half its height, down for positive directions
+func (av *AppView) ScrollDetailPane(direction int) {
+	if detail := av.detailView(); detail != nil && detail.IsVisible {
+		step := detail.H / 2
+		if step < 1 {
+			step = 1
+		}
+		detail.Scroll(direction * step)
+	}
+}
+
 func (av *AppView) UpdateCurrentView(g *gocui.Gui) error {
 	if view, ok := av.GetChild("popup"); ok {
 		if popupView, ok := view.(*EventPopupView); ok {
-			if popupView.IsVisible {
+			if popupView.IsVisible || popupView.ConflictDialogOpen() {
 				return nil
 			}
 		}
@@ -674,11 +981,37 @@ func (av *AppView) UpdateCurrentView(g *gocui.Gui) error {
 			}
 		}
 	}
+	if mv := av.marksView(); mv != nil && mv.IsVisible {
+		g.Cursor = false
+		g.SetCurrentView(mv.Name)
+		return nil
+	}
+	if fv := av.finderView(); fv != nil && fv.IsVisible {
+		g.Cursor = true
+		g.SetCurrentView(fv.Name)
+		return nil
+	}
+	if clv := av.commandLine(); clv != nil {
+		if clv.IsVisible {
+			g.Cursor = true
+			g.SetCurrentView(clv.Name)
+			return nil
+		}
+		if clv.HasFocusedOutput() {
+			g.Cursor = false
+			g.SetCurrentView(commandOutputName)
+			return nil
+		}
+	}
 
 	g.Cursor = true
 
-	if av.IsMonthMode() {
-		// In month mode, focus on the appropriate month day view
+	if av.IsYearMode() {
+		// The year view draws its own selection
+		g.Cursor = false
+		g.SetCurrentView("year")
+	} else if av.isGridMode() {
+		// In month and weeks mode, focus on the appropriate month day view
 		currentViewName := av.calculateMonthDayViewName()
 		g.SetCurrentView(currentViewName)
 		if g.CurrentView() != nil {
@@ -695,9 +1028,9 @@ func (av *AppView) UpdateCurrentView(g *gocui.Gui) error {
 		}
 	} else {
 		// In week mode, use weekday names
-		g.SetCurrentView(WeekdayNames[av.Calendar.CurrentDay.Date.Weekday()])
+		g.SetCurrentView(av.selectedColumnName())
 		g.CurrentView().BgColor = gocui.Attribute(termbox.ColorBlack)
-		g.CurrentView().SetCursor(1, av.GetCursorY())
+		g.CurrentView().SetCursor(av.getCursorX(), av.GetCursorY())
 	}
 
 	return nil
@@ -720,7 +1053,7 @@ func (av *AppView) GetHoveredOnView(g *gocui.Gui) View {
 		return nil
 	} else {
 		// Week mode logic (and month mode for now)
-		viewName := WeekdayNames[av.Calendar.CurrentDay.Date.Weekday()]
+		viewName := av.selectedColumnName()
 		var hoveredView View
 
 		if view, ok := av.FindChildView(viewName); ok {
@@ -737,6 +1070,35 @@ func (av *AppView) GetHoveredOnView(g *gocui.Gui) View {
 	}
 }
 
+// selectedColumnName returns the name of the day column showing the selected day
+func (av *AppView) selectedColumnName() string {
+	if name := av.columnNameForDate(av.Calendar.CurrentDay.Date); name != "" {
+		return name
+	}
+	return DayColumnNames[0]
+}
+
+// columnNameForDate returns the name of the day column showing date, or "" if it is not shown
+func (av *AppView) columnNameForDate(date time.Time) string {
+	if i := av.Calendar.DayIndex(date); i >= 0 {
+		return DayColumnNames[i]
+	}
+	return ""
+}
+
+// getCursorX returns the cursor column in the current day, moving it onto the hovered event's
+// column when overlapping events are drawn side by side
+func (av *AppView) getCursorX() int {
+	if view, ok := av.FindChildView(av.selectedColumnName()); ok {
+		if dayView, ok := view.(*DayView); ok {
+			if eventView, ok := dayView.IsOnEvent(av.GetCursorY()); ok {
+				return eventView.X - dayView.X + 1
+			}
+		}
+	}
+	return 1
+}
+
 func (av *AppView) GetCursorY() int {
 	y := 0
 
@@ -837,7 +1199,7 @@ func (av *AppView) preloadWeatherData() {
 }
 
 
-// updateWeekViewWeather updates weather icons in week view if currently active
+// updateWeekViewWeather updates weather icons in the week or day view if currently active
 func (av *AppView) updateWeekViewWeather() error {
 	if !config.IsWeatherEnabled(av.Config) {
 		return nil
@@ -845,7 +1207,7 @@ func (av *AppView) updateWeekViewWeather() error {
 	
 	if mainView, ok := av.GetChild("main"); ok {
 		if mv, ok := mainView.(*MainView); ok {
-			if mv.CalendarView != nil && mv.CalendarView.ViewMode == "week" && mv.CalendarView.WeekView != nil {
+			if mv.CalendarView != nil && mv.CalendarView.isTimeline() && mv.CalendarView.WeekView != nil {
 				// Don't fail the UI update if weather fails - just skip weather display
 				mv.CalendarView.WeekView.UpdateWeatherData(av.Config, av.weatherCache)
 			}
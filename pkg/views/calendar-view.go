@@ -14,11 +14,17 @@ type CalendarView struct {
 	
 	Calendar *calendar.Calendar
 	Database *database.Database
-	ViewMode string // "week", "month", or "agenda"
+	ViewMode string // "week", "day", "days", "month", "weeks", "year", or "agenda"
+	
+	// RollingDays is the number of columns in the "days" mode; CompactWeeks the number of rows in
+	// the "weeks" grid
+	RollingDays  int
+	CompactWeeks int
 	
 	TimeView   *TimeView
 	WeekView   *WeekView
 	MonthView  *MonthView
+	YearView   *YearView
 	AgendaView *AgendaView
 }
 
@@ -28,14 +34,17 @@ func NewCalendarView(c *calendar.Calendar, db *database.Database, em *eventmanag
 		Calendar: c,
 		Database: db,
 		ViewMode: "week", // Default to week view
+		RollingDays:  4,
+		CompactWeeks: 2,
 	}
 	
-	// Create the time view (used by week view)
+	// Create the time view (used by the week and day views)
 	cv.TimeView = NewTimeView()
 	
 	// Create all views but only add the active one as a child
 	cv.WeekView = NewWeekView(c, cv.TimeView)
 	cv.MonthView = NewMonthView(c, em)
+	cv.YearView = NewYearView(c, em)
 	cv.AgendaView = NewAgendaView(c, em)
 	
 	// Start with week view
@@ -46,9 +55,10 @@ func NewCalendarView(c *calendar.Calendar, db *database.Database, em *eventmanag
 }
 
 func (cv *CalendarView) Update(g *gocui.Gui) error {
-	// If we're in week mode but don't have a WeekView, create it now (after events are loaded)
-	if cv.ViewMode == "week" && cv.WeekView == nil {
+	// If we're in a timeline mode but don't have a WeekView, create it now (after events are loaded)
+	if cv.isTimeline() && cv.WeekView == nil {
 		cv.WeekView = NewWeekView(cv.Calendar, cv.TimeView)
+		cv.WeekView.SingleDay = cv.ViewMode == "day"
 		cv.AddChild("active", cv.WeekView)
 	}
 	
@@ -62,8 +72,18 @@ func (cv *CalendarView) Update(g *gocui.Gui) error {
 	return nil
 }
 
+// isTimeline reports whether the current mode shows days as time columns next to the TimeView
+func (cv *CalendarView) isTimeline() bool {
+	return cv.ViewMode == "week" || cv.ViewMode == "day" || cv.ViewMode == "days"
+}
+
+// isGrid reports whether the current mode shows days as cells of the month grid
+func (cv *CalendarView) isGrid() bool {
+	return cv.ViewMode == "month" || cv.ViewMode == "weeks"
+}
+
 func (cv *CalendarView) updateChildViewProperties() {
-	if cv.ViewMode == "week" {
+	if cv.isTimeline() {
 		// Position time view and week view
 		if cv.TimeView != nil {
 			cv.TimeView.SetProperties(
@@ -82,7 +102,7 @@ func (cv *CalendarView) updateChildViewProperties() {
 				cv.H,
 			)
 		}
-	} else if cv.ViewMode == "month" {
+	} else if cv.isGrid() {
 		// Month view takes the full area
 		if cv.MonthView != nil {
 			cv.MonthView.SetProperties(
@@ -92,6 +112,16 @@ func (cv *CalendarView) updateChildViewProperties() {
 				cv.H,
 			)
 		}
+	} else if cv.ViewMode == "year" {
+		// Year view takes the full area
+		if cv.YearView != nil {
+			cv.YearView.SetProperties(
+				cv.X,
+				cv.Y,
+				cv.W,
+				cv.H,
+			)
+		}
 	} else if cv.ViewMode == "agenda" {
 		// Agenda view takes the full area
 		if cv.AgendaView != nil {
@@ -106,28 +136,61 @@ func (cv *CalendarView) updateChildViewProperties() {
 }
 
 func (cv *CalendarView) SwitchToWeekView(g *gocui.Gui) error {
-	if cv.ViewMode == "week" {
-		return nil // Already in week view
+	return cv.switchToTimeline(g, "week")
+}
+
+// SwitchToDayView shows the selected day as a single full-width column
+func (cv *CalendarView) SwitchToDayView(g *gocui.Gui) error {
+	return cv.switchToTimeline(g, "day")
+}
+
+// SwitchToDaysView shows RollingDays columns starting at the selected day
+func (cv *CalendarView) SwitchToDaysView(g *gocui.Gui) error {
+	return cv.switchToTimeline(g, "days")
+}
+
+// SetRollingDays changes the number of columns of the "days" mode, redrawing it if active
+func (cv *CalendarView) SetRollingDays(g *gocui.Gui, days int) error {
+	if days < 1 || days > MaxDayColumns {
+		return nil
 	}
-	
+	cv.RollingDays = days
+	if cv.ViewMode != "days" {
+		return nil
+	}
+	return cv.enterTimeline(g, "days")
+}
+
+// switchToTimeline switches to the week, day or rolling days mode, which share the WeekView and
+// TimeView
+func (cv *CalendarView) switchToTimeline(g *gocui.Gui, mode string) error {
+	if cv.ViewMode == mode {
+		return nil // Already in this view
+	}
+	return cv.enterTimeline(g, mode)
+}
+
+// enterTimeline replaces the current views with a freshly laid out timeline
+func (cv *CalendarView) enterTimeline(g *gocui.Gui, mode string) error {
 	// Delete current view and all its children from gocui
-	if cv.ViewMode == "month" && cv.MonthView != nil {
-		if err := cv.deleteMonthViewFromGUI(g); err != nil {
-			return err
-		}
-	} else if cv.ViewMode == "agenda" && cv.AgendaView != nil {
-		if err := cv.deleteAgendaViewFromGUI(g); err != nil {
-			return err
-		}
+	if err := cv.deleteCurrentViewFromGUI(g); err != nil {
+		return err
 	}
 	
-	cv.ViewMode = "week"
+	cv.ViewMode = mode
+	
+	// The rolling layout has its own number of days; the week and day modes use Sunday to Saturday
+	if mode == "days" {
+		cv.Calendar.SetLayout(cv.RollingDays, true)
+	} else if cv.Calendar.Rolling {
+		cv.Calendar.SetLayout(7, false)
+	}
 	
 	// Don't create WeekView here - mark that we need to recreate it
 	// The main update cycle will handle creating it with current event data
 	cv.WeekView = nil
 	
-	// Remove month view from children 
+	// Remove the previous view from children
 	cv.children.Delete("active")
 	cv.AddChild("time", cv.TimeView)
 	// Don't add WeekView yet - will be created after events are loaded
@@ -136,23 +199,43 @@ func (cv *CalendarView) SwitchToWeekView(g *gocui.Gui) error {
 }
 
 func (cv *CalendarView) SwitchToMonthView(g *gocui.Gui) error {
-	if cv.ViewMode == "month" {
-		return nil // Already in month view
+	return cv.switchToGrid(g, "month", 0)
+}
+
+// SwitchToWeeksView shows CompactWeeks weeks of the month grid around the selected day
+func (cv *CalendarView) SwitchToWeeksView(g *gocui.Gui) error {
+	return cv.switchToGrid(g, "weeks", cv.CompactWeeks)
+}
+
+// SetCompactWeeks changes the number of rows of the "weeks" mode, redrawing it if active
+func (cv *CalendarView) SetCompactWeeks(g *gocui.Gui, weeks int) error {
+	if weeks < MinCompactWeeks || weeks > MaxCompactWeeks {
+		return nil
 	}
-	
-	// Delete week view and time view from gocui
-	if cv.WeekView != nil {
-		if err := cv.deleteWeekViewFromGUI(g); err != nil {
-			return err
-		}
+	cv.CompactWeeks = weeks
+	if cv.ViewMode != "weeks" {
+		return nil
 	}
-	if cv.TimeView != nil {
-		if err := g.DeleteView(cv.TimeView.Name); err != nil && err != gocui.ErrUnknownView {
-			return err
-		}
+	return cv.enterGrid(g, "weeks", weeks)
+}
+
+// switchToGrid switches to the month or compact weeks mode, which share the MonthView
+func (cv *CalendarView) switchToGrid(g *gocui.Gui, mode string, weeks int) error {
+	if cv.ViewMode == mode {
+		return nil // Already in this view
+	}
+	return cv.enterGrid(g, mode, weeks)
+}
+
+// enterGrid replaces the current views with the month grid showing weeks rows (0 for a month)
+func (cv *CalendarView) enterGrid(g *gocui.Gui, mode string, weeks int) error {
+	// Delete week/day or agenda views from gocui
+	if err := cv.deleteCurrentViewFromGUI(g); err != nil {
+		return err
 	}
 	
-	cv.ViewMode = "month"
+	cv.ViewMode = mode
+	cv.MonthView.SetWeeks(weeks)
 	
 	// Remove week/time views from children and add month view
 	cv.children.Delete("time")
@@ -162,29 +245,33 @@ func (cv *CalendarView) SwitchToMonthView(g *gocui.Gui) error {
 	return nil
 }
 
+// SwitchToYearView shows the twelve months of the selected year
+func (cv *CalendarView) SwitchToYearView(g *gocui.Gui) error {
+	if cv.ViewMode == "year" {
+		return nil // Already in year view
+	}
+	
+	if err := cv.deleteCurrentViewFromGUI(g); err != nil {
+		return err
+	}
+	
+	cv.ViewMode = "year"
+	
+	cv.children.Delete("time")
+	cv.children.Delete("active")
+	cv.AddChild("active", cv.YearView)
+	
+	return nil
+}
+
 func (cv *CalendarView) SwitchToAgendaView(g *gocui.Gui) error {
 	if cv.ViewMode == "agenda" {
 		return nil // Already in agenda view
 	}
 	
 	// Delete current view from gocui
-	if cv.ViewMode == "week" {
-		if cv.WeekView != nil {
-			if err := cv.deleteWeekViewFromGUI(g); err != nil {
-				return err
-			}
-		}
-		if cv.TimeView != nil {
-			if err := g.DeleteView(cv.TimeView.Name); err != nil && err != gocui.ErrUnknownView {
-				return err
-			}
-		}
-	} else if cv.ViewMode == "month" {
-		if cv.MonthView != nil {
-			if err := cv.deleteMonthViewFromGUI(g); err != nil {
-				return err
-			}
-		}
+	if err := cv.deleteCurrentViewFromGUI(g); err != nil {
+		return err
 	}
 	
 	cv.ViewMode = "agenda"
@@ -200,17 +287,31 @@ func (cv *CalendarView) SwitchToAgendaView(g *gocui.Gui) error {
 	return nil
 }
 
-func (cv *CalendarView) deleteAgendaViewFromGUI(g *gocui.Gui) error {
-	// Delete the main agenda view
-	if err := g.DeleteView(cv.AgendaView.Name); err != nil && err != gocui.ErrUnknownView {
-		return err
-	}
-	
-	// Delete all agenda event views
-	if cv.AgendaView != nil {
-		cv.AgendaView.clearEventViews(g)
+// deleteCurrentViewFromGUI removes the views of the current mode before switching to another
+func (cv *CalendarView) deleteCurrentViewFromGUI(g *gocui.Gui) error {
+	switch {
+	case cv.isTimeline():
+		if cv.WeekView != nil {
+			if err := cv.deleteWeekViewFromGUI(g); err != nil {
+				return err
+			}
+		}
+		if cv.TimeView != nil {
+			if err := g.DeleteView(cv.TimeView.Name); err != nil && err != gocui.ErrUnknownView {
+				return err
+			}
+		}
+	case cv.isGrid() && cv.MonthView != nil:
+		return cv.deleteMonthViewFromGUI(g)
+	case cv.ViewMode == "year" && cv.YearView != nil:
+		if err := g.DeleteView(cv.YearView.Name); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+	case cv.ViewMode == "agenda" && cv.AgendaView != nil:
+		if err := g.DeleteView(cv.AgendaView.Name); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
 	}
-	
 	return nil
 }
 
@@ -220,7 +321,7 @@ func (cv *CalendarView) deleteMonthViewFromGUI(g *gocui.Gui) error {
 		return err
 	}
 	
-	// Delete all month day views (monthday_0 to monthday_41)
+	// Delete all month day views (monthday_0 to monthday_41), as the compact grid may use fewer
 	for i := 0; i < 42; i++ {
 		dayViewName := fmt.Sprintf("monthday_%d", i)
 		if err := g.DeleteView(dayViewName); err != nil && err != gocui.ErrUnknownView {
@@ -234,9 +335,8 @@ func (cv *CalendarView) deleteMonthViewFromGUI(g *gocui.Gui) error {
 func (cv *CalendarView) deleteWeekViewFromGUI(g *gocui.Gui) error {
 	// Delete all event views from each day first
 	if cv.WeekView != nil {
-		weekdayNames := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
-		for _, dayName := range weekdayNames {
-			if dayView, ok := cv.WeekView.GetChild(dayName); ok {
+		for _, column := range DayColumnNames {
+			if dayView, ok := cv.WeekView.GetChild(column); ok {
 				if dv, ok := dayView.(*DayView); ok {
 					// Delete all event views in this day view
 					for pair := dv.children.Oldest(); pair != nil; pair = pair.Next() {
@@ -261,14 +361,12 @@ func (cv *CalendarView) deleteWeekViewFromGUI(g *gocui.Gui) error {
 		return err
 	}
 	
-	// Delete all weekday views
-	weekdayNames := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
-	for _, dayName := range weekdayNames {
-		if err := g.DeleteView(dayName); err != nil && err != gocui.ErrUnknownView {
+	// Delete all day column views
+	for _, column := range DayColumnNames {
+		if err := g.DeleteView(column); err != nil && err != gocui.ErrUnknownView {
 			// Continue deleting other views even if one fails
 		}
 	}
 	
 	return nil
 }
-
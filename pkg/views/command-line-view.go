@@ -0,0 +1,320 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samuelstranges/chronos/internal/command"
+	"github.com/jroimartin/gocui"
+)
+
+// commandOutputName is the view showing what the last command printed
+const commandOutputName = "commandline-output"
+
+// commandPromptName is the view showing the ':' before the command line
+const commandPromptName = "commandline-prompt"
+
+// commandPendingName is the view showing the keys typed towards an action
+const commandPendingName = "commandline-pending"
+
+// commandModeName is the view showing the mode, e.g. visual mode, at the left of the bottom row
+const commandModeName = "commandline-mode"
+
+// CommandLineView is the ':' prompt on the bottom row. It keeps the lines run from it to recall
+// with the arrow keys, completes commands with Tab, and shows what the last command printed.
+type CommandLineView struct {
+	*BaseView
+	IsVisible bool
+	// Pending is the keys typed towards an action, shown at the right of the bottom row
+	Pending string
+	// Mode names the mode the keys are in, e.g. "-- VISUAL --", shown at the left of the bottom
+	// row while nothing else is
+	Mode string
+
+	history []string
+	// historyIndex is the recalled line; len(history) is the line being typed, kept in draft
+	historyIndex int
+	draft        string
+
+	// completions are the lines Tab cycles through, until another key is pressed
+	completions     []string
+	completionIndex int
+
+	// output is what the last command printed. One line shows on the bottom row until the next
+	// key; more lines show above it and take the focus until a key is pressed.
+	output      []string
+	outputError bool
+}
+
+func NewCommandLineView() *CommandLineView {
+	return &CommandLineView{
+		BaseView: NewBaseView("commandline"),
+	}
+}
+
+// Open shows an empty prompt
+func (clv *CommandLineView) Open() {
+	clv.IsVisible = true
+	clv.historyIndex = len(clv.history)
+	clv.draft = ""
+	clv.completions = nil
+	clv.output = nil
+}
+
+// Close hides the prompt, remembering line in the history if it is not empty
+func (clv *CommandLineView) Close(line string) {
+	clv.IsVisible = false
+	line = strings.TrimSpace(line)
+	if line != "" && (len(clv.history) == 0 || clv.history[len(clv.history)-1] != line) {
+		clv.history = append(clv.history, line)
+	}
+}
+
+// History returns the lines run from the prompt, oldest first
+func (clv *CommandLineView) History() []string {
+	return clv.history
+}
+
+// SetOutput shows lines printed by a command, in red for errors
+func (clv *CommandLineView) SetOutput(lines []string, isError bool) {
+	clv.output = lines
+	clv.outputError = isError
+}
+
+// HasFocusedOutput reports whether the output is long enough to hold the focus
+func (clv *CommandLineView) HasFocusedOutput() bool {
+	return len(clv.output) > 1
+}
+
+// DismissOutput hides the one-line output
+func (clv *CommandLineView) DismissOutput() {
+	if !clv.HasFocusedOutput() {
+		clv.output = nil
+	}
+}
+
+func (clv *CommandLineView) Update(g *gocui.Gui) error {
+	if err := clv.updateMode(g); err != nil {
+		return err
+	}
+	if err := clv.updateOutput(g); err != nil {
+		return err
+	}
+	if err := clv.updatePending(g); err != nil {
+		return err
+	}
+
+	if !clv.IsVisible {
+		for _, name := range []string{commandPromptName, clv.Name} {
+			if err := g.DeleteView(name); err != nil && err != gocui.ErrUnknownView {
+				return err
+			}
+		}
+		return nil
+	}
+
+	maxX, maxY := g.Size()
+	prompt, err := g.SetView(commandPromptName, -1, maxY-2, 1, maxY)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		prompt.Frame = false
+		fmt.Fprint(prompt, ":")
+	}
+
+	v, err := g.SetView(clv.Name, 0, maxY-2, maxX, maxY)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = false
+		v.Editable = true
+		v.Editor = clv
+	}
+
+	g.SetViewOnTop(commandPromptName)
+	g.SetViewOnTop(clv.Name)
+	return nil
+}
+
+// updatePending draws the pending keys at the right of the bottom row
+func (clv *CommandLineView) updatePending(g *gocui.Gui) error {
+	if clv.Pending == "" {
+		if err := g.DeleteView(commandPendingName); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	maxX, maxY := g.Size()
+	v, err := g.SetView(commandPendingName, maxX-len(clv.Pending)-3, maxY-2, maxX-1, maxY)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Frame = false
+	v.Clear()
+	fmt.Fprint(v, clv.Pending)
+	g.SetViewOnTop(commandPendingName)
+	return nil
+}
+
+// updateMode draws the mode at the left of the bottom row when neither the prompt nor an output
+// is shown there
+func (clv *CommandLineView) updateMode(g *gocui.Gui) error {
+	if clv.Mode == "" || clv.IsVisible || len(clv.output) > 0 {
+		if err := g.DeleteView(commandModeName); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	_, maxY := g.Size()
+	v, err := g.SetView(commandModeName, -1, maxY-2, len(clv.Mode)+1, maxY)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Frame = false
+	v.Clear()
+	fmt.Fprint(v, "\x1b[1m"+clv.Mode+"\x1b[0m")
+	g.SetViewOnTop(commandModeName)
+	return nil
+}
+
+// updateOutput draws the output of the last command: one line on the bottom row, more in a box
+// above it
+func (clv *CommandLineView) updateOutput(g *gocui.Gui) error {
+	if len(clv.output) == 0 {
+		if err := g.DeleteView(commandOutputName); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	maxX, maxY := g.Size()
+	x0, y0, x1, y1 := -1, maxY-2, maxX, maxY
+	if clv.HasFocusedOutput() {
+		height := len(clv.output) + 2
+		if height > maxY-1 {
+			height = maxY - 1
+		}
+		x0, y0, x1, y1 = 0, maxY-1-height, maxX-1, maxY-2
+	}
+
+	v, err := g.SetView(commandOutputName, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Frame = clv.HasFocusedOutput()
+	v.Title = ""
+	if v.Frame {
+		v.Title = " Press any key "
+	}
+	v.FgColor = gocui.ColorDefault
+	if clv.outputError {
+		v.FgColor = gocui.ColorRed
+	}
+	// Any key closes the box, through the editor
+	v.Editable = true
+	v.Editor = gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+		clv.output = nil
+	})
+
+	v.Clear()
+	for _, line := range clv.output {
+		fmt.Fprintln(v, line)
+	}
+	g.SetViewOnTop(commandOutputName)
+	return nil
+}
+
+// Edit edits the command line: Tab completes, the arrow keys recall earlier lines, Ctrl-u
+// clears the line and Backspace on an empty line closes the prompt
+func (clv *CommandLineView) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	if !clv.IsVisible {
+		// Closed with Backspace; the view goes at the next layout
+		return
+	}
+	if key != gocui.KeyTab {
+		clv.completions = nil
+	}
+
+	switch key {
+	case gocui.KeyTab:
+		clv.complete(v)
+	case gocui.KeyArrowUp:
+		clv.recall(v, -1)
+	case gocui.KeyArrowDown:
+		clv.recall(v, 1)
+	case gocui.KeyCtrlU:
+		setCommandLine(v, "")
+	case gocui.KeyBackspace, gocui.KeyBackspace2:
+		if CommandLineText(v) == "" {
+			clv.Close("")
+			return
+		}
+		v.EditDelete(true)
+	case gocui.KeyEnter:
+		// Enter runs the line through its keybinding
+	default:
+		gocui.DefaultEditor.Edit(v, key, ch, mod)
+	}
+}
+
+// complete replaces the line with its next completion
+func (clv *CommandLineView) complete(v *gocui.View) {
+	if clv.completions == nil {
+		clv.completions = command.Complete(CommandLineText(v))
+		clv.completionIndex = 0
+		if len(clv.completions) == 0 {
+			return
+		}
+	} else {
+		clv.completionIndex = (clv.completionIndex + 1) % len(clv.completions)
+	}
+
+	line := clv.completions[clv.completionIndex]
+	// A single match is done with, so the next Tab completes the next word
+	if len(clv.completions) == 1 && !strings.HasSuffix(line, "=") {
+		line += " "
+		clv.completions = nil
+	}
+	setCommandLine(v, line)
+}
+
+// recall replaces the line with an earlier or later one from the history
+func (clv *CommandLineView) recall(v *gocui.View, direction int) {
+	index := clv.historyIndex + direction
+	if index < 0 || index > len(clv.history) {
+		return
+	}
+	if clv.historyIndex == len(clv.history) {
+		clv.draft = CommandLineText(v)
+	}
+
+	clv.historyIndex = index
+	if index == len(clv.history) {
+		setCommandLine(v, clv.draft)
+	} else {
+		setCommandLine(v, clv.history[index])
+	}
+}
+
+// CommandLineText returns the text typed in the command line view
+func CommandLineText(v *gocui.View) string {
+	return strings.TrimSuffix(v.Buffer(), "\n")
+}
+
+// setCommandLine replaces the text of the command line view, with the cursor at its end
+func setCommandLine(v *gocui.View, text string) {
+	v.Clear()
+	fmt.Fprint(v, text)
+
+	width, _ := v.Size()
+	origin := 0
+	if len(text) >= width {
+		origin = len(text) - width + 1
+	}
+	v.SetOrigin(origin, 0)
+	v.SetCursor(len(text)-origin, 0)
+}
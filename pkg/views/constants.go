@@ -10,11 +10,36 @@ const (
 	PopupWidth  = LabelWidth + FieldWidth
 	PopupHeight = 16
 
+	QuickAddFieldWidth = 50
+	QuickAddWidth      = LabelWidth + QuickAddFieldWidth
+
+	ConflictViewName = "conflict-popup"
+	ConflictWidth    = 72
+
+	// ConflictMaxOccurrences limits the overlapping occurrences of a series the conflict dialog lists
+	ConflictMaxOccurrences = 5
+
+	FreeSlotsViewName = "free-slots"
+	FreeSlotsWidth    = 44
+	FreeSlotsMaxRows  = 12
+
 	TimeFormat = "2006-01-02 15:04"
 
 	TimeViewWidth = 10
 
+	// MaxDayColumns is the most days a rolling layout shows side by side; DetailedColumnWidth is
+	// the column width from which its event blocks show times, location and description
+	MaxDayColumns       = 14
+	DetailedColumnWidth = 40
+
+	MinCompactWeeks = 2
+	MaxCompactWeeks = 4
+
 	TitleViewHeight = 3
 
+	// DetailPaneWidth and DetailPaneHeight size the event detail pane on the right or bottom
+	DetailPaneWidth  = 40
+	DetailPaneHeight = 10
+
 	Padding = 1
 )
@@ -3,6 +3,7 @@ package views
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/samuelstranges/chronos/internal/calendar"
@@ -18,6 +19,18 @@ type DayView struct {
 	TimeView *TimeView
 	WeatherIcon string
 	WeatherMaxTemp string
+
+	// SelectedEventId is the event IsOnEvent prefers when several overlap the cursor
+	SelectedEventId int
+
+	// Selection is the block selected in visual mode, nil outside it
+	Selection *calendar.Selection
+
+	// Grab is the event grab mode moves and resizes, nil outside it
+	Grab *calendar.Grab
+
+	// ShowDetails draws wide event blocks with times, location and description (single-day view)
+	ShowDetails bool
 }
 
 func NewDayView(name string, d *calendar.Day, tv *TimeView) *DayView {
@@ -45,9 +58,13 @@ func (dv *DayView) Update(g *gocui.Gui) error {
 	}
 
 	dv.updateBgColor(v)
+	dv.drawSelection(v)
 
 	// Create title with weather if available
 	title := dv.Day.FormatTitle()
+	if dv.ShowDetails {
+		title = dv.Day.Date.Format("Monday, January 2 2006")
+	}
 	if dv.WeatherIcon != "" {
 		if dv.WeatherMaxTemp != "" {
 			title += " " + dv.WeatherMaxTemp + "°" + dv.WeatherIcon
@@ -82,6 +99,33 @@ func (dv *DayView) updateBgColor(v *gocui.View) {
 
 
 
+// visualShade is the background of the slots selected in visual mode
+const visualShade = "\x1b[48;5;24m"
+
+// drawSelection shades the visible slots of the day that visual mode selects
+func (dv *DayView) drawSelection(v *gocui.View) {
+	v.Clear()
+	if dv.Selection == nil {
+		return
+	}
+
+	date := dv.Day.Date
+	start := dv.TimeView.GetViewportStart()
+	blank := strings.Repeat(" ", dv.W)
+	var lines []string
+	for row := 0; row < dv.TimeView.GetVisibleSlots(); row++ {
+		slot := start + row
+		t := time.Date(date.Year(), date.Month(), date.Day(), slot/2, slot%2*30, 0, 0, date.Location())
+		if dv.Selection.ContainsSlot(t) {
+			lines = append(lines, visualShade+blank+"\x1b[0m")
+		} else {
+			// A blank, not an empty line: gocui drops a leading empty line
+			lines = append(lines, " ")
+		}
+	}
+	fmt.Fprint(v, strings.Join(lines, "\n"))
+}
+
 func (dv *DayView) updateChildViewProperties(g *gocui.Gui) error {
 
 	eventViews := make(map[string]*EventView)
@@ -97,7 +141,9 @@ func (dv *DayView) updateChildViewProperties(g *gocui.Gui) error {
 	sort.Slice(events, func(i, j int) bool {
 		return events[i].Time.Before(events[j].Time)
 	})
-	
+
+	// Overlapping events are laid out side by side
+	columns := calendar.OverlapColumns(events)
 
 	for i, event := range events {
 		x := dv.X
@@ -139,9 +185,14 @@ func (dv *DayView) updateChildViewProperties(g *gocui.Gui) error {
 			}
 		}
 		
-		w := dv.W
-		
-		
+		// Split the day width between the columns of the event's overlap group
+		columnWidth := dv.W / columns[i].Columns
+		x += columns[i].Column * columnWidth
+		w := columnWidth
+		if columns[i].Column == columns[i].Columns-1 {
+			w = dv.W - columns[i].Column*columnWidth
+		}
+
 		// Ensure minimum height
 		if h <= 0 {
 			continue
@@ -167,11 +218,15 @@ func (dv *DayView) updateChildViewProperties(g *gocui.Gui) error {
 			existingView.X, existingView.Y, existingView.W, existingView.H = x, y, w, h
 			existingView.Event = event
 			existingView.ShowBottomBorder = showBottomBorder
+			existingView.ShowDetails = dv.ShowDetails
+			dv.highlight(existingView)
 			delete(eventViews, viewName)
 		} else {
 			ev := NewEvenView(viewName, event)
 			ev.X, ev.Y, ev.W, ev.H = x, y, w, h
 			ev.ShowBottomBorder = showBottomBorder
+			ev.ShowDetails = dv.ShowDetails
+			dv.highlight(ev)
 			dv.AddChild(viewName, ev)
 		}
 	}
@@ -192,9 +247,31 @@ func (dv *DayView) updateChildViewProperties(g *gocui.Gui) error {
 	return nil
 }
 
+// highlight marks an event view selected in visual mode, or held or overlapped in grab mode
+func (dv *DayView) highlight(ev *EventView) {
+	ev.Selected = dv.Selection != nil && dv.Selection.Overlaps(ev.Event)
+	ev.Grabbed = dv.Grab != nil && ev.Event.Id == dv.Grab.Event.Id
+	ev.Conflict = dv.Grab != nil && (dv.Grab.ConflictsWith(ev.Event.Id) || ev.Grabbed && len(dv.Grab.Conflicts) > 0)
+}
+
+// deleteFromGUI removes the day and its event views from the screen; they are recreated by the
+// next Update
+func (dv *DayView) deleteFromGUI(g *gocui.Gui) {
+	for pair := dv.children.Oldest(); pair != nil; pair = pair.Next() {
+		if eventView, ok := pair.Value.(*EventView); ok {
+			g.DeleteView(eventView.Name)
+			g.DeleteView(eventView.Name + "_border")
+		}
+	}
+	g.DeleteView(dv.Name)
+}
+
+// IsOnEvent returns the event view under cursor row y. When overlapping events share the row,
+// the one matching SelectedEventId wins, otherwise the leftmost.
 func (dv *DayView) IsOnEvent(y int) (*EventView, bool) {
 	// Convert cursor position to absolute screen coordinates
 	absoluteY := dv.Y + y
+	var found *EventView
 	for pair := dv.children.Newest(); pair != nil; pair = pair.Prev() {
 		if eventView, ok := pair.Value.(*EventView); ok {
 			// Subtract 1 from height to exclude the bottom padding/underline row from cursor detection
@@ -203,6 +280,34 @@ func (dv *DayView) IsOnEvent(y int) (*EventView, bool) {
 				detectableHeight = 1 // Ensure at least 1 row is detectable
 			}
 			if absoluteY >= eventView.Y && absoluteY < (eventView.Y+detectableHeight) {
+				if dv.SelectedEventId != 0 && eventView.Event.Id == dv.SelectedEventId {
+					return eventView, true
+				}
+				if found == nil || eventView.X < found.X {
+					found = eventView
+				}
+			}
+		}
+	}
+	return found, found != nil
+}
+
+// SlotAt returns the time of the slot drawn on screen row y
+func (dv *DayView) SlotAt(y int) (time.Time, bool) {
+	row := y - dv.Y - 1
+	if row < 0 || row >= dv.TimeView.GetVisibleSlots() {
+		return time.Time{}, false
+	}
+	slot := dv.TimeView.GetViewportStart() + row
+	date := dv.Day.Date
+	return time.Date(date.Year(), date.Month(), date.Day(), slot/2, slot%2*30, 0, 0, date.Location()), true
+}
+
+// EventAt returns the event view drawn at screen position x, y
+func (dv *DayView) EventAt(x, y int) (*EventView, bool) {
+	for pair := dv.children.Newest(); pair != nil; pair = pair.Prev() {
+		if eventView, ok := pair.Value.(*EventView); ok {
+			if x > eventView.X && x < eventView.X+eventView.W && y > eventView.Y && y < eventView.Y+eventView.H {
 				return eventView, true
 			}
 		}
@@ -0,0 +1,168 @@
+package views
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/utils"
+	"github.com/jroimartin/gocui"
+)
+
+// detailURLPattern finds the web links highlighted in event details
+var detailURLPattern = regexp.MustCompile(`https?://\S+`)
+
+const (
+	detailURLStyle   = "\x1b[38;5;39m\x1b[4m"
+	detailLabelWidth = 10
+)
+
+// DetailView is a pane showing every detail of the hovered event, to the right of or below the calendar
+type DetailView struct {
+	*BaseView
+
+	IsVisible bool
+	// Position is "right" or "bottom"
+	Position string
+	Event    *calendar.Event
+
+	scrollOffset int
+}
+
+func NewDetailView() *DetailView {
+	return &DetailView{
+		BaseView: NewBaseView("detail"),
+		Position: "right",
+	}
+}
+
+func (dv *DetailView) Update(g *gocui.Gui) error {
+	if !dv.IsVisible {
+		if err := g.DeleteView(dv.Name); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	v, err := g.SetView(
+		dv.Name,
+		dv.X,
+		dv.Y,
+		dv.X+dv.W,
+		dv.Y+dv.H,
+	)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = true
+	}
+
+	width, height := v.Size()
+	lines := dv.render(width)
+
+	// Keep the scroll inside the content
+	if dv.scrollOffset > len(lines)-height {
+		dv.scrollOffset = len(lines) - height
+	}
+	if dv.scrollOffset < 0 {
+		dv.scrollOffset = 0
+	}
+
+	v.Title = " Details "
+	if len(lines) > height {
+		v.Title = fmt.Sprintf(" Details %d/%d ", dv.scrollOffset+1, len(lines)-height+1)
+	}
+
+	v.Clear()
+	for _, line := range lines[dv.scrollOffset:] {
+		fmt.Fprintln(v, line)
+	}
+
+	return nil
+}
+
+// SetEvent shows an event, scrolling back to the top when it is a different one
+func (dv *DetailView) SetEvent(event *calendar.Event) {
+	if event == nil || dv.Event == nil || event.Id != dv.Event.Id {
+		dv.scrollOffset = 0
+	}
+	dv.Event = event
+}
+
+// Scroll moves the content by lines, down for positive values
+func (dv *DetailView) Scroll(lines int) {
+	dv.scrollOffset += lines
+}
+
+// render lays out the event's details wrapped to width
+func (dv *DetailView) render(width int) []string {
+	if dv.Event == nil {
+		return []string{"No event selected"}
+	}
+	event := dv.Event
+
+	lines := []string{"\x1b[1m" + calendar.WrapTextWithColor(event.Name, event.Color) + "\x1b[0m", ""}
+	// Fields wrap beside their label
+	indent := strings.Repeat(" ", detailLabelWidth)
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		for i, line := range highlightURLs(utils.WrapText(value, width-detailLabelWidth), width-detailLabelWidth) {
+			if i == 0 {
+				lines = append(lines, fmt.Sprintf("%-*s%s", detailLabelWidth, label, line))
+			} else {
+				lines = append(lines, indent+line)
+			}
+		}
+	}
+
+	durationStr := fmt.Sprintf("%.1fh", event.DurationHour)
+	if event.DurationHour == float64(int(event.DurationHour)) {
+		durationStr = fmt.Sprintf("%.0fh", event.DurationHour)
+	}
+
+	field("Date", event.Time.Format("Monday, January 2, 2006"))
+	field("Time", fmt.Sprintf("%s (%s)", event.FormatDurationTime(), durationStr))
+	field("Location", event.Location)
+	field("Colour", calendar.ColorAttributeToName(event.Color))
+	field("Repeats", event.RecurrenceSummary())
+
+	if strings.TrimSpace(event.Description) != "" {
+		lines = append(lines, "", "\x1b[1mDescription\x1b[0m")
+		lines = append(lines, highlightURLs(utils.WrapText(event.Description, width), width)...)
+	}
+
+	return lines
+}
+
+// highlightURLs styles the links in wrapped lines. A link longer than a line is split by
+// utils.WrapText into full-width lines of its own, so the start of the next line continues it.
+func highlightURLs(lines []string, width int) []string {
+	styled := make([]string, len(lines))
+	continued := false
+	for i, line := range lines {
+		rest := line
+		prefix := ""
+		if continued {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			prefix = detailURLStyle + line[:end] + "\x1b[0m"
+			rest = line[end:]
+		}
+
+		styled[i] = prefix + detailURLPattern.ReplaceAllStringFunc(rest, func(url string) string {
+			return detailURLStyle + url + "\x1b[0m"
+		})
+
+		// A full line without spaces that is, or continues, a link is followed by more of it
+		isLink := continued || detailURLPattern.MatchString(line)
+		continued = isLink && !strings.Contains(line, " ") && utf8.RuneCountInString(line) == width
+	}
+	return styled
+}
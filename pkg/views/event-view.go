@@ -2,8 +2,10 @@ package views
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/utils"
 	"github.com/jroimartin/gocui"
 )
 
@@ -12,6 +14,10 @@ type EventView struct {
 
 	Event               *calendar.Event
 	ShowBottomBorder    bool
+	ShowDetails         bool // draw times, location and description (single-day view)
+	Selected            bool // selected in visual mode, drawn in reverse video
+	Grabbed             bool // held in grab mode, drawn in reverse video
+	Conflict            bool // the grabbed event overlaps this one, drawn in red
 }
 
 func NewEvenView(name string, e *calendar.Event) *EventView {
@@ -42,13 +48,21 @@ func (ev *EventView) Update(g *gocui.Gui) error {
 		// Fallback to a visible color if somehow the event has no color
 		eventColor = gocui.ColorBlue
 	}
+	if ev.Conflict {
+		eventColor = gocui.ColorRed
+	}
 	v.BgColor = eventColor
+	if ev.Selected || ev.Grabbed {
+		v.BgColor |= gocui.AttrReverse
+	}
 	
 	
 	v.Frame = false
 	v.Clear()
 	
-	if ev.ShowBottomBorder {
+	if ev.ShowDetails {
+		ev.writeDetails(v)
+	} else if ev.ShowBottomBorder {
 		// ANSI escape codes for underlining and resetting formatting.
 		const ansiUnderline = "\x1b[4m" // Start underline
 		const ansiBlackFg = "\x1b[30m"  // Set foreground color to black
@@ -103,3 +117,42 @@ func (ev *EventView) Update(g *gocui.Gui) error {
 
 	return nil
 }
+
+// writeDetails fills the block with the event's times, location and as much of the description
+// as fits, wrapped to the block width
+func (ev *EventView) writeDetails(v *gocui.View) {
+	const ansiUnderline = "\x1b[4m"
+	const ansiBlackFg = "\x1b[30m"
+	const ansiReset = "\x1b[0m"
+
+	width := ev.W - 1
+	rows := ev.H - 1 // the last row of the block is the gap to the next slot
+	if rows < 1 {
+		rows = 1
+	}
+
+	lines := []string{ev.Event.Name + "  " + ev.Event.Time.Format("15:04") + "-" + ev.Event.EndTime().Format("15:04")}
+	if ev.Event.Location != "" {
+		lines = append(lines, "@ "+ev.Event.Location)
+	}
+	for _, line := range utils.WrapText(ev.Event.Description, width) {
+		// Blank lines between paragraphs would waste the block's few rows
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > rows {
+		lines = lines[:rows]
+	}
+
+	if ev.ShowBottomBorder {
+		// Underline the last row to separate it from the next event of the same colour
+		for len(lines) < rows {
+			lines = append(lines, "")
+		}
+		last := lines[rows-1]
+		lines[rows-1] = ansiBlackFg + ansiUnderline + last + strings.Repeat("\t", width) + ansiReset
+	}
+
+	fmt.Fprint(v, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,83 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+	"github.com/jroimartin/gocui"
+)
+
+// editorCommand returns the user's editor command from $EDITOR or $VISUAL, falling back to vi
+func editorCommand() []string {
+	for _, name := range []string{"EDITOR", "VISUAL"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editInExternalEditor suspends the TUI, opens text in the user's editor and returns the saved
+// text. pattern names the temporary file, so an extension in it picks the editor's filetype.
+// Keybinding handlers run on the main loop, so nothing is drawn while the editor has the terminal.
+func editInExternalEditor(g *gocui.Gui, text, pattern string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	_, err = file.WriteString(text)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	command := editorCommand()
+	cmd := exec.Command(command[0], append(command[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	termbox.Close()
+	runErr := cmd.Run()
+	if err := resumeTerminal(g); err != nil {
+		return "", err
+	}
+	if runErr != nil {
+		return "", fmt.Errorf("%s: %v", command[0], runErr)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
+
+// resumeTerminal takes the terminal back after an external program. termbox keeps its event
+// channels across Close and Init, so gocui's event loop carries on, but the modes gocui set when
+// it started have to be set again.
+func resumeTerminal(g *gocui.Gui) error {
+	if err := termbox.Init(); err != nil {
+		return err
+	}
+	termbox.SetOutputMode(termbox.Output256)
+
+	inputMode := termbox.InputAlt
+	if g.InputEsc {
+		inputMode = termbox.InputEsc
+	}
+	if g.Mouse {
+		inputMode |= termbox.InputMouse
+	}
+	termbox.SetInputMode(inputMode)
+
+	return nil
+}
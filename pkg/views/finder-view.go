@@ -0,0 +1,174 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/samuelstranges/chronos/internal/finder"
+	"github.com/jroimartin/gocui"
+)
+
+// FinderWidth is the width of the event finder
+const FinderWidth = 76
+
+// FinderMaxHeight caps the height of the event finder, prompt and list together
+const FinderMaxHeight = 24
+
+// finderListName is the view listing the events found, below the finder's prompt
+const finderListName = "finder-list"
+
+// FinderView finds events by fuzzy matching their names and locations as the query is typed,
+// listing each recurring series once. The arrow keys, Ctrl-n and Ctrl-p move the selection.
+type FinderView struct {
+	*BaseView
+	IsVisible bool
+	// Matches are the entries matching the query, best first
+	Matches  []finder.Match
+	Selected int
+
+	entries []*finder.Entry
+	query   string
+	offset  int // first match shown
+}
+
+func NewFinderView() *FinderView {
+	return &FinderView{
+		BaseView: NewBaseView("finder"),
+	}
+}
+
+// Open shows an empty query over entries
+func (fv *FinderView) Open(entries []*finder.Entry) {
+	fv.IsVisible = true
+	fv.entries = entries
+	fv.search("")
+}
+
+// Close hides the finder and lets go of its entries
+func (fv *FinderView) Close() {
+	fv.IsVisible = false
+	fv.entries = nil
+	fv.Matches = nil
+}
+
+// SelectedEntry returns the selected match, or nil when nothing matches
+func (fv *FinderView) SelectedEntry() *finder.Entry {
+	if fv.Selected < 0 || fv.Selected >= len(fv.Matches) {
+		return nil
+	}
+	return fv.Matches[fv.Selected].Entry
+}
+
+// MoveSelection moves the selection down the list, up for negative steps
+func (fv *FinderView) MoveSelection(step int) {
+	fv.Selected = max(0, min(fv.Selected+step, len(fv.Matches)-1))
+}
+
+// search ranks the entries for query and selects the best match
+func (fv *FinderView) search(query string) {
+	fv.query = query
+	fv.Matches = finder.Rank(fv.entries, query, 0)
+	fv.Selected = 0
+	fv.offset = 0
+}
+
+// listRows returns the number of matches the list shows at once
+func (fv *FinderView) listRows() int {
+	// The prompt takes three rows and the list's borders two
+	return max(fv.H-4, 1)
+}
+
+func (fv *FinderView) Update(g *gocui.Gui) error {
+	if !fv.IsVisible {
+		for _, name := range []string{finderListName, fv.Name} {
+			if err := g.DeleteView(name); err != nil && err != gocui.ErrUnknownView {
+				return err
+			}
+		}
+		return nil
+	}
+
+	prompt, err := g.SetView(fv.Name, fv.X, fv.Y, fv.X+fv.W, fv.Y+2)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		prompt.Title = " Find events (Enter jumps, Esc closes) "
+		prompt.Editable = true
+		prompt.Editor = fv
+	}
+
+	list, err := g.SetView(finderListName, fv.X, fv.Y+3, fv.X+fv.W, fv.Y+fv.H)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		list.Highlight = true
+		list.SelBgColor = gocui.ColorGreen
+		list.SelFgColor = gocui.ColorBlack
+	}
+	list.Title = fmt.Sprintf(" %d/%d ", len(fv.Matches), len(fv.entries))
+
+	// Scroll just far enough to show the selection
+	rows := fv.listRows()
+	if fv.Selected < fv.offset {
+		fv.offset = fv.Selected
+	} else if fv.Selected >= fv.offset+rows {
+		fv.offset = fv.Selected - rows + 1
+	}
+
+	list.Clear()
+	if len(fv.Matches) == 0 {
+		fmt.Fprintln(list, " No events match")
+	}
+	for _, match := range fv.Matches[fv.offset:min(fv.offset+rows, len(fv.Matches))] {
+		fmt.Fprintln(list, formatFinderEntry(match.Entry))
+	}
+	list.SetOrigin(0, 0)
+	list.SetCursor(0, fv.Selected-fv.offset)
+
+	g.SetViewOnTop(finderListName)
+	g.SetViewOnTop(fv.Name)
+	return nil
+}
+
+// formatFinderEntry formats an entry as a line of the list: the time it jumps to, the name and
+// location, and how many times a series repeats
+func formatFinderEntry(entry *finder.Entry) string {
+	event := entry.Event
+	line := fmt.Sprintf(" %s  %s", event.Time.Format("Mon Jan 2 2006 15:04"), event.Name)
+	if event.Location != "" {
+		line += " @ " + event.Location
+	}
+	if entry.Count > 1 {
+		line += fmt.Sprintf("  (%d times)", entry.Count)
+	}
+	return line
+}
+
+// Edit edits the query, ranking the events again whenever it changes. The arrow keys, Ctrl-n and
+// Ctrl-p move the selection and Ctrl-u clears the query.
+func (fv *FinderView) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	if !fv.IsVisible {
+		return
+	}
+
+	switch key {
+	case gocui.KeyArrowDown, gocui.KeyCtrlN:
+		fv.MoveSelection(1)
+		return
+	case gocui.KeyArrowUp, gocui.KeyCtrlP:
+		fv.MoveSelection(-1)
+		return
+	case gocui.KeyCtrlU:
+		setCommandLine(v, "")
+	case gocui.KeyEnter:
+		// Enter jumps to the selection through its keybinding
+		return
+	default:
+		gocui.DefaultEditor.Edit(v, key, ch, mod)
+	}
+
+	if query := CommandLineText(v); query != fv.query {
+		fv.search(query)
+	}
+}
@@ -2,12 +2,19 @@ package views
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/samuelstranges/chronos/internal/utils"
 	"github.com/jroimartin/gocui"
 )
 
 type KeybindsView struct {
 	*BaseView
 	IsVisible bool
+	// Keymap is the keymap the help is generated from; nil shows the default keys
+	Keymap *keymap.Keymap
 }
 
 func NewKeybindsView() *KeybindsView {
@@ -17,44 +24,42 @@ func NewKeybindsView() *KeybindsView {
 	}
 }
 
-// getKeybindingsContent returns the keybindings content as a slice of strings
+// helpKeyWidth is the width of the keys column of the help
+const helpKeyWidth = 11
+
+// getKeybindingsContent lists the bound keys of every action by category, from the live keymap
 func (kbv *KeybindsView) getKeybindingsContent() []string {
-	return []string{
-		" Views:",
-		" q           - Exit chronos",
-		" ?           - Show/hide help",
-		" v           - Toggle view (Week→Month→Agenda)",
-		"",
-		" Navigation:",
-		" h/l or ←/→  - Previous/Next day",
-		" H/L         - Previous/Next week",
-		" m/M         - Previous/Next month",
-		" j/k or ↓/↑  - Move time cursor down/up",
-		" t           - goto today",
-		" D           - jump to specific date",
-		" T           - jump to time in day",
-		" w/b/e       - Jump to next/prev/end event",
-		" g/G         - Start/End of day (00:00/23:30)",
-		"",
-		" Event Management:",
-		" a           - Add new event",
-		" c           - Change event",
-		" C           - Color picker",
-		" d           - Change duration",
-		" y           - Copy event",
-		" p           - Paste event",
-		" x           - Delete event",
-		" B           - Bulk delete all events w/ same name",
-		"",
-		" Advanced Search:",
-		" /           - Search events (name/desc/loc)",
-		" n/N         - Next/previous search match",
-		" Esc         - Clear search",
-		"",
-		" Undo Buffer:",
-		" u           - Undo last action",
-		" r           - Redo last undone action",
+	km := kbv.Keymap
+	if km == nil {
+		km = keymap.Default()
+	}
+
+	// Descriptions wrap beside the keys column, inside the frame
+	indent := strings.Repeat(" ", helpKeyWidth+4)
+	descriptionWidth := KeybindsWidth - 1 - len(indent)
+
+	var lines []string
+	for i, section := range km.Help() {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, " "+section.Category+":")
+		for _, entry := range section.Entries {
+			description := utils.WrapText(entry.Description, descriptionWidth)
+			if utf8.RuneCountInString(entry.Keys) > helpKeyWidth {
+				// Long key lists get a line of their own
+				lines = append(lines, " "+entry.Keys)
+				description[0] = indent + description[0]
+			} else {
+				description[0] = fmt.Sprintf(" %s%s - %s", entry.Keys, strings.Repeat(" ", helpKeyWidth-utf8.RuneCountInString(entry.Keys)), description[0])
+			}
+			for j := 1; j < len(description); j++ {
+				description[j] = indent + description[j]
+			}
+			lines = append(lines, description...)
+		}
 	}
+	return lines
 }
 
 // GetRequiredHeight returns the number of lines needed for all keybinding content
@@ -64,8 +64,8 @@ func (mv *MainView) updateChildViewProperties() {
 		)
 	}
 
-	// Auto-adjust viewport for responsive behavior in week view
-	if mv.CalendarView != nil && mv.CalendarView.ViewMode == "week" && mv.CalendarView.TimeView != nil {
+	// Auto-adjust viewport for responsive behavior in the week and day views
+	if mv.CalendarView != nil && mv.CalendarView.isTimeline() && mv.CalendarView.TimeView != nil {
 		// First, auto-adjust the viewport based on calendar cursor time and available space
 		mv.CalendarView.TimeView.AutoAdjustViewport(mv.Calendar.CurrentDay.Date)
 		
@@ -93,6 +93,27 @@ func (mv *MainView) SwitchToWeekView(g *gocui.Gui) error {
 	return nil
 }
 
+func (mv *MainView) SwitchToDayView(g *gocui.Gui) error {
+	if mv.CalendarView != nil {
+		return mv.CalendarView.SwitchToDayView(g)
+	}
+	return nil
+}
+
+func (mv *MainView) SwitchToDaysView(g *gocui.Gui) error {
+	if mv.CalendarView != nil {
+		return mv.CalendarView.SwitchToDaysView(g)
+	}
+	return nil
+}
+
+func (mv *MainView) SwitchToWeeksView(g *gocui.Gui) error {
+	if mv.CalendarView != nil {
+		return mv.CalendarView.SwitchToWeeksView(g)
+	}
+	return nil
+}
+
 func (mv *MainView) SwitchToMonthView(g *gocui.Gui) error {
 	if mv.CalendarView != nil {
 		return mv.CalendarView.SwitchToMonthView(g)
@@ -100,6 +121,13 @@ func (mv *MainView) SwitchToMonthView(g *gocui.Gui) error {
 	return nil
 }
 
+func (mv *MainView) SwitchToYearView(g *gocui.Gui) error {
+	if mv.CalendarView != nil {
+		return mv.CalendarView.SwitchToYearView(g)
+	}
+	return nil
+}
+
 func (mv *MainView) SwitchToAgendaView(g *gocui.Gui) error {
 	if mv.CalendarView != nil {
 		return mv.CalendarView.SwitchToAgendaView(g)
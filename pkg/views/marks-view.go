@@ -0,0 +1,65 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/jroimartin/gocui"
+)
+
+// MarksWidth is the width of the marks list
+const MarksWidth = 40
+
+// MarksView lists the marks with their dates. Typing a mark's letter jumps to it.
+type MarksView struct {
+	*BaseView
+	IsVisible bool
+	Marks     []database.Mark
+}
+
+func NewMarksView() *MarksView {
+	return &MarksView{
+		BaseView: NewBaseView("marks"),
+	}
+}
+
+// GetRequiredHeight returns the number of lines needed to list the marks
+func (mv *MarksView) GetRequiredHeight() int {
+	// The view spans H+1 rows, two of them borders
+	return len(mv.getMarksContent()) + 1
+}
+
+func (mv *MarksView) getMarksContent() []string {
+	if len(mv.Marks) == 0 {
+		return []string{" No marks set"}
+	}
+	lines := make([]string, len(mv.Marks))
+	for i, mark := range mv.Marks {
+		lines[i] = fmt.Sprintf(" %s  %s", mark.Name, mark.Time.In(time.Local).Format("Mon Jan 2 2006 15:04"))
+	}
+	return lines
+}
+
+func (mv *MarksView) Update(g *gocui.Gui) error {
+	if !mv.IsVisible {
+		if err := g.DeleteView(mv.Name); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	v, err := g.SetView(mv.Name, mv.X, mv.Y, mv.X+mv.W, mv.Y+mv.H)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = " Marks (a-z to jump) "
+	}
+	v.Clear()
+	for _, line := range mv.getMarksContent() {
+		fmt.Fprintln(v, line)
+	}
+	g.SetViewOnTop(mv.Name)
+	return nil
+}
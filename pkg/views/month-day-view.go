@@ -81,30 +81,14 @@ func (mdv *MonthDayView) Update(g *gocui.Gui) error {
 	fmt.Fprintf(v, "%s\n", dayStr)
 	
 	// Draw events (as bullet points)
-	maxEvents := mdv.H - 2 // Leave space for day number AND potential grid line/border
-	if maxEvents < 0 {
-		maxEvents = 0
-	}
-	
-	
-	var eventsToShow []*calendar.Event
-	var hasOverflow bool
-	
-	if len(mdv.Events) > maxEvents && maxEvents > 0 {
-		// More events than can fit - reserve last line for overflow message
-		eventsToShow = mdv.Events[:maxEvents-1]
-		hasOverflow = true
-		
+	eventsToShow, hasOverflow := mdv.VisibleEvents()
+	if hasOverflow {
 		// Debug overflow logic
 		if f, err := os.OpenFile("/tmp/chronos_overflow_debug.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
 			fmt.Fprintf(f, "OVERFLOW: showing %d events, hiding %d events\n", len(eventsToShow), len(mdv.Events)-len(eventsToShow))
 			f.Close()
 		}
 	} else {
-		// All events fit, or no space at all
-		eventsToShow = mdv.Events
-		hasOverflow = false
-		
 		// Debug no overflow
 		if len(mdv.Events) > 0 {
 			if f, err := os.OpenFile("/tmp/chronos_overflow_debug.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
@@ -170,6 +154,27 @@ func (mdv *MonthDayView) truncateEventName(name string) string {
 	return name[:maxWidth]
 }
 
+// VisibleEvents returns the events listed in the cell, one a row below the day number, and
+// whether the last row tells how many more there are instead
+func (mdv *MonthDayView) VisibleEvents() ([]*calendar.Event, bool) {
+	maxEvents := mdv.H - 2 // Leave space for day number AND potential grid line/border
+	if len(mdv.Events) > maxEvents && maxEvents > 0 {
+		// More events than can fit - reserve last line for overflow message
+		return mdv.Events[:maxEvents-1], true
+	}
+	// All events fit, or no space at all
+	return mdv.Events, false
+}
+
+// EventAtRow returns the event listed on a row of the cell, below the day number on row 0
+func (mdv *MonthDayView) EventAtRow(row int) (*calendar.Event, bool) {
+	shown, _ := mdv.VisibleEvents()
+	if row < 1 || row > len(shown) {
+		return nil, false
+	}
+	return shown[row-1], true
+}
+
 func (mdv *MonthDayView) LoadEvents(events []*calendar.Event) {
 	mdv.Events = make([]*calendar.Event, 0)
 	
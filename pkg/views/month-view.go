@@ -25,6 +25,9 @@ var MonthDayNames = []string{
 
 const dayHeaderHeight = 2
 
+// weekGutterWidth is the column left of the grid showing ISO week numbers
+const weekGutterWidth = 4
+
 type MonthView struct {
 	*BaseView
 	
@@ -34,6 +37,11 @@ type MonthView struct {
 	// Current month being displayed
 	CurrentMonth time.Time
 	
+	// Weeks is the number of rows of the compact weeks grid, which starts on weeksStart and
+	// scrolls with the selected day; 0 shows CurrentMonth
+	Weeks      int
+	weeksStart time.Time
+	
 	// Grid dimensions
 	CellWidth  int
 	CellHeight int
@@ -64,26 +72,72 @@ func NewMonthView(c *calendar.Calendar, em *eventmanager.EventManager) *MonthVie
 }
 
 func (mv *MonthView) createMonthDayViews() {
-	// Get the first day of the month
-	firstDay := time.Date(mv.CurrentMonth.Year(), mv.CurrentMonth.Month(), 1, 0, 0, 0, 0, mv.CurrentMonth.Location())
-	
-	// Find the Sunday of the week containing the first day
-	startOfWeek := firstDay
-	for startOfWeek.Weekday() != time.Sunday {
-		startOfWeek = startOfWeek.AddDate(0, 0, -1)
-	}
+	gridStart := mv.GridStart()
 	
-	// Create 42 day views (6 rows × 7 columns)
-	for i := 0; i < 42; i++ {
-		dayDate := startOfWeek.AddDate(0, 0, i)
+	// Create one day view per cell (6 rows × 7 columns for a month)
+	for i := 0; i < mv.cellCount(); i++ {
+		dayDate := gridStart.AddDate(0, 0, i)
 		dayName := fmt.Sprintf("monthday_%d", i)
 		
 		// Create day view
 		dayView := NewMonthDayView(dayName, dayDate, mv.CurrentMonth)
+		if mv.Weeks > 0 {
+			// No day of the compact grid is outside the displayed range
+			dayView.IsCurrentMonth = true
+		}
 		mv.AddChild(dayName, dayView)
 	}
 }
 
+// GridStart returns the date of the top-left cell
+func (mv *MonthView) GridStart() time.Time {
+	if mv.Weeks > 0 {
+		return mv.weeksStart
+	}
+	
+	// Get the first day of the month
+	firstDay := time.Date(mv.CurrentMonth.Year(), mv.CurrentMonth.Month(), 1, 0, 0, 0, 0, mv.CurrentMonth.Location())
+	
+	// Find the start of the week containing the first day
+	return calendar.StartOfWeek(firstDay, mv.Calendar.WeekStart)
+}
+
+// cellCount returns the number of day cells in the grid
+func (mv *MonthView) cellCount() int {
+	return mv.GridRows * mv.GridCols
+}
+
+// SetWeeks switches between the month grid (0) and a compact grid of weeks rows that starts with
+// the selected day's week
+func (mv *MonthView) SetWeeks(weeks int) {
+	mv.Weeks = weeks
+	mv.GridRows = 6
+	if weeks > 0 {
+		mv.GridRows = weeks
+		mv.weeksStart = calendar.StartOfWeek(mv.Calendar.CurrentDay.Date, mv.Calendar.WeekStart)
+	}
+	mv.CurrentMonth = mv.Calendar.CurrentDay.Date
+	mv.refreshMonthDayViews()
+}
+
+// syncWeeks scrolls the compact grid by whole weeks until it shows date
+func (mv *MonthView) syncWeeks(date time.Time) {
+	if mv.Weeks == 0 {
+		return
+	}
+	week := calendar.StartOfWeek(date, mv.Calendar.WeekStart)
+	start := mv.weeksStart
+	if week.Before(start) {
+		start = week
+	} else if last := start.AddDate(0, 0, 7*(mv.Weeks-1)); week.After(last) {
+		start = week.AddDate(0, 0, -7*(mv.Weeks-1))
+	}
+	if !start.Equal(mv.weeksStart) {
+		mv.weeksStart = start
+		mv.refreshMonthDayViews()
+	}
+}
+
 func (mv *MonthView) Update(g *gocui.Gui) error {
 	// Debug logging
 	if f, err := os.OpenFile("/tmp/chronos_month_debug.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
@@ -114,6 +168,9 @@ func (mv *MonthView) Update(g *gocui.Gui) error {
 		v.Frame = false
 	}
 	
+	// Keep the selected day in the compact weeks grid
+	mv.syncWeeks(mv.Calendar.CurrentDay.Date)
+	
 	// Load events for current month
 	if err := mv.loadEventsForMonth(); err != nil {
 		return err
@@ -121,7 +178,7 @@ func (mv *MonthView) Update(g *gocui.Gui) error {
 	
 	// Calculate cell dimensions based on available space
 	dayHeaderHeight := 2 // Space for day names and separator line
-	mv.CellWidth = mv.W / mv.GridCols
+	mv.CellWidth = (mv.W - weekGutterWidth) / mv.GridCols
 	if mv.CellWidth < 1 {
 		mv.CellWidth = 1
 	}
@@ -159,14 +216,14 @@ func (mv *MonthView) updateChildViewProperties() {
 		f.Close()
 	}
 	
-	for i := 0; i < 42; i++ {
+	for i := 0; i < mv.cellCount(); i++ {
 		dayName := fmt.Sprintf("monthday_%d", i)
 		if dayView, ok := mv.GetChild(dayName); ok {
 			row := i / mv.GridCols
 			col := i % mv.GridCols
 			
 			// Calculate position for compact grid layout (no individual borders)
-			x := mv.X + col*mv.CellWidth
+			x := mv.X + weekGutterWidth + col*mv.CellWidth
 			y := mv.Y + dayHeaderHeight + row*mv.CellHeight
 			w := mv.CellWidth
 			h := mv.CellHeight
@@ -211,6 +268,15 @@ func (mv *MonthView) UpdateToPrevMonth() {
 	mv.refreshMonthDayViews()
 }
 
+// SetCurrentMonth shows the month containing date
+func (mv *MonthView) SetCurrentMonth(date time.Time) {
+	if mv.CurrentMonth.Year() == date.Year() && mv.CurrentMonth.Month() == date.Month() {
+		return
+	}
+	mv.CurrentMonth = date
+	mv.refreshMonthDayViews()
+}
+
 func (mv *MonthView) refreshMonthDayViews() {
 	// Debug logging
 	if f, err := os.OpenFile("/tmp/chronos_month_debug.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
@@ -235,11 +301,18 @@ func (mv *MonthView) refreshMonthDayViews() {
 }
 
 func (mv *MonthView) loadEventsForMonth() error {
-	// Load events for the current month
-	events, err := mv.EventManager.GetEventsByMonth(mv.CurrentMonth.Year(), mv.CurrentMonth.Month())
+	// Load events for the current month, or for every day of the compact weeks grid
+	var events []*calendar.Event
+	var err error
+	if mv.Weeks > 0 {
+		events, err = mv.EventManager.GetEventsByDateRange(mv.weeksStart, mv.weeksStart.AddDate(0, 0, mv.cellCount()))
+	} else {
+		events, err = mv.EventManager.GetEventsByMonth(mv.CurrentMonth.Year(), mv.CurrentMonth.Month())
+	}
 	if err != nil {
 		return err
 	}
+	events = mv.Calendar.Filtered(events)
 	
 	// Convert UTC events to local time for display
 	localEvents := make([]*calendar.Event, len(events))
@@ -250,7 +323,7 @@ func (mv *MonthView) loadEventsForMonth() error {
 	}
 	
 	// Distribute events to appropriate day views
-	for i := 0; i < 42; i++ {
+	for i := 0; i < mv.cellCount(); i++ {
 		dayName := fmt.Sprintf("monthday_%d", i)
 		if dayView, ok := mv.GetChild(dayName); ok {
 			if monthDayView, ok := dayView.(*MonthDayView); ok {
@@ -263,11 +336,13 @@ func (mv *MonthView) loadEventsForMonth() error {
 }
 
 func (mv *MonthView) drawCompactGrid(v *gocui.View) {
-	// Draw day headers with pipe separators
-	dayHeaders := ""
-	for i, dayName := range MonthDayNames {
+	blankGutter := strings.Repeat(" ", weekGutterWidth)
+	
+	// Draw day headers with pipe separators, starting with the configured first day of the week
+	dayHeaders := fmt.Sprintf("%-*s", weekGutterWidth, "Wk")
+	for i := range MonthDayNames {
 		// Use 3-character abbreviation for day names
-		dayAbbr := dayName[:3]
+		dayAbbr := MonthDayNames[(int(mv.Calendar.WeekStart)+i)%7][:3]
 		paddedName := fmt.Sprintf("%-*s", mv.CellWidth-1, dayAbbr)
 		dayHeaders += paddedName
 		if i < len(MonthDayNames)-1 {
@@ -277,11 +352,12 @@ func (mv *MonthView) drawCompactGrid(v *gocui.View) {
 	fmt.Fprintf(v, "%s\n", dayHeaders)
 	
 	// Draw grid lines for each row
+	gridStart := mv.GridStart()
 	for row := 0; row < mv.GridRows; row++ {
 		// Draw horizontal separator line
 		if row == 0 {
 			// Top border
-			separator := ""
+			separator := blankGutter
 			for col := 0; col < mv.GridCols; col++ {
 				separator += strings.Repeat("─", mv.CellWidth-1)
 				if col < mv.GridCols-1 {
@@ -291,9 +367,13 @@ func (mv *MonthView) drawCompactGrid(v *gocui.View) {
 			fmt.Fprintf(v, "%s\n", separator)
 		}
 		
-		// Draw the row content (will be filled by MonthDayView children)
+		// Draw the row content (will be filled by MonthDayView children), with the ISO week
+		// number in the gutter
 		for line := 0; line < mv.CellHeight-1; line++ {
-			rowLine := ""
+			rowLine := blankGutter
+			if line == 0 {
+				rowLine = fmt.Sprintf("%2d%s", calendar.WeekNumber(gridStart.AddDate(0, 0, 7*row)), blankGutter[2:])
+			}
 			for col := 0; col < mv.GridCols; col++ {
 				// Leave space for content (filled by child views)
 				rowLine += strings.Repeat(" ", mv.CellWidth-1)
@@ -306,7 +386,7 @@ func (mv *MonthView) drawCompactGrid(v *gocui.View) {
 		
 		// Draw horizontal separator (except for last row)
 		if row < mv.GridRows-1 {
-			separator := ""
+			separator := blankGutter
 			for col := 0; col < mv.GridCols; col++ {
 				separator += strings.Repeat("─", mv.CellWidth-1)
 				if col < mv.GridCols-1 {
@@ -366,7 +446,7 @@ func (mv *MonthView) UpdateWeatherData(cfg *config.Config, weatherCache *weather
 	}
 	
 	// Update weather data for all day views
-	for i := 0; i < 42; i++ {
+	for i := 0; i < mv.cellCount(); i++ {
 		dayName := fmt.Sprintf("monthday_%d", i)
 		if dayViewInterface, ok := mv.GetChild(dayName); ok {
 			if dayView, ok := dayViewInterface.(*MonthDayView); ok {
@@ -2,7 +2,11 @@ package views
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/freetime"
 	"github.com/samuelstranges/chronos/internal/utils"
 	component "github.com/j-04/gocui-component"
 	"github.com/jroimartin/gocui"
@@ -20,7 +24,8 @@ func (epv *EventPopupView) NewEventForm(g *gocui.Gui, title, name, date, time, l
 	form.AddInputField("Frequency", LabelWidth, FieldWidth).SetText(frequency).AddValidate("Invalid frequency (number or 'w' for weekdays)", utils.ValidateFrequency)
 	form.AddInputField("Occurence", LabelWidth, FieldWidth).SetText(occurence).AddValidate("Invalid occurence", utils.ValidateNumber)
 	form.AddInputField("Color", LabelWidth, FieldWidth).SetText(color)
-	form.AddInputField("Description", LabelWidth, FieldWidth).SetText(description)
+	epv.description = description
+	form.AddInputField("Description", LabelWidth, FieldWidth).SetText(descriptionPreview(description))
 
 	return form
 }
@@ -35,7 +40,8 @@ func (epv *EventPopupView) EditEventForm(g *gocui.Gui, title, name, date, time,
 	form.AddInputField("Location", LabelWidth, FieldWidth).SetText(location)
 	form.AddInputField("Duration", LabelWidth, FieldWidth).SetText(duration).AddValidate("Invalid duration", utils.ValidateDuration)
 	form.AddInputField("Color", LabelWidth, FieldWidth).SetText(color)
-	form.AddInputField("Description", LabelWidth, FieldWidth).SetText(description)
+	epv.description = description
+	form.AddInputField("Description", LabelWidth, FieldWidth).SetText(descriptionPreview(description))
 
 	return form
 }
@@ -50,6 +56,15 @@ func (epv *EventPopupView) GotoForm(g *gocui.Gui, title string) *component.Form
 	return form
 }
 
+// PasteTimeForm creates a form for the time events are pasted at, starting from defaultTime
+func (epv *EventPopupView) PasteTimeForm(g *gocui.Gui, title, defaultTime string) *component.Form {
+	form := component.NewForm(g, title, epv.X, epv.Y, epv.W, epv.H)
+
+	form.AddInputField("Time", LabelWidth, FieldWidth).SetText(defaultTime).AddValidate("Invalid time (HH:MM on the hour or half hour)", utils.ValidateEventTime)
+
+	return form
+}
+
 // DateForm creates a form for navigating to a specific date
 func (epv *EventPopupView) DateForm(g *gocui.Gui, title string) *component.Form {
 	form := component.NewForm(g, title, epv.X, epv.Y, epv.W, epv.H)
@@ -86,12 +101,47 @@ func (epv *EventPopupView) SearchForm(g *gocui.Gui, title string) *component.For
 	form := component.NewForm(g, title, epv.X, epv.Y, epv.W, epv.H)
 	
 	form.AddInputField("Query", LabelWidth, FieldWidth).SetText("")
-	form.AddInputField("From Date", LabelWidth, FieldWidth).SetText("").AddValidate("Invalid date (YYYYMMDD, 't' for today, or empty)", utils.ValidateOptionalDate)
-	form.AddInputField("To Date", LabelWidth, FieldWidth).SetText("").AddValidate("Invalid date (YYYYMMDD, 't' for today, or empty)", utils.ValidateOptionalDate)
+	form.AddInputField("From Date", LabelWidth, FieldWidth).SetText("").AddValidate("Invalid date (YYYYMMDD, 't' today, 'w' this week, or empty)", utils.ValidateOptionalSearchDate)
+	form.AddInputField("To Date", LabelWidth, FieldWidth).SetText("").AddValidate("Invalid date (YYYYMMDD, 't' today, 'w' this week, or empty)", utils.ValidateOptionalSearchDate)
+	form.AddInputField("Save As", LabelWidth, FieldWidth).SetText("").AddValidate("Filter names are one word, or empty to search", func(value string) bool {
+		return len(strings.Fields(value)) <= 1
+	})
 	
 	return form
 }
 
+// FreeSlotForm creates a form for finding free slots, prefilled with the configured working hours
+func (epv *EventPopupView) FreeSlotForm(g *gocui.Gui, title string) *component.Form {
+	form := component.NewForm(g, title, epv.X, epv.Y, epv.W, epv.H)
+
+	duration := strconv.FormatFloat(config.GetDefaultEventLength(epv.Config), 'f', -1, 64)
+	form.AddInputField("Duration", LabelWidth, FieldWidth).SetText(duration).AddValidate("Invalid duration (eg. 1.5, 90m)", func(value string) bool {
+		_, err := freetime.ParseDuration(value)
+		return err == nil
+	})
+	form.AddInputField("Hours", LabelWidth, FieldWidth).SetText(config.GetWorkingHours(epv.Config)).AddValidate("Invalid hours (HH:MM-HH:MM)", func(value string) bool {
+		_, _, err := freetime.ParseHours(value)
+		return err == nil
+	})
+	form.AddInputField("Days", LabelWidth, FieldWidth).SetText(config.GetWorkingDays(epv.Config)).AddValidate("Invalid days (eg. mon-fri, all)", func(value string) bool {
+		_, err := freetime.ParseWeekdays(value)
+		return err == nil
+	})
+	form.AddInputField("From Date", LabelWidth, FieldWidth).SetText("t").AddValidate("Invalid date (YYYYMMDD, 't' for today, or empty)", utils.ValidateOptionalDate)
+	form.AddInputField("Within Days", LabelWidth, FieldWidth).SetText("7").AddValidate("Invalid number of days", utils.ValidateNumber)
+
+	return form
+}
+
+// QuickAddForm creates a form for adding an event from a natural-language phrase
+func (epv *EventPopupView) QuickAddForm(g *gocui.Gui, title string) *component.Form {
+	form := component.NewForm(g, title, epv.X, epv.Y, epv.W, epv.H)
+
+	form.AddInputField("Event", LabelWidth, QuickAddFieldWidth).SetText("")
+
+	return form
+}
+
 // positionCursorsAtEnd positions cursors at the end of field text
 func (epv *EventPopupView) positionCursorsAtEnd(g *gocui.Gui) {
 	for _, input := range epv.Form.GetInputs() {
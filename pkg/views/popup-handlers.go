@@ -1,12 +1,17 @@
 package views
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
 	"github.com/samuelstranges/chronos/internal/database"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/freetime"
+	"github.com/samuelstranges/chronos/internal/quickadd"
 	"github.com/jroimartin/gocui"
 )
 
@@ -57,7 +62,7 @@ func (epv *EventPopupView) CreateEventFromInputs(existingEvent *calendar.Event)
 	
 	occurence, _ := strconv.Atoi(epv.Form.GetFieldText("Occurence"))
 	colorName := epv.Form.GetFieldText("Color")
-	description := epv.Form.GetFieldText("Description")
+	description := epv.formDescription()
 
 	color := calendar.ColorNameToAttribute(colorName)
 	if color == gocui.ColorDefault {
@@ -79,11 +84,15 @@ func (epv *EventPopupView) AddEvent(g *gocui.Gui, v *gocui.View) error {
 	}
 	events := newEvent.GetReccuringEvents()
 
-	for _, event := range events {
-		if _, success := epv.EventManager.AddEvent(event); !success {
-			// Error is handled by EventManager internally
-			return nil
-		}
+	// Events that overlap get the conflict dialog instead of an error
+	if shown, err := epv.ShowAddConflicts(g, events); shown || err != nil {
+		return err
+	}
+
+	// A series is added as one change, or not at all when an occurrence is refused
+	if _, success := epv.EventManager.AddEvents(events, fmt.Sprintf("add %d %s", len(events), pluralEvents(len(events)))); !success {
+		// Error is handled by EventManager internally
+		return nil
 	}
 
 	return epv.Close(g, v)
@@ -101,6 +110,10 @@ func (epv *EventPopupView) EditEvent(g *gocui.Gui, v *gocui.View, event *calenda
 	}
 	newEvent.Id = event.Id
 
+	if shown, err := epv.showConflictsIfAny(g, newEvent, event.Id); shown || err != nil {
+		return err
+	}
+
 	if !epv.EventManager.UpdateEvent(event.Id, newEvent) {
 		// Error is handled by EventManager internally
 		return nil
@@ -109,6 +122,50 @@ func (epv *EventPopupView) EditEvent(g *gocui.Gui, v *gocui.View, event *calenda
 	return epv.Close(g, v)
 }
 
+// EditDescriptionInEditor handler for writing the event form's description in $EDITOR
+func (epv *EventPopupView) EditDescriptionInEditor(g *gocui.Gui, v *gocui.View) error {
+	if !epv.IsVisible {
+		return nil
+	}
+
+	text, err := editInExternalEditor(g, epv.formDescription(), "chronos-description-*.txt")
+	if err != nil {
+		return epv.ShowErrorMessage(g, "Editor Error", err.Error())
+	}
+	epv.description = strings.TrimRight(text, " \t\n")
+	preview := descriptionPreview(epv.description)
+
+	for _, input := range epv.Form.GetInputs() {
+		if input.GetLabel() == "Description" {
+			input.SetText(preview)
+		}
+	}
+	if field, err := g.View("Description"); err == nil {
+		field.Clear()
+		field.SetOrigin(0, 0)
+		field.SetCursor(0, 0)
+		fmt.Fprint(field, preview)
+		field.MoveCursor(len(preview), 0, true)
+	}
+
+	return nil
+}
+
+// formDescription returns the event form's description, keeping the line breaks that the
+// Description field cannot show unless the field has been typed in since
+func (epv *EventPopupView) formDescription() string {
+	text := epv.Form.GetFieldText("Description")
+	if text == descriptionPreview(epv.description) {
+		return epv.description
+	}
+	return text
+}
+
+// descriptionPreview fits a description on the single line of a form field
+func descriptionPreview(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
+
 // Goto handler for navigating to specific time on the same day
 func (epv *EventPopupView) Goto(g *gocui.Gui, v *gocui.View) error {
 	if !epv.IsVisible {
@@ -169,6 +226,9 @@ func (epv *EventPopupView) GotoDate(g *gocui.Gui, v *gocui.View) error {
 	// Set date, keeping current time
 	currentDate := epv.Calendar.CurrentDay.Date
 	newDate := time.Date(year, time.Month(month), day, currentDate.Hour(), currentDate.Minute(), currentDate.Second(), currentDate.Nanosecond(), currentDate.Location())
+	if epv.JumpCallback != nil {
+		epv.JumpCallback()
+	}
 	epv.Calendar.CurrentDay.Date = newDate
 	epv.Calendar.UpdateWeek()
 
@@ -249,6 +309,34 @@ func (epv *EventPopupView) SetDuration(g *gocui.Gui, v *gocui.View) error {
 	return epv.Close(g, v)
 }
 
+// PasteAtTime handler pastes at the time typed in the paste popup
+func (epv *EventPopupView) PasteAtTime(g *gocui.Gui, v *gocui.View) error {
+	if !epv.IsVisible {
+		return nil
+	}
+
+	for _, v := range epv.Form.GetInputs() {
+		if !v.IsValid() {
+			return nil
+		}
+	}
+
+	// The field has been validated as HH:MM
+	clock, err := time.Parse("15:04", strings.TrimSpace(epv.Form.GetFieldText("Time")))
+	if err != nil {
+		return nil
+	}
+
+	// Close first, as the paste may show a conflict dialog in the popup
+	if err := epv.Close(g, v); err != nil {
+		return err
+	}
+	if epv.PasteTimeCallback != nil {
+		return epv.PasteTimeCallback(clock.Hour(), clock.Minute())
+	}
+	return nil
+}
+
 // ExecuteSearch handler for executing search
 func (epv *EventPopupView) ExecuteSearch(g *gocui.Gui, v *gocui.View) error {
 	if !epv.IsVisible {
@@ -262,6 +350,7 @@ func (epv *EventPopupView) ExecuteSearch(g *gocui.Gui, v *gocui.View) error {
 		StartTime: "", // No separate time fields in simplified form
 		EndDate:   strings.TrimSpace(epv.Form.GetFieldText("To Date")),
 		EndTime:   "", // No separate time fields in simplified form
+		WeekStart: config.GetWeekStart(epv.Config),
 	}
 
 	// At least one search parameter must be provided
@@ -269,6 +358,15 @@ func (epv *EventPopupView) ExecuteSearch(g *gocui.Gui, v *gocui.View) error {
 		return epv.Close(g, v)
 	}
 
+	// A named search is saved as a filter, which hides the events it does not match
+	if name := strings.TrimSpace(epv.Form.GetFieldText("Save As")); name != "" && epv.SaveFilterCallback != nil {
+		filter := calendar.Filter{Name: name, Text: criteria.Query, From: criteria.StartDate, To: criteria.EndDate}
+		if err := epv.Close(g, v); err != nil {
+			return err
+		}
+		return epv.SaveFilterCallback(filter)
+	}
+
 	// Call the search callback if it exists
 	if epv.SearchCallback != nil {
 		if err := epv.SearchCallback(criteria); err != nil {
@@ -279,6 +377,169 @@ func (epv *EventPopupView) ExecuteSearch(g *gocui.Gui, v *gocui.View) error {
 	return epv.Close(g, v)
 }
 
+// FindFreeSlots handler searches for free slots and lists them
+func (epv *EventPopupView) FindFreeSlots(g *gocui.Gui, v *gocui.View) error {
+	if !epv.IsVisible {
+		return nil
+	}
+
+	for _, input := range epv.Form.GetInputs() {
+		if !input.IsValid() {
+			return nil
+		}
+	}
+
+	// The fields have been validated, so parsing cannot fail
+	query := freetime.Query{}
+	query.Duration, _ = freetime.ParseDuration(epv.Form.GetFieldText("Duration"))
+	query.DayStart, query.DayEnd, _ = freetime.ParseHours(epv.Form.GetFieldText("Hours"))
+	query.Weekdays, _ = freetime.ParseWeekdays(epv.Form.GetFieldText("Days"))
+	days, _ := strconv.Atoi(strings.TrimSpace(epv.Form.GetFieldText("Within Days")))
+
+	// Search from now when starting today, otherwise from the start of the given day
+	now := time.Now()
+	query.From = now
+	if dateStr := strings.TrimSpace(epv.Form.GetFieldText("From Date")); dateStr != "" && dateStr != "t" {
+		if date, err := time.ParseInLocation("20060102", dateStr, time.Local); err == nil && date.After(now) {
+			query.From = date
+		}
+	}
+	start := query.From
+	query.To = time.Date(start.Year(), start.Month(), start.Day()+days, 0, 0, 0, 0, time.Local)
+
+	slots, err := freetime.Find(epv.EventManager, query)
+	if err != nil {
+		return epv.ShowErrorMessage(g, "Find Free Slot", err.Error())
+	}
+	if len(slots) == 0 {
+		return epv.ShowErrorMessage(g, "Find Free Slot", "No free "+formatSlotDuration(query.Duration)+" slots in that range")
+	}
+
+	if err := epv.Close(g, v); err != nil {
+		return err
+	}
+	return epv.showFreeSlotList(g, slots, query.Duration)
+}
+
+// SelectFreeSlot handler closes the free slot list and hands the highlighted slot to the callback
+func (epv *EventPopupView) SelectFreeSlot(g *gocui.Gui, v *gocui.View) error {
+	if epv.freeSlotIndex >= len(epv.freeSlots) {
+		return epv.closeFreeSlotList(g)
+	}
+	slot := epv.freeSlots[epv.freeSlotIndex]
+	duration := epv.freeSlotDuration
+
+	if err := epv.closeFreeSlotList(g); err != nil {
+		return err
+	}
+	if epv.FreeSlotCallback != nil {
+		return epv.FreeSlotCallback(slot, duration)
+	}
+	return nil
+}
+
+// QuickAdd handler parses the quick-add phrase and shows the result for confirmation
+func (epv *EventPopupView) QuickAdd(g *gocui.Gui, v *gocui.View) error {
+	if !epv.IsVisible {
+		return nil
+	}
+
+	input := strings.TrimSpace(epv.Form.GetFieldText("Event"))
+	if input == "" {
+		return epv.Close(g, v)
+	}
+
+	parser := quickadd.NewParser(time.Now(), epv.Calendar.CurrentDay.Date, config.GetDefaultEventLength(epv.Config))
+	result, err := parser.Parse(input)
+	if err != nil {
+		return epv.ShowErrorMessage(g, "Quick Add", err.Error())
+	}
+
+	if err := epv.Close(g, v); err != nil {
+		return err
+	}
+	return epv.showQuickAddConfirmation(g, result)
+}
+
+// showConflictsIfAny opens the conflict dialog if the event would be refused for overlapping
+// others. eventId is the ID of the edited event, or 0 for a new one.
+func (epv *EventPopupView) showConflictsIfAny(g *gocui.Gui, event *calendar.Event, eventId int) (bool, error) {
+	if epv.EventManager.GetOverlapPolicy() != eventmanager.OverlapForbid {
+		return false, nil
+	}
+
+	overlapping, err := epv.EventManager.FindOverlappingEvents(event, eventId)
+	if err != nil || len(overlapping) == 0 {
+		// Let the EventManager report database errors as usual
+		return false, nil
+	}
+
+	localEvents := make([]*calendar.Event, len(overlapping))
+	for i, other := range overlapping {
+		localEvent := *other
+		localEvent.Time = other.Time.In(time.Local)
+		localEvents[i] = &localEvent
+	}
+
+	return true, epv.showConflictDialog(g, event, eventId, localEvents)
+}
+
+// ShowAddConflicts opens the conflict dialog if a new event, or any occurrence of a new series,
+// would be refused for overlapping. It reports whether the dialog was opened.
+func (epv *EventPopupView) ShowAddConflicts(g *gocui.Gui, events []calendar.Event) (bool, error) {
+	if len(events) == 1 {
+		return epv.showConflictsIfAny(g, &events[0], 0)
+	}
+	if len(events) == 0 || epv.EventManager.GetOverlapPolicy() != eventmanager.OverlapForbid {
+		return false, nil
+	}
+
+	var clashes []*calendar.Event
+	for i := range events {
+		overlapping, err := epv.EventManager.FindOverlappingEvents(&events[i])
+		if err != nil {
+			// Let the EventManager report database errors as usual
+			return false, nil
+		}
+		if len(overlapping) > 0 {
+			clashes = append(clashes, &events[i])
+		}
+	}
+	if len(clashes) == 0 {
+		return false, nil
+	}
+
+	return true, epv.showSeriesConflictDialog(g, events, clashes)
+}
+
+// ResolveConflict applies one of the conflict dialog's resolutions as a single undoable change
+func (epv *EventPopupView) ResolveConflict(g *gocui.Gui, resolution eventmanager.Resolution) error {
+	plan, ok := epv.conflictPlans[resolution]
+	if !ok {
+		// Not possible for this conflict; the dialog says why
+		return nil
+	}
+	eventId := epv.conflictEventId
+
+	if err := epv.closeConflictDialog(g); err != nil {
+		return err
+	}
+	if !epv.EventManager.ApplyConflictPlan(plan, eventId) {
+		// Error is handled by EventManager internally
+		return nil
+	}
+
+	// Follow the event if it moved
+	epv.Calendar.CurrentDay.Date = plan.Event.Time
+	epv.Calendar.UpdateWeek()
+
+	if !epv.IsVisible {
+		// Opened from the command line, with no form to close
+		return nil
+	}
+	return epv.Close(g, nil)
+}
+
 // addKeybind adds a keybinding to all form items
 func (epv *EventPopupView) addKeybind(key interface{}, handler func(g *gocui.Gui, v *gocui.View) error) {
 	for _, item := range epv.Form.GetItems() {
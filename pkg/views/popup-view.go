@@ -9,6 +9,8 @@ import (
 	"github.com/samuelstranges/chronos/internal/config"
 	"github.com/samuelstranges/chronos/internal/database"
 	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/freetime"
+	"github.com/samuelstranges/chronos/internal/quickadd"
 	component "github.com/j-04/gocui-component"
 	"github.com/jroimartin/gocui"
 )
@@ -23,8 +25,29 @@ type EventPopupView struct {
 
 	IsVisible bool
 	SearchCallback func(criteria database.SearchCriteria) error
+	// SaveFilterCallback is called instead of SearchCallback when the search is saved as a filter
+	SaveFilterCallback func(filter calendar.Filter) error
 	ColorPickerCallback func(colorName string) error
 	DurationCallback func(duration float64) error
+	PasteTimeCallback func(hour, minute int) error
+	FreeSlotCallback func(slot freetime.Slot, duration time.Duration) error
+	// JumpCallback is called before the date popup moves the selection
+	JumpCallback func()
+
+	// Conflict dialog state: the event that overlaps, the ID it is saved under (0 for a new
+	// event) and the possible resolutions
+	conflictEvent   *calendar.Event
+	conflictEventId int
+	conflictPlans   map[eventmanager.Resolution]*eventmanager.ConflictPlan
+
+	// Free slot list state: the slots found, the duration searched for and the highlighted row
+	freeSlots        []freetime.Slot
+	freeSlotDuration time.Duration
+	freeSlotIndex    int
+
+	// description is the full text of the event forms' Description field, which only shows it
+	// on one line
+	description string
 }
 
 func NewEvenPopup(g *gocui.Gui, c *calendar.Calendar, db *database.Database, em *eventmanager.EventManager, cfg *config.Config) *EventPopupView {
@@ -48,6 +71,11 @@ func (epv *EventPopupView) Update(g *gocui.Gui) error {
 
 
 func (epv *EventPopupView) ShowNewEventPopup(g *gocui.Gui) error {
+	return epv.ShowNewEventPopupWithDuration(g, config.GetDefaultEventLength(epv.Config))
+}
+
+// ShowNewEventPopupWithDuration displays the new event form at the cursor with the given duration in hours
+func (epv *EventPopupView) ShowNewEventPopupWithDuration(g *gocui.Gui, duration float64) error {
 	if epv.IsVisible {
 		return nil
 	}
@@ -58,12 +86,13 @@ func (epv *EventPopupView) ShowNewEventPopup(g *gocui.Gui) error {
 	
 	// Get defaults from config
 	defaultColor := config.GetDefaultColor(epv.Config)
-	defaultDuration := fmt.Sprintf("%.1f", config.GetDefaultEventLength(epv.Config))
+	defaultDuration := fmt.Sprintf("%.1f", duration)
 	
 	epv.Form = epv.NewEventForm(g, "New Event", "", defaultDate, defaultTime, "", defaultDuration, "7", "1", "", defaultColor)
 
 	epv.addKeybind(gocui.KeyEsc, epv.Close)
 	epv.addKeybind(gocui.KeyEnter, epv.AddEvent)
+	epv.addKeybind(gocui.KeyCtrlE, epv.EditDescriptionInEditor)
 
 	epv.Form.AddButton("Add", epv.AddEvent)
 	epv.Form.AddButton("Cancel", epv.Close)
@@ -77,6 +106,65 @@ func (epv *EventPopupView) ShowNewEventPopup(g *gocui.Gui) error {
 	return nil
 }
 
+// ShowQuickAddPopup displays a single-line prompt for natural-language event entry
+func (epv *EventPopupView) ShowQuickAddPopup(g *gocui.Gui) error {
+	if epv.IsVisible {
+		return nil
+	}
+
+	epv.Form = epv.QuickAddForm(g, "Quick Add: Lunch tomorrow 12:30 for 90m at Cafe")
+
+	epv.addKeybind(gocui.KeyEsc, epv.Close)
+	epv.addKeybind(gocui.KeyEnter, epv.QuickAdd)
+
+	epv.Form.AddButton("Preview", epv.QuickAdd)
+	epv.Form.AddButton("Cancel", epv.Close)
+
+	epv.Form.SetCurrentItem(0)
+	epv.IsVisible = true
+	epv.Form.Draw()
+
+	return nil
+}
+
+// showQuickAddConfirmation shows the parsed event in the new event form so it can be checked before saving
+func (epv *EventPopupView) showQuickAddConfirmation(g *gocui.Gui, result *quickadd.Result) error {
+	date := result.Start.Format("20060102")
+	startTime := result.Start.Format("15:04")
+	duration := strconv.FormatFloat(result.DurationHours, 'f', -1, 64)
+
+	frequency := "7"
+	if result.FrequencyDays == -1 {
+		frequency = "w"
+	} else if result.FrequencyDays > 0 {
+		frequency = strconv.Itoa(result.FrequencyDays)
+	}
+
+	color := config.GetDefaultColor(epv.Config)
+	if result.Color != 0 {
+		color = calendar.ColorAttributeToName(result.Color)
+	}
+
+	// The prompt is wider than the event form, so re-centre at the normal width
+	epv.SetProperties(epv.X+(epv.W-PopupWidth)/2, epv.Y, PopupWidth, epv.H)
+	epv.Form = epv.NewEventForm(g, "Confirm Quick Add", result.Name, date, startTime, result.Location, duration, frequency, strconv.Itoa(result.Occurrences), "", color)
+
+	epv.addKeybind(gocui.KeyEsc, epv.Close)
+	epv.addKeybind(gocui.KeyEnter, epv.AddEvent)
+	epv.addKeybind(gocui.KeyCtrlE, epv.EditDescriptionInEditor)
+
+	epv.Form.AddButton("Add", epv.AddEvent)
+	epv.Form.AddButton("Cancel", epv.Close)
+
+	epv.Form.SetCurrentItem(0)
+	epv.IsVisible = true
+	epv.Form.Draw()
+
+	epv.positionCursorsAtEnd(g)
+
+	return nil
+}
+
 func (epv *EventPopupView) ShowEditEventPopup(g *gocui.Gui, eventView *EventView) error {
 	if epv.IsVisible {
 		return nil
@@ -103,6 +191,7 @@ func (epv *EventPopupView) ShowEditEventPopup(g *gocui.Gui, eventView *EventView
 	}
 	epv.addKeybind(gocui.KeyEsc, epv.Close)
 	epv.addKeybind(gocui.KeyEnter, editHandler)
+	epv.addKeybind(gocui.KeyCtrlE, epv.EditDescriptionInEditor)
 
 	epv.Form.AddButton("Change", editHandler)
 	epv.Form.AddButton("Cancel", epv.Close)
@@ -201,6 +290,161 @@ func (epv *EventPopupView) closeErrorPopup(g *gocui.Gui) error {
 }
 
 
+// showConflictDialog lists the events an add or edit overlaps and offers ways to resolve it.
+// The form stays open underneath so Esc returns to it with the input intact.
+func (epv *EventPopupView) showConflictDialog(g *gocui.Gui, event *calendar.Event, eventId int, overlapping []*calendar.Event) error {
+	lines := []string{
+		fmt.Sprintf(" %s %s overlaps:", event.Name, formatConflictTime(event)),
+	}
+	for _, other := range overlapping {
+		lines = append(lines, fmt.Sprintf("   %-20.20s %s", other.Name, formatConflictTime(other)))
+	}
+
+	return epv.openConflictDialog(g, event, eventId, lines, func(resolution eventmanager.Resolution) (*eventmanager.ConflictPlan, error) {
+		return epv.EventManager.PlanConflictResolution(event, eventId, resolution)
+	})
+}
+
+// showSeriesConflictDialog lists the occurrences of a new series that overlap other events and
+// offers ways to resolve them all at once
+func (epv *EventPopupView) showSeriesConflictDialog(g *gocui.Gui, events []calendar.Event, clashes []*calendar.Event) error {
+	lines := []string{
+		fmt.Sprintf(" %d of %d occurrences of %s overlap:", len(clashes), len(events), events[0].Name),
+	}
+	for i, clash := range clashes {
+		if i == ConflictMaxOccurrences {
+			lines = append(lines, fmt.Sprintf("   and %d more", len(clashes)-i))
+			break
+		}
+		lines = append(lines, "   "+formatConflictTime(clash))
+	}
+
+	return epv.openConflictDialog(g, &events[0], 0, lines, func(resolution eventmanager.Resolution) (*eventmanager.ConflictPlan, error) {
+		return epv.EventManager.PlanSeriesResolution(events, resolution)
+	})
+}
+
+// openConflictDialog shows the lines describing a conflict followed by the resolutions planFor works
+// out, and binds their keys
+func (epv *EventPopupView) openConflictDialog(g *gocui.Gui, event *calendar.Event, eventId int, lines []string, planFor func(eventmanager.Resolution) (*eventmanager.ConflictPlan, error)) error {
+	epv.conflictEvent = event
+	epv.conflictEventId = eventId
+	epv.conflictPlans = make(map[eventmanager.Resolution]*eventmanager.ConflictPlan)
+	lines = append(lines, "")
+
+	options := []struct {
+		key        string
+		label      string
+		resolution eventmanager.Resolution
+		describe   func(plan *eventmanager.ConflictPlan) string
+	}{
+		{"s", "Shift", eventmanager.ResolveShift, func(plan *eventmanager.ConflictPlan) string {
+			return describeResolved("Shift", plan)
+		}},
+		{"f", "Shorten", eventmanager.ResolveShorten, func(plan *eventmanager.ConflictPlan) string {
+			return describeResolved("Shorten", plan)
+		}},
+		{"p", "Push", eventmanager.ResolvePush, func(plan *eventmanager.ConflictPlan) string {
+			description := fmt.Sprintf("Push %d event(s) later", len(plan.Moved))
+			if len(plan.Moved) > 0 {
+				description += ": " + plan.Moved[0].Name + " to " + formatConflictTime(plan.Moved[0])
+			}
+			return description
+		}},
+	}
+	for _, option := range options {
+		plan, err := planFor(option.resolution)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf(" [%s] %s: not possible (%v)", option.key, option.label, err))
+			continue
+		}
+		epv.conflictPlans[option.resolution] = plan
+		lines = append(lines, fmt.Sprintf(" [%s] %s", option.key, option.describe(plan)))
+	}
+	if epv.IsVisible {
+		lines = append(lines, " [Esc] Back to the form")
+	} else {
+		lines = append(lines, " [Esc] Cancel")
+	}
+
+	maxX, maxY := g.Size()
+	width := ConflictWidth
+	height := len(lines) + 1
+	x := (maxX - width) / 2
+	y := (maxY - height) / 2
+
+	v, err := g.SetView(ConflictViewName, x, y, x+width, y+height)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Title = " Conflict "
+	v.Frame = true
+	v.FgColor = gocui.ColorYellow
+	v.Clear()
+	for _, line := range lines {
+		fmt.Fprintln(v, line)
+	}
+
+	g.DeleteKeybindings(ConflictViewName)
+	for key, resolution := range map[rune]eventmanager.Resolution{
+		's': eventmanager.ResolveShift,
+		'f': eventmanager.ResolveShorten,
+		'p': eventmanager.ResolvePush,
+	} {
+		resolution := resolution
+		if err := g.SetKeybinding(ConflictViewName, key, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+			return epv.ResolveConflict(g, resolution)
+		}); err != nil {
+			return err
+		}
+	}
+	if err := g.SetKeybinding(ConflictViewName, gocui.KeyEsc, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		return epv.closeConflictDialog(g)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := g.SetViewOnTop(ConflictViewName); err != nil {
+		return err
+	}
+	_, err = g.SetCurrentView(ConflictViewName)
+	return err
+}
+
+// ConflictDialogOpen reports whether the conflict dialog is waiting for a resolution
+func (epv *EventPopupView) ConflictDialogOpen() bool {
+	return epv.conflictPlans != nil
+}
+
+// closeConflictDialog removes the conflict dialog and returns focus to the form, if there is one
+func (epv *EventPopupView) closeConflictDialog(g *gocui.Gui) error {
+	epv.conflictEvent = nil
+	epv.conflictPlans = nil
+	g.DeleteKeybindings(ConflictViewName)
+	if err := g.DeleteView(ConflictViewName); err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+
+	if epv.IsVisible && epv.Form != nil {
+		epv.Form.SetCurrentItem(epv.Form.GetCurrentItem())
+	}
+	return nil
+}
+
+// describeResolved describes moving or shortening the event of a plan, or the occurrences of a
+// series it changes
+func describeResolved(verb string, plan *eventmanager.ConflictPlan) string {
+	if plan.Series == nil {
+		return verb + " to " + formatConflictTime(plan.Event)
+	}
+	return fmt.Sprintf("%s %d occurrence(s), the first to %s", verb, plan.Resolved, formatConflictTime(plan.Event))
+}
+
+// formatConflictTime formats an event's day and time range for the conflict dialog
+func formatConflictTime(event *calendar.Event) string {
+	return event.Time.Format("Mon 2 15:04") + "-" + event.EndTime().Format("15:04")
+}
+
 func (epv *EventPopupView) ShowGotoPopup(g *gocui.Gui) error {
 	if epv.IsVisible {
 		return nil
@@ -223,6 +467,29 @@ func (epv *EventPopupView) ShowGotoPopup(g *gocui.Gui) error {
 	return nil
 }
 
+// ShowPasteTimePopup asks for the time to paste events at in the views without time slots
+func (epv *EventPopupView) ShowPasteTimePopup(g *gocui.Gui, defaultTime string) error {
+	if epv.IsVisible {
+		return nil
+	}
+
+	epv.Form = epv.PasteTimeForm(g, "Paste At", defaultTime)
+
+	epv.addKeybind(gocui.KeyEsc, epv.Close)
+	epv.addKeybind(gocui.KeyEnter, epv.PasteAtTime)
+
+	epv.Form.AddButton("Paste", epv.PasteAtTime)
+	epv.Form.AddButton("Cancel", epv.Close)
+
+	epv.Form.SetCurrentItem(0)
+	epv.IsVisible = true
+	epv.Form.Draw()
+
+	epv.positionCursorsAtEnd(g)
+
+	return nil
+}
+
 func (epv *EventPopupView) ShowDatePopup(g *gocui.Gui) error {
 	if epv.IsVisible {
 		return nil
@@ -312,3 +579,119 @@ func (epv *EventPopupView) ShowSearchPopup(g *gocui.Gui) error {
 	return nil
 }
 
+
+// ShowFreeSlotPopup displays the form for finding free slots
+func (epv *EventPopupView) ShowFreeSlotPopup(g *gocui.Gui) error {
+	if epv.IsVisible {
+		return nil
+	}
+
+	epv.Form = epv.FreeSlotForm(g, "Find Free Slot")
+
+	epv.addKeybind(gocui.KeyEsc, epv.Close)
+	epv.addKeybind(gocui.KeyEnter, epv.FindFreeSlots)
+
+	epv.Form.AddButton("Find", epv.FindFreeSlots)
+	epv.Form.AddButton("Cancel", epv.Close)
+
+	epv.Form.SetCurrentItem(0)
+	epv.IsVisible = true
+	epv.Form.Draw()
+
+	epv.positionCursorsAtEnd(g)
+
+	return nil
+}
+
+// showFreeSlotList lists the free slots found; j/k move the highlight and Enter picks a slot.
+// The popup stays marked visible so the calendar does not take focus back while the list is open.
+func (epv *EventPopupView) showFreeSlotList(g *gocui.Gui, slots []freetime.Slot, duration time.Duration) error {
+	epv.freeSlots = slots
+	epv.freeSlotDuration = duration
+	epv.freeSlotIndex = 0
+	epv.IsVisible = true
+
+	maxX, maxY := g.Size()
+	width := FreeSlotsWidth
+	height := len(slots) + 1
+	if height > FreeSlotsMaxRows+1 {
+		height = FreeSlotsMaxRows + 1
+	}
+	x := (maxX - width) / 2
+	y := (maxY - height) / 2
+
+	v, err := g.SetView(FreeSlotsViewName, x, y, x+width, y+height)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Title = fmt.Sprintf(" Free %s slots ", formatSlotDuration(duration))
+	v.Frame = true
+	v.Highlight = true
+	v.SelBgColor = gocui.ColorGreen
+	v.SelFgColor = gocui.ColorBlack
+	v.Clear()
+	for _, slot := range slots {
+		fmt.Fprintf(v, " %s %s-%s  %sh free\n", slot.Start.Format("Mon Jan 2"), slot.Start.Format("15:04"),
+			slot.End.Format("15:04"), strconv.FormatFloat(slot.Hours(), 'f', -1, 64))
+	}
+	v.SetOrigin(0, 0)
+	v.SetCursor(0, 0)
+
+	g.DeleteKeybindings(FreeSlotsViewName)
+	keys := []struct {
+		key     interface{}
+		handler func(g *gocui.Gui, v *gocui.View) error
+	}{
+		{'j', func(g *gocui.Gui, v *gocui.View) error { return epv.moveFreeSlotSelection(v, 1) }},
+		{'k', func(g *gocui.Gui, v *gocui.View) error { return epv.moveFreeSlotSelection(v, -1) }},
+		{gocui.KeyArrowDown, func(g *gocui.Gui, v *gocui.View) error { return epv.moveFreeSlotSelection(v, 1) }},
+		{gocui.KeyArrowUp, func(g *gocui.Gui, v *gocui.View) error { return epv.moveFreeSlotSelection(v, -1) }},
+		{gocui.KeyEnter, epv.SelectFreeSlot},
+		{gocui.KeyEsc, func(g *gocui.Gui, v *gocui.View) error { return epv.closeFreeSlotList(g) }},
+		{'q', func(g *gocui.Gui, v *gocui.View) error { return epv.closeFreeSlotList(g) }},
+	}
+	for _, kb := range keys {
+		if err := g.SetKeybinding(FreeSlotsViewName, kb.key, gocui.ModNone, kb.handler); err != nil {
+			return err
+		}
+	}
+
+	g.Cursor = false
+	_, err = g.SetCurrentView(FreeSlotsViewName)
+	return err
+}
+
+// moveFreeSlotSelection moves the highlighted slot, scrolling the list when needed
+func (epv *EventPopupView) moveFreeSlotSelection(v *gocui.View, direction int) error {
+	index := epv.freeSlotIndex + direction
+	if index < 0 || index >= len(epv.freeSlots) {
+		return nil
+	}
+	epv.freeSlotIndex = index
+
+	_, height := v.Size()
+	_, originY := v.Origin()
+	if index < originY {
+		originY = index
+	} else if index >= originY+height {
+		originY = index - height + 1
+	}
+	v.SetOrigin(0, originY)
+	return v.SetCursor(0, index-originY)
+}
+
+// closeFreeSlotList removes the free slot list and hands focus back to the calendar
+func (epv *EventPopupView) closeFreeSlotList(g *gocui.Gui) error {
+	epv.freeSlots = nil
+	epv.IsVisible = false
+	g.DeleteKeybindings(FreeSlotsViewName)
+	if err := g.DeleteView(FreeSlotsViewName); err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	return nil
+}
+
+// formatSlotDuration formats a searched-for duration for the free slot list title, e.g. "1.5h"
+func formatSlotDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', -1, 64) + "h"
+}
@@ -17,6 +17,14 @@ type TitleView struct {
 	CurrentDate time.Time
 	WeatherData string
 	CurrentEvent string
+
+	// RangeStart and RangeEnd are the first and last days of the compact weeks grid or the agenda
+	RangeStart time.Time
+	RangeEnd   time.Time
+
+	// AgendaSearch is set while the agenda lists AgendaResults search results instead of a range
+	AgendaSearch  bool
+	AgendaResults int
 }
 
 func NewTitleView(c *calendar.Calendar) *TitleView {
@@ -73,7 +81,10 @@ func (tv *TitleView) updateBody(v *gocui.View) {
 	line2 := "Current event: " + tv.CurrentEvent
 	
 	// Line 3: View context information (will be updated by AppView)
-	line3 := tv.getContextualInfo()
+	line3 := fmt.Sprintf("%s - Week %d", tv.getContextualInfo(), tv.weekNumber())
+	if filter := tv.Calendar.Filter; filter != nil {
+		line3 += fmt.Sprintf(" | Filter: %s (%s)", filter.Name, filter.Describe())
+	}
 
 	v.Clear()
 	fmt.Fprintln(v, line1)
@@ -86,9 +97,32 @@ func (tv *TitleView) getContextualInfo() string {
 	case "month":
 		currentDate := tv.Calendar.CurrentDay.Date
 		return fmt.Sprintf("Showing month: %s %d", currentDate.Month().String(), currentDate.Year())
-	case "agenda":
+	case "days":
+		if len(tv.Calendar.CurrentWeek.Days) == 1 {
+			currentDate := tv.Calendar.CurrentDay.Date
+			return fmt.Sprintf("Showing 1 day: %s %d, %d", currentDate.Month().String(), currentDate.Day(), currentDate.Year())
+		}
+		return fmt.Sprintf("Showing %d days: %s, %d", len(tv.Calendar.CurrentWeek.Days), tv.Calendar.FormatWeekBody(), tv.Calendar.CurrentWeek.StartDate.Year())
+	case "weeks":
+		weeks := (int((tv.RangeEnd.Sub(tv.RangeStart).Hours()+12)/24) + 1) / 7
+		return fmt.Sprintf("Showing %d weeks: %s %d to %s %d, %d", weeks, tv.RangeStart.Month().String(), tv.RangeStart.Day(),
+			tv.RangeEnd.Month().String(), tv.RangeEnd.Day(), tv.RangeStart.Year())
+	case "year":
+		currentDate := tv.Calendar.CurrentDay.Date
+		return fmt.Sprintf("Showing year: %d (%s, %s %d)", currentDate.Year(), currentDate.Weekday().String(), currentDate.Month().String(), currentDate.Day())
+	case "day":
 		currentDate := tv.Calendar.CurrentDay.Date
-		return fmt.Sprintf("Showing agenda: %s %d, %d", currentDate.Month().String(), currentDate.Day(), currentDate.Year())
+		return fmt.Sprintf("Showing day: %s, %s %d, %d", currentDate.Weekday().String(), currentDate.Month().String(), currentDate.Day(), currentDate.Year())
+	case "agenda":
+		if tv.AgendaSearch {
+			return fmt.Sprintf("Showing agenda: %d search results from %s %d, %d", tv.AgendaResults,
+				tv.RangeStart.Month().String(), tv.RangeStart.Day(), tv.RangeStart.Year())
+		}
+		if tv.RangeEnd.Equal(tv.RangeStart) {
+			return fmt.Sprintf("Showing agenda: %s %d, %d", tv.RangeStart.Month().String(), tv.RangeStart.Day(), tv.RangeStart.Year())
+		}
+		return fmt.Sprintf("Showing agenda: %s %d to %s %d, %d", tv.RangeStart.Month().String(), tv.RangeStart.Day(),
+			tv.RangeEnd.Month().String(), tv.RangeEnd.Day(), tv.RangeStart.Year())
 	default: // "week" or empty
 		selectedWeek := tv.Calendar.FormatWeekBody()
 		startDate := tv.Calendar.CurrentWeek.StartDate
@@ -96,6 +130,15 @@ func (tv *TitleView) getContextualInfo() string {
 	}
 }
 
+// weekNumber returns the ISO week number of the displayed week, or of the selected day's week in
+// views that do not show a single week
+func (tv *TitleView) weekNumber() int {
+	if tv.ViewMode == "week" || tv.ViewMode == "" {
+		return calendar.WeekNumber(tv.Calendar.CurrentWeek.StartDate)
+	}
+	return calendar.WeekNumber(calendar.StartOfWeek(tv.Calendar.CurrentDay.Date, tv.Calendar.WeekStart))
+}
+
 // SetViewMode sets the current view mode for contextual display
 func (tv *TitleView) SetViewMode(mode string) {
 	tv.ViewMode = mode
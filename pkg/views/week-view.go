@@ -1,21 +1,23 @@
 package views
 
 import (
+	"fmt"
+
 	"github.com/samuelstranges/chronos/internal/calendar"
 	"github.com/samuelstranges/chronos/internal/config"
 	"github.com/samuelstranges/chronos/internal/weather"
 	"github.com/jroimartin/gocui"
 )
 
-var WeekdayNames = []string{
-	"Sunday",
-	"Monday",
-	"Tuesday",
-	"Wednesday",
-	"Thursday",
-	"Friday",
-	"Saturday",
-}
+// DayColumnNames are the view names of the day columns, left to right. A week uses the first
+// seven; rolling layouts use up to MaxDayColumns.
+var DayColumnNames = func() []string {
+	names := make([]string, MaxDayColumns)
+	for i := range names {
+		names[i] = fmt.Sprintf("day_%d", i)
+	}
+	return names
+}()
 
 type WeekView struct {
 	*BaseView
@@ -23,6 +25,9 @@ type WeekView struct {
 	Calendar *calendar.Calendar
 
 	TimeView *TimeView
+
+	// SingleDay lays out only the selected day, full width with detailed event blocks
+	SingleDay bool
 }
 
 func NewWeekView(c *calendar.Calendar, tv *TimeView) *WeekView {
@@ -32,13 +37,26 @@ func NewWeekView(c *calendar.Calendar, tv *TimeView) *WeekView {
 		TimeView: tv,
 	}
 
-	for i, dayName := range WeekdayNames {
-		wv.AddChild(dayName, NewDayView(dayName, c.CurrentWeek.Days[i], tv))
+	for i, day := range c.CurrentWeek.Days {
+		wv.AddChild(DayColumnNames[i], NewDayView(DayColumnNames[i], day, tv))
 	}
 
 	return wv
 }
 
+// columnNames returns the names of the columns in use by the calendar's current layout
+func (wv *WeekView) columnNames() []string {
+	return DayColumnNames[:len(wv.Calendar.CurrentWeek.Days)]
+}
+
+// selectedColumn returns the name of the column showing the selected day
+func (wv *WeekView) selectedColumn() string {
+	if i := wv.Calendar.DayIndex(wv.Calendar.CurrentDay.Date); i >= 0 {
+		return DayColumnNames[i]
+	}
+	return DayColumnNames[0]
+}
+
 func (wv *WeekView) Update(g *gocui.Gui) error {
 	v, err := g.SetView(
 		wv.Name,
@@ -56,19 +74,46 @@ func (wv *WeekView) Update(g *gocui.Gui) error {
 
 	wv.updateChildViewProperties()
 
-	if err = wv.UpdateChildren(g); err != nil {
-		return err
+	if !wv.SingleDay {
+		return wv.UpdateChildren(g)
+	}
+
+	// Only the selected day is drawn; the other days are removed until they are selected
+	selected := wv.selectedColumn()
+	for _, column := range wv.columnNames() {
+		if dayView, ok := wv.GetChild(column); ok {
+			if column == selected {
+				if err = dayView.Update(g); err != nil {
+					return err
+				}
+			} else if dv, ok := dayView.(*DayView); ok {
+				dv.deleteFromGUI(g)
+			}
+		}
 	}
 
 	return nil
 }
 
 func (wv *WeekView) updateChildViewProperties() {
-	x := wv.X
-	w := wv.W/7 - Padding
+	if wv.SingleDay {
+		wv.updateSingleDayProperties()
+		return
+	}
 
-	for _, weekday := range WeekdayNames {
-		if dayView, ok := wv.GetChild(weekday); ok {
+	columns := wv.columnNames()
+	x := wv.X
+	w := wv.W/len(columns) - Padding
+
+	for _, column := range columns {
+		if dayView, ok := wv.GetChild(column); ok {
+			if dv, ok := dayView.(*DayView); ok {
+				dv.SelectedEventId = wv.Calendar.SelectedEventId
+				dv.Selection = wv.visualSelection()
+				dv.Grab = wv.Calendar.Grab
+				// Rolling layouts with few days have room for detailed event blocks
+				dv.ShowDetails = wv.Calendar.Rolling && w >= DetailedColumnWidth
+			}
 
 			dayView.SetProperties(
 				x,
@@ -82,6 +127,49 @@ func (wv *WeekView) updateChildViewProperties() {
 	}
 }
 
+// DayAt returns the day column drawn at screen position x, y
+func (wv *WeekView) DayAt(x, y int) (*DayView, bool) {
+	columns := wv.columnNames()
+	if wv.SingleDay {
+		columns = []string{wv.selectedColumn()}
+	}
+	for _, column := range columns {
+		if dayView, ok := wv.GetChild(column); ok {
+			if dv, ok := dayView.(*DayView); ok && x > dv.X && x < dv.X+dv.W && y > dv.Y && y < dv.Y+dv.H {
+				return dv, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// visualSelection returns the block selected in visual mode, nil outside it
+func (wv *WeekView) visualSelection() *calendar.Selection {
+	if selection, ok := wv.Calendar.Visual(); ok {
+		return &selection
+	}
+	return nil
+}
+
+// updateSingleDayProperties gives the selected day the full width of the view
+func (wv *WeekView) updateSingleDayProperties() {
+	if dayView, ok := wv.GetChild(wv.selectedColumn()); ok {
+		if dv, ok := dayView.(*DayView); ok {
+			dv.SelectedEventId = wv.Calendar.SelectedEventId
+			dv.Selection = wv.visualSelection()
+			dv.Grab = wv.Calendar.Grab
+			dv.ShowDetails = true
+		}
+
+		dayView.SetProperties(
+			wv.X,
+			wv.Y+1,
+			wv.W-Padding,
+			wv.H-2,
+		)
+	}
+}
+
 // UpdateWeatherData updates weather information for all day columns in week view
 func (wv *WeekView) UpdateWeatherData(cfg *config.Config, weatherCache *weather.WeatherCache) error {
 	if !config.IsWeatherEnabled(cfg) {
@@ -120,8 +208,8 @@ func (wv *WeekView) UpdateWeatherData(cfg *config.Config, weatherCache *weather.
 	}
 	
 	// Update weather data for all day views in the week
-	for _, weekday := range WeekdayNames {
-		if dayViewInterface, ok := wv.GetChild(weekday); ok {
+	for _, column := range wv.columnNames() {
+		if dayViewInterface, ok := wv.GetChild(column); ok {
 			if dayView, ok := dayViewInterface.(*DayView); ok {
 				dateStr := dayView.Day.Date.Format("2006-01-02")
 				if weatherData, exists := weatherMap[dateStr]; exists {
@@ -0,0 +1,235 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+)
+
+const (
+	yearMonthWidth  = 20 // 7 two-digit days separated by spaces
+	yearMonthGap    = 3
+	yearMonthHeight = 8 // month name, weekday header and up to 6 weeks
+)
+
+// yearHeatColors are the 256-colour backgrounds for 0, ≤2, ≤4, ≤6 and >6 booked hours
+var yearHeatColors = []int{0, 22, 28, 34, 40}
+
+// YearView shows twelve mini-months with every day shaded by the hours booked on it
+type YearView struct {
+	*BaseView
+
+	Calendar     *calendar.Calendar
+	EventManager *eventmanager.EventManager
+
+	// Booked hours per day of the displayed year, keyed by "2006-01-02"
+	bookedHours map[string]float64
+}
+
+func NewYearView(c *calendar.Calendar, em *eventmanager.EventManager) *YearView {
+	return &YearView{
+		BaseView:     NewBaseView("year"),
+		Calendar:     c,
+		EventManager: em,
+	}
+}
+
+func (yv *YearView) Update(g *gocui.Gui) error {
+	v, err := g.SetView(
+		yv.Name,
+		yv.X,
+		yv.Y,
+		yv.X+yv.W,
+		yv.Y+yv.H,
+	)
+	if err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = false
+	}
+
+	if err := yv.loadBookedHours(); err != nil {
+		return err
+	}
+
+	v.Clear()
+	lines, selectedLine := yv.render()
+	for _, line := range lines {
+		fmt.Fprintln(v, line)
+	}
+
+	// Scroll so the selected month is visible when the terminal is too short for the whole year
+	_, height := v.Size()
+	originY := 0
+	if selectedLine+yearMonthHeight > height {
+		originY = selectedLine + yearMonthHeight - height
+	}
+	v.SetOrigin(0, originY)
+
+	return nil
+}
+
+// loadBookedHours reads the displayed year's events with a single query
+func (yv *YearView) loadBookedHours() error {
+	year := yv.Calendar.CurrentDay.Date.Year()
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	events, err := yv.EventManager.GetEventsByDateRange(start, start.AddDate(1, 0, 0))
+	if err != nil {
+		return err
+	}
+	events = yv.Calendar.Filtered(events)
+
+	localEvents := make([]*calendar.Event, len(events))
+	for i, event := range events {
+		localEvent := *event
+		localEvent.Time = event.Time.In(time.Local)
+		localEvents[i] = &localEvent
+	}
+	yv.bookedHours = calendar.BookedHoursByDate(localEvents)
+	return nil
+}
+
+// monthsPerRow fits as many mini-months side by side as the width allows
+func (yv *YearView) monthsPerRow() int {
+	for _, columns := range []int{6, 4, 3, 2} {
+		if columns*(yearMonthWidth+yearMonthGap) <= yv.W {
+			return columns
+		}
+	}
+	return 1
+}
+
+// render draws the year and returns the lines along with the first line of the selected month
+func (yv *YearView) render() ([]string, int) {
+	selected := yv.Calendar.CurrentDay.Date
+	columns := yv.monthsPerRow()
+	indent := strings.Repeat(" ", (yv.W-columns*(yearMonthWidth+yearMonthGap)+yearMonthGap)/2)
+
+	var lines []string
+	selectedLine := 0
+	for first := 0; first < 12; first += columns {
+		block := make([]string, yearMonthHeight)
+		for i := range block {
+			block[i] = indent
+		}
+		for month := first; month < first+columns && month < 12; month++ {
+			if time.Month(month+1) == selected.Month() {
+				selectedLine = len(lines)
+			}
+			for i, line := range yv.renderMonth(time.Month(month + 1)) {
+				if month > first {
+					block[i] += strings.Repeat(" ", yearMonthGap)
+				}
+				block[i] += line
+			}
+		}
+		lines = append(lines, block...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, indent+yv.legend(), "", indent+"hjkl day  J/K month  Enter week view  Space month view")
+	return lines, selectedLine
+}
+
+// renderMonth draws one mini-month as yearMonthHeight lines of yearMonthWidth visible characters
+func (yv *YearView) renderMonth(month time.Month) []string {
+	selected := yv.Calendar.CurrentDay.Date
+	year := selected.Year()
+	now := time.Now()
+
+	name := month.String()
+	if month == selected.Month() {
+		name = "\x1b[1m" + name + "\x1b[0m"
+	}
+	padding := (yearMonthWidth - len(month.String())) / 2
+	lines := []string{
+		strings.Repeat(" ", padding) + name + strings.Repeat(" ", yearMonthWidth-padding-len(month.String())),
+		yv.weekdayHeader(),
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	day := calendar.StartOfWeek(first, yv.Calendar.WeekStart)
+	for week := 0; week < 6; week++ {
+		cells := make([]string, 7)
+		for i := range cells {
+			if day.Month() != month {
+				cells[i] = "  "
+			} else {
+				isSelected := month == selected.Month() && day.Day() == selected.Day()
+				isToday := day.Year() == now.Year() && day.YearDay() == now.YearDay()
+				cells[i] = yv.renderDay(day, isSelected, isToday)
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+		lines = append(lines, strings.Join(cells, " "))
+	}
+	return lines
+}
+
+// weekdayHeader abbreviates the weekdays to two letters, starting with the configured first day
+func (yv *YearView) weekdayHeader() string {
+	names := make([]string, 7)
+	for i := range names {
+		names[i] = time.Weekday((int(yv.Calendar.WeekStart) + i) % 7).String()[:2]
+	}
+	return strings.Join(names, " ")
+}
+
+// renderDay shades a day by its booked hours; the selected day is highlighted and today is underlined
+func (yv *YearView) renderDay(day time.Time, isSelected, isToday bool) string {
+	text := fmt.Sprintf("%2d", day.Day())
+	level := yearHeatLevel(yv.bookedHours[day.Format("2006-01-02")])
+
+	var style string
+	switch {
+	case isSelected:
+		style = "\x1b[48;5;220m\x1b[38;5;16m"
+	case level > 0:
+		fg := 15
+		if level > 2 {
+			fg = 16
+		}
+		style = fmt.Sprintf("\x1b[48;5;%dm\x1b[38;5;%dm", yearHeatColors[level], fg)
+	}
+	if isToday {
+		style += "\x1b[1;4m"
+	}
+	if style == "" {
+		return text
+	}
+	return style + text + "\x1b[0m"
+}
+
+// legend explains the shading
+func (yv *YearView) legend() string {
+	legend := "Booked:"
+	for level, label := range []string{"none", "≤2h", "≤4h", "≤6h", ">6h"} {
+		swatch := "  "
+		if level > 0 {
+			swatch = fmt.Sprintf("\x1b[48;5;%dm  \x1b[0m", yearHeatColors[level])
+		}
+		legend += " " + swatch + " " + label
+	}
+	return legend
+}
+
+// yearHeatLevel buckets booked hours into the shades of yearHeatColors
+func yearHeatLevel(hours float64) int {
+	switch {
+	case hours <= 0:
+		return 0
+	case hours <= 2:
+		return 1
+	case hours <= 4:
+		return 2
+	case hours <= 6:
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// agendaEvent creates an hour-long event at the given hour of a day of June 2030 for agenda tests
+func agendaEvent(name string, day, hour int) *calendar.Event {
+	start := time.Date(2030, 6, day, hour, 0, 0, 0, time.Local)
+	return calendar.NewEvent(name, "", "", start, 1.0, 0, 1, 0)
+}
+
+func TestGroupEventsByDay(t *testing.T) {
+	events := []*calendar.Event{
+		agendaEvent("Before", 2, 23),
+		agendaEvent("Standup", 3, 9),
+		agendaEvent("Lunch", 3, 12),
+		agendaEvent("Review", 5, 15),
+		agendaEvent("Late", 5, 23),
+		agendaEvent("After", 10, 9),
+	}
+
+	tests := []struct {
+		name      string
+		days      int
+		skipEmpty bool
+		want      map[int][]string // day of June -> event names, for every listed day
+		order     []int
+	}{
+		{
+			name:  "range includes empty days",
+			days:  4,
+			want:  map[int][]string{3: {"Standup", "Lunch"}, 4: nil, 5: {"Review", "Late"}, 6: nil},
+			order: []int{3, 4, 5, 6},
+		},
+		{
+			name:      "range skips empty days",
+			days:      4,
+			skipEmpty: true,
+			want:      map[int][]string{3: {"Standup", "Lunch"}, 5: {"Review", "Late"}},
+			order:     []int{3, 5},
+		},
+		{
+			name:  "single day",
+			days:  1,
+			want:  map[int][]string{3: {"Standup", "Lunch"}},
+			order: []int{3},
+		},
+		{
+			name:  "search results list only days with events",
+			days:  0,
+			want:  map[int][]string{2: {"Before"}, 3: {"Standup", "Lunch"}, 5: {"Review", "Late"}, 10: {"After"}},
+			order: []int{2, 3, 5, 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := events
+			if tt.days > 0 {
+				// Ranges are loaded from the database without the events outside them
+				input = events[1:5]
+			}
+			agenda := calendar.GroupEventsByDay(input, layoutDay(3), tt.days, tt.skipEmpty)
+
+			if len(agenda) != len(tt.order) {
+				t.Fatalf("Expected %d days, got %d", len(tt.order), len(agenda))
+			}
+			for i, day := range agenda {
+				if day.Date.Day() != tt.order[i] || day.Date.Hour() != 0 {
+					t.Errorf("Day %d: expected midnight on June %d, got %s", i, tt.order[i], day.Date.Format("Jan 2 15:04"))
+				}
+				want := tt.want[tt.order[i]]
+				if len(day.Events) != len(want) {
+					t.Errorf("June %d: expected %d events, got %d", tt.order[i], len(want), len(day.Events))
+					continue
+				}
+				for j, event := range day.Events {
+					if event.Name != want[j] {
+						t.Errorf("June %d event %d: expected %s, got %s", tt.order[i], j, want[j], event.Name)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/samuelstranges/chronos/internal/api"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+)
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *api.Error      `json:"error"`
+}
+
+// setupTestAPI creates an API server backed by an in-memory database
+func setupTestAPI(t *testing.T) (*httptest.Server, *eventmanager.EventManager) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.CloseDatabase() })
+
+	em := eventmanager.NewEventManager(db)
+	srv := httptest.NewServer(api.NewServer(em).Handler())
+	t.Cleanup(srv.Close)
+
+	return srv, em
+}
+
+// callRPC sends a single JSON-RPC request and decodes the response
+func callRPC(t *testing.T, client *http.Client, url, method, params string) rpcResponse {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `"`
+	if params != "" {
+		body += `,"params":` + params
+	}
+	body += `}`
+
+	resp, err := client.Post(url+api.RPCPath, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s failed: %v", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode %s response: %v", method, err)
+	}
+	return result
+}
+
+func TestAPIEventLifecycle(t *testing.T) {
+	srv, em := setupTestAPI(t)
+	client := srv.Client()
+
+	// Add a recurring event
+	resp := callRPC(t, client, srv.URL, "events.add", `{"event":{"name":"Gym","start":"2030-03-04T07:00:00Z","duration_hours":1,"frequency_days":7,"occurrences":3,"color":"Green"}}`)
+	if resp.Error != nil {
+		t.Fatalf("events.add failed: %v", resp.Error)
+	}
+	var added struct{ Events []api.Event }
+	json.Unmarshal(resp.Result, &added)
+	if len(added.Events) != 3 {
+		t.Fatalf("Expected 3 occurrences, got %d", len(added.Events))
+	}
+
+	// List returns them in range
+	resp = callRPC(t, client, srv.URL, "events.list", `{"from":"2030-03-01","to":"2030-03-20"}`)
+	var listed []api.Event
+	json.Unmarshal(resp.Result, &listed)
+	if len(listed) != 3 || listed[0].Color != "Green" || listed[0].DurationHours != 1 {
+		t.Fatalf("Unexpected list result: %s", resp.Result)
+	}
+
+	// Partial update keeps unspecified fields
+	id := added.Events[0].ID
+	resp = callRPC(t, client, srv.URL, "events.update", `{"id":`+strconv.Itoa(id)+`,"event":{"location":"Pool"}}`)
+	if resp.Error != nil {
+		t.Fatalf("events.update failed: %v", resp.Error)
+	}
+	event, _ := em.GetEventById(id)
+	if event.Location != "Pool" || event.Name != "Gym" || event.DurationHour != 1 {
+		t.Errorf("Partial update changed other fields: %+v", event)
+	}
+
+	// Search finds by location
+	resp = callRPC(t, client, srv.URL, "events.search", `{"query":"pool"}`)
+	var found []api.Event
+	json.Unmarshal(resp.Result, &found)
+	if len(found) != 1 || found[0].ID != id {
+		t.Errorf("Expected search to find event %d, got %s", id, resp.Result)
+	}
+
+	// Delete then undo restores the event
+	if resp = callRPC(t, client, srv.URL, "events.delete", `{"id":`+strconv.Itoa(id)+`}`); resp.Error != nil {
+		t.Fatalf("events.delete failed: %v", resp.Error)
+	}
+	if resp = callRPC(t, client, srv.URL, "undo", ""); resp.Error != nil {
+		t.Fatalf("undo failed: %v", resp.Error)
+	}
+	events, _ := em.GetAllEvents()
+	if len(events) != 3 {
+		t.Errorf("Expected 3 events after undoing delete, got %d", len(events))
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	srv, em := setupTestAPI(t)
+	client := srv.Client()
+
+	tests := []struct {
+		name   string
+		method string
+		params string
+		code   int
+	}{
+		{"unknown method", "events.explode", "", -32601},
+		{"missing name", "events.add", `{"event":{"start":"2030-01-01T10:00:00Z","duration_hours":1}}`, -32602},
+		{"unknown field", "events.add", `{"event":{"name":"x","start":"2030-01-01T10:00:00Z","duration_hours":1,"colour":"Red"}}`, -32602},
+		{"bad color", "events.add", `{"event":{"name":"x","start":"2030-01-01T10:00:00Z","duration_hours":1,"color":"Mauve"}}`, -32602},
+		{"duration off the half hour", "events.add", `{"event":{"name":"x","start":"2030-01-01T10:00:00Z","duration_hours":1.25}}`, -32602},
+		{"end off the half hour", "events.add", `{"event":{"name":"x","start":"2030-01-01T10:00:00Z","end":"2030-01-01T11:15:00Z"}}`, -32602},
+		{"missing event", "events.delete", `{"id":999}`, api.CodeNotFound},
+		{"empty undo", "undo", "", api.CodeNothingToUndo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := callRPC(t, client, srv.URL, tt.method, tt.params)
+			if resp.Error == nil || resp.Error.Code != tt.code {
+				t.Errorf("Expected error code %d, got %+v", tt.code, resp.Error)
+			}
+		})
+	}
+
+	// Overlapping events are rejected by the EventManager
+	callRPC(t, client, srv.URL, "events.add", `{"event":{"name":"A","start":"2030-01-01T10:00:00Z","duration_hours":2}}`)
+	resp := callRPC(t, client, srv.URL, "events.add", `{"event":{"name":"B","start":"2030-01-01T11:00:00Z","duration_hours":1}}`)
+	if resp.Error == nil || resp.Error.Code != api.CodeRejected {
+		t.Errorf("Expected overlap to be rejected, got %+v", resp.Error)
+	}
+
+	// A series with a rejected occurrence adds none of them, so a retry cannot duplicate any
+	resp = callRPC(t, client, srv.URL, "events.add", `{"event":{"name":"C","start":"2029-12-30T10:00:00Z","duration_hours":1,"frequency_days":1,"occurrences":4}}`)
+	if resp.Error == nil || resp.Error.Code != api.CodeRejected {
+		t.Errorf("Expected the series to be rejected, got %+v", resp.Error)
+	}
+	if events, _ := em.GetAllEvents(); len(events) != 1 {
+		t.Errorf("Expected only the first event to be stored, got %d events", len(events))
+	}
+}
+
+func TestAPIUnixSocket(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.CloseDatabase()
+
+	socket := filepath.Join(t.TempDir(), "chronos.sock")
+	server := api.NewServer(eventmanager.NewEventManager(db))
+	if err := server.ListenUnix(socket); err != nil {
+		t.Fatalf("ListenUnix failed: %v", err)
+	}
+	defer server.Close()
+
+	// A second instance must not steal a live socket
+	if err := api.NewServer(eventmanager.NewEventManager(db)).ListenUnix(socket); err == nil {
+		t.Error("Expected second listener on a live socket to fail")
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp := callRPC(t, client, "http://chronos", "methods", "")
+	if resp.Error != nil || !strings.Contains(string(resp.Result), "events.add") {
+		t.Errorf("Unexpected methods result over socket: %s %+v", resp.Result, resp.Error)
+	}
+}
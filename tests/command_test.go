@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/command"
+)
+
+func TestFindCommand(t *testing.T) {
+	for _, name := range []string{"goto", "g", "del", "se", "v", "mark", "marks"} {
+		cmd, err := command.Find(name)
+		if err != nil {
+			t.Errorf("Find(%q): unexpected error %v", name, err)
+			continue
+		}
+		if !strings.HasPrefix(cmd.Name, name) {
+			t.Errorf("Find(%q): expected a command starting with it, got %s", name, cmd.Name)
+		}
+	}
+
+	if _, err := command.Find("launch"); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("Expected an unknown command error, got %v", err)
+	}
+
+	name, args := command.Split("  Goto 2026-11-03   14:00 ")
+	if name != "goto" || !reflect.DeepEqual(args, []string{"2026-11-03", "14:00"}) {
+		t.Errorf("Expected goto with a date and a time, got %q %q", name, args)
+	}
+}
+
+func TestParseGoto(t *testing.T) {
+	current := time.Date(2026, 10, 18, 9, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		args []string
+		want time.Time
+	}{
+		{[]string{"2026-11-03", "14:00"}, time.Date(2026, 11, 3, 14, 0, 0, 0, time.Local)},
+		{[]string{"20261103"}, time.Date(2026, 11, 3, 9, 30, 0, 0, time.Local)},
+		{[]string{"16:30"}, time.Date(2026, 10, 18, 16, 30, 0, 0, time.Local)},
+		{[]string{"14.5"}, time.Date(2026, 10, 18, 14, 30, 0, 0, time.Local)},
+		{[]string{"2026-12-24", "8"}, time.Date(2026, 12, 24, 8, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := command.ParseGoto(tt.args, current)
+		if err != nil {
+			t.Errorf("ParseGoto(%q): unexpected error %v", tt.args, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseGoto(%q): expected %s, got %s", tt.args, tt.want, got)
+		}
+	}
+
+	// today keeps the selected time of day
+	got, err := command.ParseGoto([]string{"today"}, current)
+	if now := time.Now(); err != nil || got.Day() != now.Day() || got.Hour() != 9 || got.Minute() != 30 {
+		t.Errorf("Expected today at 09:30, got %s (%v)", got, err)
+	}
+
+	for _, args := range [][]string{nil, {"2026-02-30"}, {"14:15"}, {"soon", "14:00"}, {"2026-11-03", "25:00"}, {"a", "b", "c"}} {
+		if _, err := command.ParseGoto(args, current); err == nil {
+			t.Errorf("ParseGoto(%q): expected an error", args)
+		}
+	}
+}
+
+func TestParseSetting(t *testing.T) {
+	tests := []struct {
+		args  []string
+		name  string
+		value string
+	}{
+		{[]string{"week_start=monday"}, "week_start", "monday"},
+		{[]string{"week_start", "Tue"}, "week_start", "tuesday"},
+		{[]string{"overlap_policy=warn"}, "overlap_policy", "warn"},
+		{[]string{"default_color=R"}, "default_color", "red"},
+		{[]string{"default_color=auto"}, "default_color", "auto"},
+		{[]string{"default_event_length=1.5"}, "default_event_length", "1.5"},
+		{[]string{"agenda_skip_empty_days=on"}, "agenda_skip_empty_days", "true"},
+		{[]string{"detail_pane"}, "detail_pane", ""},
+	}
+	for _, tt := range tests {
+		setting, value, err := command.ParseSetting(tt.args)
+		if err != nil {
+			t.Errorf("ParseSetting(%q): unexpected error %v", tt.args, err)
+			continue
+		}
+		if setting.Name != tt.name || value != tt.value {
+			t.Errorf("ParseSetting(%q): expected %s=%s, got %s=%s", tt.args, tt.name, tt.value, setting.Name, value)
+		}
+	}
+
+	errors := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"colour=red"}, "unknown option"},
+		{[]string{"week_start=mo"}, "invalid value"},
+		{[]string{"overlap_policy=sometimes"}, "forbid, warn, allow"},
+		{[]string{"default_event_length=0.7"}, "invalid value"},
+		{[]string{"week_start=monday", "tuesday"}, "usage"},
+	}
+	for _, tt := range errors {
+		if _, _, err := command.ParseSetting(tt.args); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseSetting(%q): expected an error containing %q, got %v", tt.args, tt.want, err)
+		}
+	}
+}
+
+func TestCompleteCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"go", []string{"goto"}},
+		{"se", []string{"set", "search.start", "search.next", "search.prev", "search.clear", "search.find"}},
+		{"event.ed", []string{"event.edit", "event.edit_description", "event.edit_document"}},
+		{"view m", []string{"view month"}},
+		{"v w", []string{"v week", "v weeks"}},
+		{"color ", []string{"color red", "color green", "color yellow", "color blue", "color magenta", "color cyan", "color white"}},
+		{"export ", []string{"export ics"}},
+		{"set week", []string{"set week_start="}},
+		{"set overlap_policy=", []string{"set overlap_policy=forbid", "set overlap_policy=warn", "set overlap_policy=allow"}},
+		{"set week_start=mo", []string{"set week_start=monday"}},
+		{"view month ", nil},
+		{"launch ", nil},
+	}
+
+	for _, tt := range tests {
+		if got := command.Complete(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q): expected %q, got %q", tt.line, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+)
+
+// conflictTime returns a time on the fixed day used by the conflict tests
+func conflictTime(hour, minute int) time.Time {
+	return time.Date(2030, 6, 3, hour, minute, 0, 0, time.Local)
+}
+
+// setupConflictDay adds Focus 09:00-12:00, Lunch 12:00-13:00 and Review 13:30-14:30
+func setupConflictDay(t *testing.T, em *eventmanager.EventManager) []*calendar.Event {
+	var added []*calendar.Event
+	for _, event := range []*calendar.Event{
+		calendar.NewEvent("Focus", "", "", conflictTime(9, 0), 3.0, 0, 1, 0),
+		calendar.NewEvent("Lunch", "", "", conflictTime(12, 0), 1.0, 0, 1, 0),
+		calendar.NewEvent("Review", "", "", conflictTime(13, 30), 1.0, 0, 1, 0),
+	} {
+		result, ok := em.AddEvent(*event)
+		if !ok {
+			t.Fatalf("Failed to add %s", event.Name)
+		}
+		added = append(added, result)
+	}
+	return added
+}
+
+func TestConflictResolutionPlans(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	defer db.CloseDatabase()
+	setupConflictDay(t, em)
+
+	talk := calendar.NewEvent("Talk", "", "", conflictTime(10, 0), 1.0, 0, 1, 0)
+	late := calendar.NewEvent("Late", "", "", conflictTime(11, 0), 2.0, 0, 1, 0)
+	standup := calendar.NewEvent("Standup", "", "", conflictTime(8, 0), 2.0, 0, 1, 0)
+
+	tests := []struct {
+		name       string
+		event      *calendar.Event
+		resolution eventmanager.Resolution
+		start      time.Time
+		duration   float64
+		moved      []time.Time // new start times of pushed events, in order
+		fails      bool
+	}{
+		{"shift skips busy slots", talk, eventmanager.ResolveShift, conflictTime(14, 30), 1.0, nil, false},
+		{"shift keeps length", late, eventmanager.ResolveShift, conflictTime(14, 30), 2.0, nil, false},
+		{"shorten to next event", standup, eventmanager.ResolveShorten, conflictTime(8, 0), 1.0, nil, false},
+		{"shorten with busy start", talk, eventmanager.ResolveShorten, time.Time{}, 0, nil, true},
+		{"push cascades", talk, eventmanager.ResolvePush, conflictTime(10, 0), 1.0,
+			[]time.Time{conflictTime(11, 0), conflictTime(14, 0), conflictTime(15, 0)}, false},
+		{"push past a longer event", standup, eventmanager.ResolvePush, conflictTime(8, 0), 2.0,
+			[]time.Time{conflictTime(10, 0), conflictTime(13, 0), conflictTime(14, 0)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := em.PlanConflictResolution(tt.event, 0, tt.resolution)
+			if tt.fails {
+				if err == nil {
+					t.Fatalf("Expected %s to be impossible, got %+v", tt.resolution, plan.Event)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlanConflictResolution failed: %v", err)
+			}
+			if !plan.Event.Time.Equal(tt.start) || plan.Event.DurationHour != tt.duration {
+				t.Errorf("Expected %s for %.1fh, got %s for %.1fh", tt.start.Format("15:04"), tt.duration,
+					plan.Event.Time.Format("15:04"), plan.Event.DurationHour)
+			}
+			if len(plan.Moved) != len(tt.moved) {
+				t.Fatalf("Expected %d moved events, got %d", len(tt.moved), len(plan.Moved))
+			}
+			for i, start := range tt.moved {
+				if !plan.Moved[i].Time.Equal(start) {
+					t.Errorf("Moved event %s: expected %s, got %s", plan.Moved[i].Name, start.Format("15:04"), plan.Moved[i].Time.Format("15:04"))
+				}
+			}
+		})
+	}
+
+	// Planning changes nothing
+	events, _ := em.GetAllEvents()
+	if len(events) != 3 {
+		t.Errorf("Expected planning to leave 3 events, got %d", len(events))
+	}
+}
+
+func TestApplyConflictPlanUndoRedo(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	defer db.CloseDatabase()
+	added := setupConflictDay(t, em)
+
+	// Edit Review to clash with Lunch and push Lunch later
+	review := *added[2]
+	review.Time = conflictTime(12, 0)
+	plan, err := em.PlanConflictResolution(&review, review.Id, eventmanager.ResolvePush)
+	if err != nil {
+		t.Fatalf("PlanConflictResolution failed: %v", err)
+	}
+	if !em.ApplyConflictPlan(plan, review.Id) {
+		t.Fatalf("ApplyConflictPlan failed")
+	}
+
+	lunch, _ := em.GetEventById(added[1].Id)
+	if !lunch.Time.In(time.Local).Equal(conflictTime(13, 0)) {
+		t.Errorf("Expected Lunch pushed to 13:00, got %s", lunch.Time.In(time.Local).Format("15:04"))
+	}
+	if desc := em.GetUndoDescription(); desc != "Undo resolve conflict: Review" {
+		t.Errorf("Unexpected undo description %q", desc)
+	}
+
+	// One undo restores both events
+	if err := em.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	lunch, _ = em.GetEventById(added[1].Id)
+	restored, _ := em.GetEventById(review.Id)
+	if !lunch.Time.In(time.Local).Equal(conflictTime(12, 0)) || !restored.Time.In(time.Local).Equal(conflictTime(13, 30)) {
+		t.Errorf("Undo did not restore both events: Lunch %s, Review %s",
+			lunch.Time.In(time.Local).Format("15:04"), restored.Time.In(time.Local).Format("15:04"))
+	}
+
+	// Redo re-applies the whole resolution
+	if err := em.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	lunch, _ = em.GetEventById(added[1].Id)
+	if !lunch.Time.In(time.Local).Equal(conflictTime(13, 0)) {
+		t.Errorf("Expected redo to push Lunch to 13:00 again, got %s", lunch.Time.In(time.Local).Format("15:04"))
+	}
+
+	// Adding a new event through a plan is also a single undo step
+	talk := calendar.NewEvent("Talk", "", "", conflictTime(10, 0), 1.0, 0, 1, 0)
+	plan, err = em.PlanConflictResolution(talk, 0, eventmanager.ResolveShift)
+	if err != nil || !em.ApplyConflictPlan(plan, 0) {
+		t.Fatalf("Failed to apply shift plan: %v", err)
+	}
+	events, _ := em.GetAllEvents()
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events after shift, got %d", len(events))
+	}
+	em.Undo()
+	events, _ = em.GetAllEvents()
+	if len(events) != 3 {
+		t.Errorf("Expected undo to remove the shifted event, got %d events", len(events))
+	}
+}
+
+func TestSeriesConflictResolution(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	defer db.CloseDatabase()
+	setupConflictDay(t, em)
+	if _, ok := em.AddEvent(*calendar.NewEvent("Gym", "", "", conflictTime(10, 0).AddDate(0, 0, 1), 1.0, 0, 1, 0)); !ok {
+		t.Fatalf("Failed to add Gym")
+	}
+
+	// Daily at 10:00 for three days: the first two overlap Focus and Gym
+	series := calendar.NewEvent("Talk", "", "", conflictTime(10, 0), 1.0, 1, 3, 0).GetReccuringEvents()
+
+	tests := []struct {
+		name       string
+		resolution eventmanager.Resolution
+		starts     []time.Time // the occurrences after resolution
+		resolved   int
+		moved      int
+	}{
+		{"shift each occurrence", eventmanager.ResolveShift,
+			[]time.Time{conflictTime(14, 30), conflictTime(11, 0).AddDate(0, 0, 1), conflictTime(10, 0).AddDate(0, 0, 2)}, 2, 0},
+		{"push for each occurrence", eventmanager.ResolvePush,
+			[]time.Time{conflictTime(10, 0), conflictTime(10, 0).AddDate(0, 0, 1), conflictTime(10, 0).AddDate(0, 0, 2)}, 2, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := em.PlanSeriesResolution(series, tt.resolution)
+			if err != nil {
+				t.Fatalf("PlanSeriesResolution failed: %v", err)
+			}
+			if len(plan.Series) != len(tt.starts) || plan.Resolved != tt.resolved || len(plan.Moved) != tt.moved {
+				t.Fatalf("Expected %d occurrences, %d resolved and %d moved, got %d, %d and %d",
+					len(tt.starts), tt.resolved, tt.moved, len(plan.Series), plan.Resolved, len(plan.Moved))
+			}
+			for i, start := range tt.starts {
+				if !plan.Series[i].Time.Equal(start) || plan.Series[i].Id != 0 {
+					t.Errorf("Occurrence %d: expected %s, got %s (id %d)", i, start.Format("Jan 2 15:04"),
+						plan.Series[i].Time.Format("Jan 2 15:04"), plan.Series[i].Id)
+				}
+			}
+			if !plan.Event.Time.Equal(tt.starts[0]) {
+				t.Errorf("Expected the plan to follow the first occurrence resolved, got %s", plan.Event.Time.Format("Jan 2 15:04"))
+			}
+		})
+	}
+
+	// Shortening is impossible when an occurrence starts during another event
+	if _, err := em.PlanSeriesResolution(series, eventmanager.ResolveShorten); err == nil {
+		t.Error("Expected shortening to be impossible")
+	}
+
+	// The whole series is added as one undo step
+	plan, err := em.PlanSeriesResolution(series, eventmanager.ResolveShift)
+	if err != nil || !em.ApplyConflictPlan(plan, 0) {
+		t.Fatalf("Failed to apply series plan: %v", err)
+	}
+	events, _ := em.GetAllEvents()
+	if len(events) != 7 {
+		t.Fatalf("Expected 7 events after adding the series, got %d", len(events))
+	}
+	em.Undo()
+	events, _ = em.GetAllEvents()
+	if len(events) != 4 {
+		t.Errorf("Expected undo to remove the whole series, got %d events", len(events))
+	}
+}
+
+func TestSeriesConflictsWithItself(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	defer db.CloseDatabase()
+	if _, ok := em.AddEvent(*calendar.NewEvent("Trip", "", "", conflictTime(9, 0), 24.0, 0, 1, 0)); !ok {
+		t.Fatalf("Failed to add Trip")
+	}
+
+	// Shifting the first occurrence past the trip lands it on the second, which moves on in turn
+	series := calendar.NewEvent("Talk", "", "", conflictTime(9, 0), 1.0, 1, 2, 0).GetReccuringEvents()
+	plan, err := em.PlanSeriesResolution(series, eventmanager.ResolveShift)
+	if err != nil {
+		t.Fatalf("PlanSeriesResolution failed: %v", err)
+	}
+	want := []time.Time{conflictTime(9, 0).AddDate(0, 0, 1), conflictTime(10, 0).AddDate(0, 0, 1)}
+	for i, start := range want {
+		if !plan.Series[i].Time.Equal(start) {
+			t.Errorf("Occurrence %d: expected %s, got %s", i, start.Format("Jan 2 15:04"), plan.Series[i].Time.Format("Jan 2 15:04"))
+		}
+	}
+
+	// Pushing for the second occurrence moves the first one out of its way
+	plan, err = em.PlanSeriesResolution(series, eventmanager.ResolvePush)
+	if err != nil {
+		t.Fatalf("PlanSeriesResolution failed: %v", err)
+	}
+	if len(plan.Moved) != 1 || !plan.Series[0].Time.Equal(conflictTime(9, 0)) || !plan.Series[1].Time.Equal(want[0]) {
+		t.Errorf("Expected Trip pushed and the occurrences kept, got %d moved and %s, %s", len(plan.Moved),
+			plan.Series[0].Time.Format("Jan 2 15:04"), plan.Series[1].Time.Format("Jan 2 15:04"))
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/utils"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"fits on one line", "Bring laptops", 20, []string{"Bring laptops"}},
+		{"wraps at word boundaries", "Bring laptops and chargers", 14, []string{"Bring laptops", "and chargers"}},
+		{"keeps line breaks and blank lines", "Agenda:\n\nNotes", 20, []string{"Agenda:", "", "Notes"}},
+		{"splits words longer than a line", "see https://example.com/x", 10, []string{"see", "https://ex", "ample.com/", "x"}},
+		{"word of exactly two lines", "abcdefgh end", 4, []string{"abcd", "efgh", "end"}},
+		{"collapses repeated spaces", "a    b", 10, []string{"a b"}},
+		{"empty text", "", 10, []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utils.WrapText(tt.text, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecurrenceSummary(t *testing.T) {
+	start := time.Date(2030, 6, 3, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		frequency, occurrence int
+		want                  string
+	}{
+		{0, 1, ""},
+		{7, 1, ""},
+		{1, 5, "Daily, 5 times"},
+		{7, 4, "Weekly, 4 times"},
+		{-1, 10, "Weekdays, 10 times"},
+		{14, 3, "Every 14 days, 3 times"},
+	}
+
+	for _, tt := range tests {
+		event := calendar.NewEvent("Standup", "", "", start, 0.5, tt.frequency, tt.occurrence, 0)
+		if got := event.RecurrenceSummary(); got != tt.want {
+			t.Errorf("Frequency %d, occurrence %d: expected %q, got %q", tt.frequency, tt.occurrence, tt.want, got)
+		}
+	}
+}
+
+func TestGetDetailPanePosition(t *testing.T) {
+	for value, want := range map[string]string{"": "right", "right": "right", "bottom": "bottom", "left": "right"} {
+		if got := config.GetDetailPanePosition(&config.Config{DetailPane: value}); got != want {
+			t.Errorf("GetDetailPanePosition(%q): expected %s, got %s", value, want, got)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/jroimartin/gocui"
+)
+
+// documentEvent creates a weekly event with a multi-line description for event document tests
+func documentEvent() *calendar.Event {
+	start := time.Date(2030, 6, 3, 9, 30, 0, 0, time.Local)
+	event := calendar.NewEvent("Team sync", "Agenda:\n\n  - roadmap\nhttps://example.com/notes", "Room 4", start, 1.5, 7, 4, gocui.ColorBlue)
+	event.Id = 12
+	return event
+}
+
+func TestEventDocumentRoundTrip(t *testing.T) {
+	event := documentEvent()
+
+	document := calendar.FormatEventDocument(event)
+	if !strings.HasPrefix(document, "---\nname: Team sync\ndate: 2030-06-03\ntime: 09:30\n") {
+		t.Errorf("Unexpected front matter:\n%s", document)
+	}
+
+	parsed, err := calendar.ParseEventDocument(document, event)
+	if err != nil {
+		t.Fatalf("Failed to parse formatted document: %v", err)
+	}
+	if *parsed != *event {
+		t.Errorf("Expected %+v, got %+v", *event, *parsed)
+	}
+}
+
+func TestEventEqual(t *testing.T) {
+	event := documentEvent()
+
+	// The same instant in another location, or with a monotonic clock reading, is unchanged
+	same := *event
+	same.Time = event.Time.In(time.UTC)
+	if !same.Equal(event) {
+		t.Error("Expected an event at the same instant in UTC to be equal")
+	}
+	now := *event
+	now.Time = time.Now()
+	unchanged := now
+	unchanged.Time = now.Time.Round(0)
+	if !unchanged.Equal(&now) {
+		t.Error("Expected an event without the monotonic reading to be equal")
+	}
+
+	later := *event
+	later.Time = event.Time.Add(30 * time.Minute)
+	renamed := *event
+	renamed.Name = "Planning"
+	for _, changed := range []calendar.Event{later, renamed} {
+		if changed.Equal(event) {
+			t.Errorf("Expected %+v to differ from %+v", changed, *event)
+		}
+	}
+}
+
+func TestParseEventDocumentEdits(t *testing.T) {
+	event := documentEvent()
+	document := "\n---\nName: Planning\ntime: 14:00\n# comments and blank lines are ignored\n\ncolour: green\n---\n\nNew notes\n  indented\n\n"
+
+	parsed, err := calendar.ParseEventDocument(document, event)
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+
+	if parsed.Name != "Planning" || parsed.Color != gocui.ColorGreen {
+		t.Errorf("Expected Planning in green, got %s in %s", parsed.Name, calendar.ColorAttributeToName(parsed.Color))
+	}
+	if want := time.Date(2030, 6, 3, 14, 0, 0, 0, time.Local); !parsed.Time.Equal(want) {
+		t.Errorf("Expected %s, got %s", want, parsed.Time)
+	}
+	if parsed.Description != "New notes\n  indented" {
+		t.Errorf("Expected trimmed description, got %q", parsed.Description)
+	}
+	// Fields left out keep their values
+	if parsed.Id != 12 || parsed.Location != "Room 4" || parsed.DurationHour != 1.5 || parsed.FrequencyDay != 7 {
+		t.Errorf("Expected unchanged id, location, duration and frequency, got %+v", *parsed)
+	}
+	if event.Name != "Team sync" {
+		t.Errorf("Expected the original event to be unchanged, got %s", event.Name)
+	}
+
+	// An empty colour picks the one generated from the name
+	parsed, err = calendar.ParseEventDocument("---\ncolor:\n---\n", event)
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+	if want := calendar.GenerateColorFromName("Team sync"); parsed.Color != want {
+		t.Errorf("Expected generated colour %s, got %s", calendar.ColorAttributeToName(want), calendar.ColorAttributeToName(parsed.Color))
+	}
+}
+
+func TestParseEventDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{"missing front matter", "Just notes", "must start"},
+		{"unclosed front matter", "---\nname: Sync\n", "not closed"},
+		{"empty name", "---\nname:\n---\n", "name cannot be empty"},
+		{"bad date", "---\ndate: 2030-02-30\n---\n", "invalid date"},
+		{"off-grid time", "---\ntime: 09:15\n---\n", "invalid time"},
+		{"bad duration", "---\nduration: 0.7\n---\n", "invalid duration"},
+		{"unknown colour", "---\ncolor: teal\n---\n", "unknown color"},
+		{"unknown field", "---\nroom: 4\n---\n", "unknown field"},
+		{"not a field", "---\nname Sync\n---\n", "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calendar.ParseEventDocument(tt.document, documentEvent())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -1270,4 +1270,95 @@ func TestGetReccuringEventsWeekdayStartingSaturday(t *testing.T) {
 			t.Errorf("Event %d is not on a weekday: %s", i, event.Time.Format("Monday"))
 		}
 	}
-}
\ No newline at end of file
+}
+
+// TestFindOverlappingEvents tests overlap detection including events that start the previous day
+func TestFindOverlappingEvents(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	defer db.CloseDatabase()
+
+	lateNight := calendar.NewEvent("Late Night", "", "", time.Date(2030, 5, 1, 22, 0, 0, 0, time.Local), 4.0, 0, 1, 0)
+	morning := calendar.NewEvent("Morning", "", "", time.Date(2030, 5, 2, 9, 0, 0, 0, time.Local), 1.0, 0, 1, 0)
+	added, _ := em.AddEvent(*lateNight)
+	em.AddEvent(*morning)
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		duration float64
+		exclude  []int
+		expected int
+	}{
+		{"spills over midnight", time.Date(2030, 5, 2, 1, 0, 0, 0, time.Local), 1.0, nil, 1},
+		{"adjacent is allowed", time.Date(2030, 5, 2, 10, 0, 0, 0, time.Local), 1.0, nil, 0},
+		{"inside morning", time.Date(2030, 5, 2, 9, 30, 0, 0, time.Local), 0.5, nil, 1},
+		{"spans both", time.Date(2030, 5, 2, 0, 0, 0, 0, time.Local), 12.0, nil, 2},
+		{"excluded", time.Date(2030, 5, 2, 1, 0, 0, 0, time.Local), 1.0, []int{added.Id}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := calendar.NewEvent("Probe", "", "", tt.start, tt.duration, 0, 1, 0)
+			overlapping, err := em.FindOverlappingEvents(event, tt.exclude...)
+			if err != nil {
+				t.Fatalf("FindOverlappingEvents failed: %v", err)
+			}
+			if len(overlapping) != tt.expected {
+				t.Errorf("Expected %d overlapping events, got %d", tt.expected, len(overlapping))
+			}
+		})
+	}
+}
+
+// TestOverlapPolicy tests that forbid refuses overlaps while warn and allow save them
+func TestOverlapPolicy(t *testing.T) {
+	tests := []struct {
+		policy  eventmanager.OverlapPolicy
+		saved   bool
+		warning bool
+	}{
+		{eventmanager.OverlapForbid, false, false},
+		{eventmanager.OverlapWarn, true, true},
+		{eventmanager.OverlapAllow, true, false},
+		{"bogus", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			em, db := setupTestEventManager(t)
+			defer db.CloseDatabase()
+			em.SetOverlapPolicy(tt.policy)
+
+			focus := calendar.NewEvent("Focus", "", "", time.Date(2030, 6, 3, 9, 0, 0, 0, time.Local), 3.0, 0, 1, 0)
+			talk := calendar.NewEvent("Talk", "", "", time.Date(2030, 6, 3, 10, 0, 0, 0, time.Local), 1.0, 0, 1, 0)
+			em.AddEvent(*focus)
+
+			var saved bool
+			title, _ := em.CaptureErrors(func() {
+				_, saved = em.AddEvent(*talk)
+			})
+			if saved != tt.saved {
+				t.Errorf("Expected saved=%v, got %v", tt.saved, saved)
+			}
+			if warned := title == "Overlapping Event"; warned != tt.warning {
+				t.Errorf("Expected warning=%v, got title %q", tt.warning, title)
+			}
+
+			// Moving an event onto another follows the same policy
+			lunch := calendar.NewEvent("Lunch", "", "", time.Date(2030, 6, 3, 13, 0, 0, 0, time.Local), 1.0, 0, 1, 0)
+			added, ok := em.AddEvent(*lunch)
+			if !ok {
+				t.Fatalf("Failed to add non-overlapping event")
+			}
+			moved := *added
+			moved.Time = time.Date(2030, 6, 3, 11, 30, 0, 0, time.Local)
+			var updated bool
+			em.CaptureErrors(func() {
+				updated = em.UpdateEvent(added.Id, &moved)
+			})
+			if updated != tt.saved {
+				t.Errorf("Expected update saved=%v, got %v", tt.saved, updated)
+			}
+		})
+	}
+}
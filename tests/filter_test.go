@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/jroimartin/gocui"
+)
+
+func TestFilterMatches(t *testing.T) {
+	// Wednesday October 14 2026
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	event := func(name, description string, color gocui.Attribute, day, hour int) *calendar.Event {
+		return &calendar.Event{Name: name, Description: description, Color: color,
+			Time: time.Date(2026, 10, day, hour, 0, 0, 0, time.Local), DurationHour: 1, Occurence: 1}
+	}
+	standup := event("Standup #work", "", gocui.ColorBlue, 14, 9)
+	review := event("Code review", "Bring notes #Work.", gocui.ColorGreen, 19, 14)
+	dentist := event("Dentist", "#workshop next door", gocui.ColorBlue, 11, 23)
+
+	tests := []struct {
+		filter calendar.Filter
+		want   []*calendar.Event
+	}{
+		{calendar.Filter{Text: "REVIEW"}, []*calendar.Event{review}},
+		{calendar.Filter{Text: "notes"}, []*calendar.Event{review}},
+		{calendar.Filter{Color: "blue"}, []*calendar.Event{standup, dentist}},
+		// Tags are whole words, with or without the #
+		{calendar.Filter{Tag: "work"}, []*calendar.Event{standup, review}},
+		{calendar.Filter{Tag: "#workshop"}, []*calendar.Event{dentist}},
+		{calendar.Filter{Tag: "work", Color: "Blue"}, []*calendar.Event{standup}},
+		// Windows include the whole of their last day
+		{calendar.Filter{From: "t"}, []*calendar.Event{standup, review}},
+		{calendar.Filter{To: "20261011"}, []*calendar.Event{dentist}},
+		{calendar.Filter{From: "w", To: "w"}, []*calendar.Event{standup}},
+		{calendar.Filter{From: "20261015", To: "20261019"}, []*calendar.Event{review}},
+	}
+	for _, tt := range tests {
+		var got []*calendar.Event
+		for _, e := range []*calendar.Event{standup, review, dentist} {
+			if tt.filter.Matches(e, now, time.Monday) {
+				got = append(got, e)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Filter %+v: expected %d matching events, got %d", tt.filter, len(tt.want), len(got))
+		}
+	}
+
+	// A w window is the week containing now, starting on the first day of the week
+	sunday := event("Walk", "", 0, 11, 10)
+	if !(&calendar.Filter{From: "w"}).Matches(sunday, now, time.Sunday) {
+		t.Error("Expected Sunday October 11 to be in a week starting on Sunday")
+	}
+	if (&calendar.Filter{From: "w"}).Matches(sunday, now, time.Monday) {
+		t.Error("Expected Sunday October 11 to be before a week starting on Monday")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	valid := []calendar.Filter{
+		{Name: "work", Tag: "work"},
+		{Name: "blue", Color: "BLUE"},
+		{Name: "next", From: "t", To: "20261231"},
+	}
+	for _, filter := range valid {
+		if err := filter.Validate(); err != nil {
+			t.Errorf("Validate(%+v): unexpected error %v", filter, err)
+		}
+	}
+
+	invalid := []struct {
+		filter calendar.Filter
+		want   string
+	}{
+		{calendar.Filter{Text: "x"}, "one word"},
+		{calendar.Filter{Name: "my work", Text: "x"}, "one word"},
+		{calendar.Filter{Name: "empty"}, "no conditions"},
+		{calendar.Filter{Name: "pink", Color: "pink"}, "unknown color"},
+		{calendar.Filter{Name: "soon", From: "tomorrow"}, "YYYYMMDD"},
+	}
+	for _, tt := range invalid {
+		if err := tt.filter.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate(%+v): expected an error containing %q, got %v", tt.filter, tt.want, err)
+		}
+	}
+
+	if got := (&calendar.Filter{Name: "f", Text: "lunch", Color: "red", Tag: "#team", From: "t"}).Describe(); got != `"lunch" Red #team from t` {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
+
+func TestCalendarFiltered(t *testing.T) {
+	c := calendar.NewCalendar(calendar.NewDay(time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)))
+	events := []*calendar.Event{
+		{Id: 1, Name: "Lunch", Color: gocui.ColorRed},
+		{Id: 2, Name: "Gym", Color: gocui.ColorGreen},
+	}
+
+	if got := c.Filtered(events); len(got) != 2 {
+		t.Errorf("Expected every event without a filter, got %d", len(got))
+	}
+	c.Filter = &calendar.Filter{Name: "green", Color: "green"}
+	if got := c.Filtered(events); len(got) != 1 || got[0].Id != 2 {
+		t.Errorf("Expected only the green event, got %v", got)
+	}
+}
+
+func TestSavedFilters(t *testing.T) {
+	db := setupTestDB(t)
+
+	for _, filter := range []calendar.Filter{
+		{Name: "work", Tag: "work", From: "t"},
+		{Name: "blue", Color: "Blue"},
+	} {
+		if err := db.SaveFilter(filter); err != nil {
+			t.Fatalf("SaveFilter(%s) failed: %v", filter.Name, err)
+		}
+	}
+	// Saving a filter again replaces it
+	if err := db.SaveFilter(calendar.Filter{Name: "work", Text: "meeting"}); err != nil {
+		t.Fatalf("SaveFilter(work) failed: %v", err)
+	}
+
+	filters, err := db.GetFilters()
+	if err != nil {
+		t.Fatalf("GetFilters failed: %v", err)
+	}
+	want := []calendar.Filter{{Name: "blue", Color: "Blue"}, {Name: "work", Text: "meeting"}}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Expected %+v, got %+v", want, filters)
+	}
+
+	if err := db.DeleteFilter("blue"); err != nil {
+		t.Fatalf("DeleteFilter failed: %v", err)
+	}
+	if filters, _ := db.GetFilters(); len(filters) != 1 || filters[0].Name != "work" {
+		t.Errorf("Expected only filter work to be left, got %+v", filters)
+	}
+}
+
+func TestConfigFilters(t *testing.T) {
+	if filters, err := config.GetFilters(config.GetDefaultConfig()); err != nil || len(filters) != 0 {
+		t.Errorf("Expected no filters by default, got %v (%v)", filters, err)
+	}
+
+	cfg := &config.Config{Filters: []calendar.Filter{{Name: "work", Tag: "work"}, {Name: "work", Color: "red"}}}
+	if _, err := config.GetFilters(cfg); err == nil || !strings.Contains(err.Error(), "defined twice") {
+		t.Errorf("Expected a duplicate filter error, got %v", err)
+	}
+	cfg.Filters[1] = calendar.Filter{Name: "red", Color: "scarlet"}
+	if _, err := config.GetFilters(cfg); err == nil || !strings.Contains(err.Error(), "unknown color") {
+		t.Errorf("Expected an unknown color error, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/finder"
+)
+
+// finderEvent creates a half-hour event on a day of October 2026 for finder tests
+func finderEvent(id int, name, location string, day, hour int) *calendar.Event {
+	return &calendar.Event{Id: id, Name: name, Location: location, Time: time.Date(2026, 10, day, hour, 0, 0, 0, time.Local), DurationHour: 0.5, Occurence: 1}
+}
+
+func TestFinderScore(t *testing.T) {
+	tests := []struct {
+		term, text string
+		ok         bool
+	}{
+		{"stnd", "weekly standup", true},
+		{"wsu", "weekly standup", true},
+		{"", "anything", true},
+		{"dnats", "standup", false},
+		{"standups", "standup", false},
+	}
+	for _, tt := range tests {
+		if _, ok := finder.Score([]rune(tt.term), []rune(tt.text)); ok != tt.ok {
+			t.Errorf("Score(%q, %q): expected a match %t", tt.term, tt.text, tt.ok)
+		}
+	}
+
+	// Consecutive characters and word starts score higher than scattered ones
+	better := []struct{ term, best, worse string }{
+		{"stand", "standup", "s-t-a-n-d"},
+		{"rev", "code review", "reservation"},
+		{"pl", "planning", "apple"},
+	}
+	for _, tt := range better {
+		best, _ := finder.Score([]rune(tt.term), []rune(tt.best))
+		worse, _ := finder.Score([]rune(tt.term), []rune(tt.worse))
+		if best <= worse {
+			t.Errorf("Expected %q to score higher in %q (%d) than in %q (%d)", tt.term, tt.best, best, tt.worse, worse)
+		}
+	}
+}
+
+func TestFinderEntries(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	var events []*calendar.Event
+	// A weekly series of four, two of them past
+	for i, day := range []int{1, 8, 15, 22} {
+		event := finderEvent(10+i, "Standup", "Room 9", day, 9)
+		event.FrequencyDay, event.Occurence = 7, 4
+		events = append(events, event)
+	}
+	// A finished series, and single events of the same name
+	for i, day := range []int{5, 6} {
+		event := finderEvent(20+i, "Standup", "Room 9", day, 15)
+		event.FrequencyDay, event.Occurence = 1, 2
+		events = append(events, event)
+	}
+	events = append(events, finderEvent(30, "Standup", "Room 9", 20, 9), finderEvent(31, "Dentist", "", 2, 11))
+
+	entries := finder.Entries(events, now)
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+	want := []struct {
+		id, count int
+		upcoming  bool
+	}{
+		{12, 4, true},  // the next occurrence of the weekly series
+		{21, 2, false}, // the last occurrence of the finished series
+		{30, 1, true},
+		{31, 1, false},
+	}
+	for i, w := range want {
+		if entries[i].Event.Id != w.id || entries[i].Count != w.count || entries[i].Upcoming != w.upcoming {
+			t.Errorf("Entry %d: expected event %d x%d upcoming %t, got event %d x%d upcoming %t", i, w.id, w.count, w.upcoming,
+				entries[i].Event.Id, entries[i].Count, entries[i].Upcoming)
+		}
+	}
+}
+
+func TestFinderRank(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	entries := finder.Entries([]*calendar.Event{
+		finderEvent(1, "Dentist", "", 2, 11),
+		finderEvent(2, "Code review", "Room 3", 20, 14),
+		finderEvent(3, "Lunch", "Cafe Review", 16, 12),
+		finderEvent(4, "Retro", "Room 3", 15, 10),
+		finderEvent(5, "Review budget", "", 9, 10),
+	}, now)
+
+	ids := func(matches []finder.Match) []int {
+		var ids []int
+		for _, match := range matches {
+			ids = append(ids, match.Event.Id)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		query string
+		limit int
+		want  []int
+	}{
+		// Upcoming events soonest first, then past events latest first
+		{"", 0, []int{4, 3, 2, 5, 1}},
+		{"", 2, []int{4, 3}},
+		// Names rank above locations; equal matches list upcoming events first
+		{"review", 0, []int{2, 5, 3}},
+		{"room rev", 0, []int{2}},
+		{"r3", 0, []int{4, 2}},
+		{"CAFE", 0, []int{3}},
+		{"zz", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := ids(finder.Rank(entries, tt.query, tt.limit)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Rank(%q, %d): expected %v, got %v", tt.query, tt.limit, tt.want, got)
+		}
+	}
+}
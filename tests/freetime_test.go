@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/freetime"
+)
+
+// freeDay returns a time days after the Monday of setupConflictDay
+func freeDay(days, hour, minute int) time.Time {
+	return conflictTime(hour, minute).AddDate(0, 0, days)
+}
+
+func TestFindFreeSlots(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	defer db.CloseDatabase()
+	setupConflictDay(t, em)
+
+	workingHours := freetime.Query{DayStart: 9 * time.Hour, DayEnd: 17 * time.Hour, Weekdays: freetime.WorkWeek}
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		from, to time.Time
+		weekdays freetime.Weekdays
+		limit    int
+		want     [][2]time.Time
+	}{
+		{"gap after busy morning", time.Hour, freeDay(0, 0, 0), freeDay(1, 0, 0), 0, 0,
+			[][2]time.Time{{freeDay(0, 14, 30), freeDay(0, 17, 0)}}},
+		{"short gap between events", 30 * time.Minute, freeDay(0, 0, 0), freeDay(1, 0, 0), 0, 0,
+			[][2]time.Time{{freeDay(0, 13, 0), freeDay(0, 13, 30)}, {freeDay(0, 14, 30), freeDay(0, 17, 0)}}},
+		{"next day is empty", 2 * time.Hour, freeDay(0, 0, 0), freeDay(2, 0, 0), 0, 0,
+			[][2]time.Time{{freeDay(0, 14, 30), freeDay(0, 17, 0)}, {freeDay(1, 9, 0), freeDay(1, 17, 0)}}},
+		{"start rounds up to half hour", time.Hour, freeDay(0, 14, 40), freeDay(1, 0, 0), 0, 0,
+			[][2]time.Time{{freeDay(0, 15, 0), freeDay(0, 17, 0)}}},
+		{"window ends mid-day", time.Hour, freeDay(0, 0, 0), freeDay(0, 16, 0), 0, 0,
+			[][2]time.Time{{freeDay(0, 14, 30), freeDay(0, 16, 0)}}},
+		{"too long for any gap", 3 * time.Hour, freeDay(0, 0, 0), freeDay(1, 0, 0), 0, 0, nil},
+		{"weekend skipped", time.Hour, freeDay(-2, 0, 0), freeDay(0, 0, 0), 0, 0, nil},
+		{"weekend mask", time.Hour, freeDay(-2, 0, 0), freeDay(0, 0, 0), freetime.Weekend, 0,
+			[][2]time.Time{{freeDay(-2, 9, 0), freeDay(-2, 17, 0)}, {freeDay(-1, 9, 0), freeDay(-1, 17, 0)}}},
+		{"limit", time.Hour, freeDay(0, 0, 0), freeDay(7, 0, 0), 0, 2,
+			[][2]time.Time{{freeDay(0, 14, 30), freeDay(0, 17, 0)}, {freeDay(1, 9, 0), freeDay(1, 17, 0)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := workingHours
+			query.Duration = tt.duration
+			query.From, query.To = tt.from, tt.to
+			query.Limit = tt.limit
+			if tt.weekdays != 0 {
+				query.Weekdays = tt.weekdays
+			}
+
+			slots, err := freetime.Find(em, query)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			if len(slots) != len(tt.want) {
+				t.Fatalf("Expected %d slots, got %d: %v", len(tt.want), len(slots), slots)
+			}
+			for i, want := range tt.want {
+				if !slots[i].Start.Equal(want[0]) || !slots[i].End.Equal(want[1]) {
+					t.Errorf("Slot %d: expected %s-%s, got %s-%s", i,
+						want[0].Format("Mon 15:04"), want[1].Format("Mon 15:04"),
+						slots[i].Start.Format("Mon 15:04"), slots[i].End.Format("Mon 15:04"))
+				}
+			}
+		})
+	}
+
+	if _, err := freetime.Find(em, freetime.Query{From: freeDay(0, 0, 0), To: freeDay(1, 0, 0)}); err == nil {
+		t.Error("Expected an error for a zero duration")
+	}
+	if _, err := freetime.Find(em, freetime.Query{Duration: time.Hour, From: freeDay(1, 0, 0), To: freeDay(0, 0, 0)}); err == nil {
+		t.Error("Expected an error for an empty window")
+	}
+}
+
+func TestFreeTimeParsing(t *testing.T) {
+	weekdays := []struct {
+		input string
+		want  freetime.Weekdays
+		fails bool
+	}{
+		{"", freetime.AllDays, false},
+		{"weekdays", freetime.WorkWeek, false},
+		{"mon-fri", freetime.WorkWeek, false},
+		{"Sat,Sunday", freetime.Weekend, false},
+		{"fri-mon", 1<<time.Friday | freetime.Weekend | 1<<time.Monday, false},
+		{"mon,wed,fri", 1<<time.Monday | 1<<time.Wednesday | 1<<time.Friday, false},
+		{"mo", 0, true},
+		{"mon-xyz", 0, true},
+	}
+	for _, tt := range weekdays {
+		got, err := freetime.ParseWeekdays(tt.input)
+		if (err != nil) != tt.fails {
+			t.Errorf("ParseWeekdays(%q): unexpected error state %v", tt.input, err)
+		} else if !tt.fails && got != tt.want {
+			t.Errorf("ParseWeekdays(%q) = %s, expected %s", tt.input, got, tt.want)
+		}
+	}
+
+	hours := []struct {
+		input      string
+		start, end time.Duration
+		fails      bool
+	}{
+		{"09:00-17:00", 9 * time.Hour, 17 * time.Hour, false},
+		{"8:30-24:00", 8*time.Hour + 30*time.Minute, 24 * time.Hour, false},
+		{"7-19", 7 * time.Hour, 19 * time.Hour, false},
+		{"17:00-09:00", 0, 0, true},
+		{"09:00", 0, 0, true},
+		{"09:75-10:00", 0, 0, true},
+	}
+	for _, tt := range hours {
+		start, end, err := freetime.ParseHours(tt.input)
+		if (err != nil) != tt.fails {
+			t.Errorf("ParseHours(%q): unexpected error state %v", tt.input, err)
+		} else if !tt.fails && (start != tt.start || end != tt.end) {
+			t.Errorf("ParseHours(%q) = %s-%s, expected %s-%s", tt.input, start, end, tt.start, tt.end)
+		}
+	}
+
+	durations := []struct {
+		input string
+		want  time.Duration
+		fails bool
+	}{
+		{"1.5h", 90 * time.Minute, false},
+		{"1.5", 90 * time.Minute, false},
+		{"90m", 90 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"0", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range durations {
+		got, err := freetime.ParseDuration(tt.input)
+		if (err != nil) != tt.fails {
+			t.Errorf("ParseDuration(%q): unexpected error state %v", tt.input, err)
+		} else if !tt.fails && got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, expected %s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/jroimartin/gocui"
+)
+
+func TestGrab(t *testing.T) {
+	start := time.Date(2026, 10, 21, 23, 0, 0, 0, time.Local)
+	event := calendar.Event{Id: 7, Name: "Late", Time: start, DurationHour: 1}
+	grab := calendar.NewGrab(&event)
+
+	if grab.Changed() {
+		t.Error("Expected a fresh grab to be unchanged")
+	}
+
+	// Two slots later crosses midnight, a day back returns to the 21st
+	grab.Move(0, 2)
+	if want := time.Date(2026, 10, 22, 0, 0, 0, 0, time.Local); !grab.Event.Time.Equal(want) {
+		t.Errorf("Expected %s, got %s", want, grab.Event.Time)
+	}
+	grab.Move(-1, 0)
+	if !grab.StartsOn(time.Date(2026, 10, 21, 12, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected the preview to start on the 21st, got %s", grab.Event.Time)
+	}
+	if !grab.Changed() {
+		t.Error("Expected a moved grab to be changed")
+	}
+
+	// Shrinking stops at one slot
+	grab.Resize(1)
+	if grab.Event.DurationHour != 1.5 {
+		t.Errorf("Expected 1.5h, got %.1fh", grab.Event.DurationHour)
+	}
+	grab.Resize(-5)
+	if grab.Event.DurationHour != 0.5 {
+		t.Errorf("Expected the shortest event to be 0.5h, got %.1fh", grab.Event.DurationHour)
+	}
+
+	// The grabbed event is untouched
+	if !event.Time.Equal(start) || event.DurationHour != 1 || !grab.Original.Time.Equal(start) {
+		t.Errorf("Expected the original event to keep its time, got %s for %.1fh", event.Time, event.DurationHour)
+	}
+
+	grab.Conflicts = []int{3, 4}
+	if !grab.ConflictsWith(4) || grab.ConflictsWith(7) {
+		t.Errorf("Expected conflicts with 3 and 4 only, got %v", grab.Conflicts)
+	}
+}
+
+func TestGrabKeys(t *testing.T) {
+	km := keymap.Default()
+
+	tests := []struct {
+		key    keymap.Key
+		action string
+	}{
+		{keymap.Key{Ch: 'j'}, "grab.down"},
+		{keymap.Key{Ch: 'h'}, "grab.prev_day"},
+		{keymap.Key{Ch: 'J'}, "grab.grow"},
+		{keymap.Key{Key: gocui.KeyEnter}, "grab.commit"},
+		{keymap.Key{Ch: 'd'}, ""}, // other keys do nothing while an event is held
+	}
+	for _, tt := range tests {
+		action, _ := km.LookupMode(keymap.ScopeGrab, tt.key)
+		if action != tt.action {
+			t.Errorf("LookupMode(grab, %s): expected %q, got %q", tt.key, tt.action, action)
+		}
+	}
+
+	if action, _ := km.Lookup(keymap.ScopeCalendar, keymap.Key{Ch: 'R'}); action != "grab.start" {
+		t.Errorf("Expected R to grab the selected event, got %q", action)
+	}
+	if !keymap.Repeats("grab.down") {
+		t.Error("Expected a count to move a grabbed event several slots")
+	}
+}
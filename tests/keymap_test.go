@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/keymap"
+	"github.com/jroimartin/gocui"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    keymap.Key
+		display string
+	}{
+		{"a", keymap.Key{Ch: 'a'}, "a"},
+		{"A", keymap.Key{Ch: 'A'}, "A"},
+		{"?", keymap.Key{Ch: '?'}, "?"},
+		{"+", keymap.Key{Ch: '+'}, "+"},
+		{"ctrl+e", keymap.Key{Key: gocui.KeyCtrlE}, "Ctrl-e"},
+		{"Ctrl+E", keymap.Key{Key: gocui.KeyCtrlE}, "Ctrl-e"},
+		{"alt+j", keymap.Key{Ch: 'j', Mod: gocui.ModAlt}, "Alt-j"},
+		{"alt+ctrl+d", keymap.Key{Key: gocui.KeyCtrlD, Mod: gocui.ModAlt}, "Alt-Ctrl-d"},
+		{"enter", keymap.Key{Key: gocui.KeyEnter}, "Enter"},
+		{"ESC", keymap.Key{Key: gocui.KeyEsc}, "Esc"},
+		{"space", keymap.Key{Key: gocui.KeySpace}, "Space"},
+		{" ", keymap.Key{Key: gocui.KeySpace}, "Space"},
+		{"left", keymap.Key{Key: gocui.KeyArrowLeft}, "←"},
+		{"f5", keymap.Key{Key: gocui.KeyF5}, "F5"},
+	}
+
+	for _, tt := range tests {
+		key, err := keymap.ParseKey(tt.spec)
+		if err != nil {
+			t.Errorf("ParseKey(%q): unexpected error %v", tt.spec, err)
+			continue
+		}
+		if key != tt.want {
+			t.Errorf("ParseKey(%q): expected %+v, got %+v", tt.spec, tt.want, key)
+		}
+		if key.String() != tt.display {
+			t.Errorf("ParseKey(%q): expected it shown as %q, got %q", tt.spec, tt.display, key.String())
+		}
+	}
+
+	for _, spec := range []string{"", "ab", "ctrl+1", "ctrl+enter", "shift+a", "ctrl+"} {
+		if _, err := keymap.ParseKey(spec); err == nil {
+			t.Errorf("ParseKey(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestKeymapOverrides(t *testing.T) {
+	km, err := keymap.New(map[string][]string{
+		"event.add":      {"a", "ctrl+n", "a"},
+		"event.delete":   {},
+		"nav.next_week":  {"alt+l"},
+		"app.quit":       {""},
+		"nav.prev_month": {"K"}, // K is the year view's previous month, which the year view keeps
+	})
+	if err == nil {
+		t.Fatalf("Expected K to conflict with year.prev_month")
+	}
+
+	km, err = keymap.New(map[string][]string{
+		"event.add":     {"a", "ctrl+n", "a"},
+		"event.delete":  {},
+		"nav.next_week": {"alt+l"},
+		"app.quit":      {""},
+		"event.yank":    {"x"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if want := []keymap.Key{{Ch: 'a'}, {Key: gocui.KeyCtrlN}}; !reflect.DeepEqual(km.Keys("event.add"), want) {
+		t.Errorf("Expected event.add on a and Ctrl-n once each, got %v", km.Keys("event.add"))
+	}
+	if len(km.Keys("event.delete")) != 0 || len(km.Keys("app.quit")) != 0 {
+		t.Errorf("Expected event.delete and app.quit unbound, got %v and %v", km.Keys("event.delete"), km.Keys("app.quit"))
+	}
+	if !km.HasAltBindings() {
+		t.Errorf("Expected Alt bindings")
+	}
+
+	lookups := []struct {
+		scope  string
+		key    keymap.Key
+		action string
+	}{
+		{keymap.ScopeCalendar, keymap.Key{Ch: 'x'}, "event.yank"},
+		{keymap.ScopeCalendar, keymap.Key{Key: gocui.KeyCtrlN}, "event.add"},
+		{keymap.ScopeCalendar, keymap.Key{Ch: 'l', Mod: gocui.ModAlt}, "nav.next_week"},
+		{keymap.ScopeCalendar, keymap.Key{Ch: 'L'}, ""},
+		{keymap.ScopeCalendar, keymap.Key{Ch: 'q'}, ""},
+		{keymap.ScopeCalendar, keymap.Key{Ch: 'J'}, ""},
+		{keymap.ScopeYear, keymap.Key{Ch: 'J'}, "year.next_month"},
+		{keymap.ScopeYear, keymap.Key{Ch: 'h'}, "nav.prev_day"},
+		{keymap.ScopeAgenda, keymap.Key{Ch: 'z'}, "agenda.toggle_empty_days"},
+		{keymap.ScopeAgenda, keymap.Key{Key: gocui.KeyEnter}, ""},
+	}
+	for _, tt := range lookups {
+		action, _ := km.Lookup(tt.scope, tt.key)
+		if action != tt.action {
+			t.Errorf("Lookup(%s, %s): expected %q, got %q", tt.scope, tt.key, tt.action, action)
+		}
+	}
+
+	// Alt keys are bound on their plain key, which the dispatcher reads Alt from
+	scopeKeys := km.ScopeKeys(keymap.ScopeCalendar)
+	var hasL bool
+	for _, key := range scopeKeys {
+		if key.Mod != 0 {
+			t.Errorf("Expected scope keys without Alt, got %s", key)
+		}
+		hasL = hasL || key == keymap.Key{Ch: 'l'}
+	}
+	if !hasL {
+		t.Errorf("Expected l among the calendar keys")
+	}
+}
+
+func TestKeymapErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		want      string
+	}{
+		{"unknown action", map[string][]string{"event.launch": {"l"}}, `unknown action "event.launch"`},
+		{"invalid key", map[string][]string{"event.add": {"ctrl+?"}}, "event.add: invalid key"},
+		{"conflict with a default", map[string][]string{"event.add": {"x"}}, "key x is bound to both event.add and event.delete"},
+		{"calendar key used by the agenda", map[string][]string{"event.add": {"z"}}, "key z is bound to both"},
+		{"same key twice", map[string][]string{"event.add": {"ctrl+n"}, "event.quick_add": {"ctrl+n"}}, "key Ctrl-n is bound to both"},
+		{"ctrl letter that is another key", map[string][]string{"event.add": {"ctrl+m"}}, "key Enter is bound to both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := keymap.New(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// The year and agenda keys do not share a view, so they may overlap
+	if _, err := keymap.New(map[string][]string{"agenda.toggle_empty_days": {"J"}}); err != nil {
+		t.Errorf("Expected a year key in the agenda to be allowed, got %v", err)
+	}
+}
+
+func TestKeymapHelp(t *testing.T) {
+	km, err := keymap.New(map[string][]string{"nav.prev_day": {"h", "alt+h"}, "event.delete": nil})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sections := km.Help()
+	if len(sections) == 0 || sections[0].Category != "Views" || sections[0].Entries[0].Keys != "q" {
+		t.Fatalf("Expected the help to start with q in Views, got %+v", sections)
+	}
+
+	entries := map[string]string{}
+	for _, section := range sections {
+		for _, entry := range section.Entries {
+			entries[entry.Description] = entry.Keys
+		}
+	}
+	if entries["Previous day"] != "h Alt-h" {
+		t.Errorf("Expected previous day on h Alt-h, got %q", entries["Previous day"])
+	}
+	if _, ok := entries["Delete event"]; ok {
+		t.Errorf("Expected unbound actions to be left out of the help")
+	}
+
+	// Every action has default keys that do not conflict
+	for _, action := range keymap.Actions {
+		if len(keymap.Default().Keys(action.Name)) == 0 {
+			t.Errorf("Expected %s to have default keys", action.Name)
+		}
+	}
+}
+
+func TestConfigKeybindings(t *testing.T) {
+	var cfg config.Config
+	data := `{"keybindings": {"event.add": "ctrl+n", "nav.next_week": ["L", "alt+l"], "event.delete": [], "app.quit": null}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Failed to read keybindings: %v", err)
+	}
+
+	want := map[string][]string{
+		"event.add":     {"ctrl+n"},
+		"nav.next_week": {"L", "alt+l"},
+		"event.delete":  {},
+		"app.quit":      {""},
+	}
+	if got := config.GetKeybindings(&cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	km, err := keymap.New(config.GetKeybindings(&cfg))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(km.Keys("app.quit")) != 0 {
+		t.Errorf("Expected null to unbind app.quit, got %v", km.Keys("app.quit"))
+	}
+}
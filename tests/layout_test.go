@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// layoutDay returns noon on the given day of June 2030 (June 2 is a Sunday)
+func layoutDay(day int) time.Time {
+	return time.Date(2030, 6, day, 12, 0, 0, 0, time.Local)
+}
+
+func TestRollingLayout(t *testing.T) {
+	c := calendar.NewCalendar(calendar.NewDay(layoutDay(5)))
+	c.SetLayout(4, true)
+
+	steps := []struct {
+		name       string
+		move       func()
+		start, end int
+	}{
+		{"starts at the selected day", func() {}, 5, 8},
+		{"moving inside the window keeps it", func() { c.UpdateToNextDay(); c.UpdateToNextDay() }, 5, 8},
+		{"moving past the end scrolls by a day", func() { c.UpdateToNextDay(); c.UpdateToNextDay() }, 6, 9},
+		{"jumping back shows the day first", func() { c.GotoDate(2030, 6, 2) }, 2, 5},
+		{"moving before the start scrolls back", func() { c.UpdateToPrevDay() }, 1, 4},
+	}
+
+	for _, step := range steps {
+		step.move()
+		if len(c.CurrentWeek.Days) != 4 {
+			t.Fatalf("%s: expected 4 days, got %d", step.name, len(c.CurrentWeek.Days))
+		}
+		start, end := c.CurrentWeek.Days[0].Date, c.CurrentWeek.Days[3].Date
+		if start.Day() != layoutDay(step.start).Day() || end.Day() != layoutDay(step.end).Day() {
+			t.Errorf("%s: expected June %d to %d, got %s to %s", step.name, step.start, step.end,
+				start.Format("Jan 2"), end.Format("Jan 2"))
+		}
+		if i := c.DayIndex(c.CurrentDay.Date); i < 0 || i > 3 {
+			t.Errorf("%s: selected day not shown, index %d", step.name, i)
+		}
+	}
+
+	c.SetLayout(7, false)
+	if len(c.CurrentWeek.Days) != 7 || c.CurrentWeek.Days[0].Date.Weekday() != time.Sunday {
+		t.Errorf("Expected a Sunday-to-Saturday week after leaving the rolling layout, got %d days from %s",
+			len(c.CurrentWeek.Days), c.CurrentWeek.Days[0].Date.Weekday())
+	}
+	if c.DayIndex(layoutDay(20)) != -1 {
+		t.Error("Expected DayIndex of a day outside the week to be -1")
+	}
+}
+
+func TestFormatWeekBody(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{"within a month", layoutDay(5), "June 2 to 8"},
+		{"across months", time.Date(2030, 7, 1, 12, 0, 0, 0, time.Local), "June 30 to July 6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := calendar.NewCalendar(calendar.NewDay(tt.date))
+			if got := c.FormatWeekBody(); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/jumplist"
+)
+
+func TestMarks(t *testing.T) {
+	db := setupTestDB(t)
+
+	if mark, err := db.GetMark("a"); err != nil || mark != nil {
+		t.Fatalf("Expected no mark a, got %v (%v)", mark, err)
+	}
+
+	local := time.FixedZone("UTC+10", 10*60*60)
+	planning := time.Date(2026, 11, 3, 14, 0, 0, 0, local)
+	for name, when := range map[string]time.Time{
+		"p": planning,
+		"a": time.Date(2026, 10, 22, 10, 0, 0, 0, time.UTC),
+	} {
+		if err := db.SetMark(name, when); err != nil {
+			t.Fatalf("SetMark(%s) failed: %v", name, err)
+		}
+	}
+
+	mark, err := db.GetMark("p")
+	if err != nil || mark == nil {
+		t.Fatalf("Expected mark p, got %v (%v)", mark, err)
+	}
+	if !mark.Time.Equal(planning) {
+		t.Errorf("Expected mark p at %s, got %s", planning, mark.Time)
+	}
+
+	// Setting a mark again moves it
+	moved := planning.AddDate(0, 0, 7)
+	if err := db.SetMark("p", moved); err != nil {
+		t.Fatalf("SetMark(p) failed: %v", err)
+	}
+
+	marks, err := db.GetMarks()
+	if err != nil {
+		t.Fatalf("GetMarks failed: %v", err)
+	}
+	if len(marks) != 2 || marks[0].Name != "a" || marks[1].Name != "p" || !marks[1].Time.Equal(moved) {
+		t.Errorf("Expected marks a and p with p moved a week, got %+v", marks)
+	}
+
+	if err := db.DeleteMark("a"); err != nil {
+		t.Fatalf("DeleteMark failed: %v", err)
+	}
+	if mark, _ := db.GetMark("a"); mark != nil {
+		t.Errorf("Expected mark a to be deleted, got %+v", mark)
+	}
+}
+
+func TestJumpList(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+
+	var jumps jumplist.List
+	if _, ok := jumps.Back(day(1)); ok {
+		t.Error("Expected nothing to go back to in an empty list")
+	}
+
+	// Jumps from the 1st to the 5th to the 9th, then to the 20th
+	jumps = jumplist.List{}
+	jumps.Push(day(1))
+	jumps.Push(day(5))
+	jumps.Push(day(9))
+
+	for _, want := range []int{9, 5, 1} {
+		got, ok := jumps.Back(day(20))
+		if !ok || !got.Equal(day(want)) {
+			t.Fatalf("Back: expected day %d, got %s (%v)", want, got, ok)
+		}
+	}
+	if _, ok := jumps.Back(day(1)); ok {
+		t.Error("Expected Back to stop at the oldest position")
+	}
+
+	for _, want := range []int{5, 9, 20} {
+		got, ok := jumps.Forward()
+		if !ok || !got.Equal(day(want)) {
+			t.Fatalf("Forward: expected day %d, got %s (%v)", want, got, ok)
+		}
+	}
+	if _, ok := jumps.Forward(); ok {
+		t.Error("Expected Forward to stop at the position the walk started from")
+	}
+
+	// A position is kept once, at its latest jump
+	jumps.Push(day(5))
+	if last, _ := jumps.Last(); !last.Equal(day(5)) || jumps.Len() != 4 {
+		t.Errorf("Expected day 5 last of 4 positions, got %s of %d", last, jumps.Len())
+	}
+
+	// The oldest positions are forgotten
+	for i := 0; i < 150; i++ {
+		jumps.Push(day(1).Add(time.Duration(i) * time.Hour))
+	}
+	if jumps.Len() != 100 {
+		t.Errorf("Expected the list to be capped at 100 positions, got %d", jumps.Len())
+	}
+}
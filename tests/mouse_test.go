@@ -0,0 +1,289 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/pkg/views"
+)
+
+func TestGrabResizeTo(t *testing.T) {
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+	event := calendar.Event{Id: 4, Name: "Planning", Time: start, DurationHour: 2}
+
+	tests := []struct {
+		name string
+		end  time.Time
+		want float64
+	}{
+		{"shorter", start.Add(90 * time.Minute), 1.5},
+		{"longer", start.Add(3 * time.Hour), 3},
+		{"past midnight", time.Date(2026, 10, 20, 1, 0, 0, 0, time.Local), 15},
+		{"end at the start keeps one slot", start, 0.5},
+		{"end before the start keeps one slot", start.Add(-time.Hour), 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grab := calendar.NewGrab(&event)
+			grab.ResizeTo(tt.end)
+			if grab.Event.DurationHour != tt.want {
+				t.Errorf("Expected %.1fh, got %.1fh", tt.want, grab.Event.DurationHour)
+			}
+			if !grab.Event.Time.Equal(start) {
+				t.Errorf("Expected the start to stay at %s, got %s", start, grab.Event.Time)
+			}
+		})
+	}
+}
+
+func TestIsMouseEnabled(t *testing.T) {
+	if !config.IsMouseEnabled(config.GetDefaultConfig()) {
+		t.Error("Expected the mouse to be enabled by default")
+	}
+	if config.IsMouseEnabled(&config.Config{DisableMouse: true}) {
+		t.Error("Expected disable_mouse to turn the mouse off")
+	}
+}
+
+func TestOnBottomEdge(t *testing.T) {
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+	long := &calendar.Event{Time: start, DurationHour: 1.5}
+	short := &calendar.Event{Time: start, DurationHour: 0.5}
+
+	tests := []struct {
+		name  string
+		event *calendar.Event
+		slot  time.Time
+		want  bool
+	}{
+		{"last slot resizes", long, start.Add(time.Hour), true},
+		{"first slot moves", long, start, false},
+		{"middle slot moves", long, start.Add(30 * time.Minute), false},
+		{"an event of one slot only moves", short, start, false},
+	}
+	for _, tt := range tests {
+		if got := calendar.OnBottomEdge(tt.event, tt.slot); got != tt.want {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDoubleClicks(t *testing.T) {
+	var clicks views.DoubleClicks
+	at := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+
+	if clicks.Click("agenda", 5, at) {
+		t.Error("Expected the first click to be single")
+	}
+	if !clicks.Click("agenda", 5, at.Add(200*time.Millisecond)) {
+		t.Error("Expected a second click on the same row to be double")
+	}
+	if clicks.Click("agenda", 5, at.Add(300*time.Millisecond)) {
+		t.Error("Expected a third click to start over")
+	}
+	if clicks.Click("agenda", 6, at.Add(400*time.Millisecond)) {
+		t.Error("Expected a click on another row to be single")
+	}
+	if clicks.Click("agenda", 6, at.Add(time.Second)) {
+		t.Error("Expected a slow second click to be single")
+	}
+	if clicks.Click("monthday_3", 6, at.Add(1100*time.Millisecond)) {
+		t.Error("Expected a click in another view to be single")
+	}
+}
+
+func TestDayViewHitTests(t *testing.T) {
+	tv := views.NewTimeView()
+	tv.SetProperties(0, 5, 8, 20) // 19 slots
+	tv.ViewportStart = 16         // 08:00
+	day := calendar.NewDay(time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local))
+	dv := views.NewDayView("monday", day, tv)
+	dv.SetProperties(10, 5, 16, 20)
+
+	slots := []struct {
+		y    int
+		want string
+		ok   bool
+	}{
+		{5, "", false}, // the frame
+		{6, "08:00", true},
+		{9, "09:30", true},
+		{24, "17:00", true},
+		{25, "", false}, // below the last slot
+	}
+	for _, tt := range slots {
+		slot, ok := dv.SlotAt(tt.y)
+		if ok != tt.ok || (ok && (slot.Format("15:04") != tt.want || slot.Day() != 19)) {
+			t.Errorf("SlotAt(%d): expected %s (%t), got %s (%t)", tt.y, tt.want, tt.ok, slot.Format("Jan 2 15:04"), ok)
+		}
+	}
+
+	// Side by side overlapping events: the one added last is drawn on top
+	left := views.NewEvenView("left", &calendar.Event{Id: 1, Name: "Left"})
+	left.SetProperties(10, 7, 16, 4)
+	right := views.NewEvenView("right", &calendar.Event{Id: 2, Name: "Right"})
+	right.SetProperties(17, 7, 9, 4)
+	dv.AddChild(left.Name, left)
+	dv.AddChild(right.Name, right)
+
+	events := []struct {
+		x, y int
+		want int
+	}{
+		{12, 9, 1},
+		{20, 9, 2},
+		{20, 8, 2},
+		{12, 11, 0}, // the row below the block
+		{10, 9, 0},  // the column's border
+	}
+	for _, tt := range events {
+		eventView, ok := dv.EventAt(tt.x, tt.y)
+		got := 0
+		if ok {
+			got = eventView.Event.Id
+		}
+		if got != tt.want {
+			t.Errorf("EventAt(%d, %d): expected event %d, got %d", tt.x, tt.y, tt.want, got)
+		}
+	}
+}
+
+func TestWeekViewDayAt(t *testing.T) {
+	c := calendar.NewCalendar(calendar.NewDay(time.Date(2026, 10, 21, 10, 0, 0, 0, time.Local)))
+	c.SetWeekStart(time.Monday)
+	wv := views.NewWeekView(c, views.NewTimeView())
+	for i, name := range views.DayColumnNames[:7] {
+		if child, ok := wv.GetChild(name); ok {
+			child.SetProperties(10+i*17, 5, 16, 20)
+		}
+	}
+
+	tests := []struct {
+		x, y int
+		want int // day of October, 0 for none
+	}{
+		{12, 10, 19},
+		{10 + 2*17 + 5, 10, 21},
+		{10 + 6*17 + 15, 24, 25},
+		{10 + 17, 10, 0}, // a column's border
+		{12, 5, 0},       // the title row
+		{5, 10, 0},       // the time column
+	}
+	for _, tt := range tests {
+		dv, ok := wv.DayAt(tt.x, tt.y)
+		got := 0
+		if ok {
+			got = dv.Day.Date.Day()
+		}
+		if got != tt.want {
+			t.Errorf("DayAt(%d, %d): expected October %d, got %d", tt.x, tt.y, tt.want, got)
+		}
+	}
+
+	// The single-day view has only the selected day's column
+	wv.SingleDay = true
+	if _, ok := wv.DayAt(12, 10); ok {
+		t.Error("Expected Monday's column to be hidden in the single-day view")
+	}
+	if dv, ok := wv.DayAt(10+2*17+5, 10); !ok || dv.Day.Date.Day() != 21 {
+		t.Error("Expected the selected Wednesday's column in the single-day view")
+	}
+}
+
+func TestAgendaEventIndexAt(t *testing.T) {
+	em, _ := setupTestEventManager(t)
+	for _, event := range []*calendar.Event{
+		agendaEvent("Standup", 3, 9),
+		agendaEvent("Lunch", 3, 12),
+		agendaEvent("Review", 5, 15),
+	} {
+		if _, ok := em.AddEvent(*event); !ok {
+			t.Fatalf("Failed to add %s", event.Name)
+		}
+	}
+
+	c := calendar.NewCalendar(calendar.NewDay(time.Date(2030, 6, 3, 9, 0, 0, 0, time.Local)))
+	agenda := views.NewAgendaView(c, em)
+	if err := agenda.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	// Every line that lists an event, and only those, is that event's
+	check := func(lines []string) {
+		t.Helper()
+		for i, line := range lines {
+			want := -1
+			for index, name := range []string{"Standup", "Lunch", "Review"} {
+				if strings.Contains(line, name) {
+					want = index
+				}
+			}
+			index, ok := agenda.EventIndexAt(agenda.HeaderHeight + i)
+			if (want >= 0) != ok || (ok && index != want) {
+				t.Errorf("Line %d %q: expected event %d, got %d (%t)", i, line, want, index, ok)
+			}
+		}
+	}
+	agenda.SelectedIndex = 0
+	lines := agenda.Layout(20)
+	if len(lines) < 6 {
+		t.Fatalf("Expected the week's dates and events, got %q", lines)
+	}
+	check(lines)
+	if _, ok := agenda.EventIndexAt(0); ok {
+		t.Error("Expected no event on the column titles")
+	}
+	if _, ok := agenda.EventIndexAt(agenda.HeaderHeight + len(lines)); ok {
+		t.Error("Expected no event below the last line")
+	}
+
+	// Scrolled down to the last event
+	agenda.SelectedIndex = 2
+	lines = agenda.Layout(3)
+	if len(lines) != 3 || !strings.Contains(lines[2], "Review") {
+		t.Fatalf("Expected the list to scroll to Review, got %q", lines)
+	}
+	check(lines)
+}
+
+func TestMonthDayEventAtRow(t *testing.T) {
+	date := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	mdv := views.NewMonthDayView("monthday_0", date, date)
+	var events []*calendar.Event
+	for i, name := range []string{"Standup", "Lunch", "Gym"} {
+		events = append(events, &calendar.Event{Id: i + 1, Name: name, Time: date.Add(time.Duration(9+i) * time.Hour)})
+	}
+	mdv.LoadEvents(events)
+
+	tests := []struct {
+		name   string
+		height int
+		want   []int // the event id on rows 0 to 4, 0 for none
+		more   bool
+	}{
+		{"all fit", 6, []int{0, 1, 2, 3, 0}, false},
+		// The last row tells how many more there are
+		{"overflow", 4, []int{0, 1, 0, 0, 0}, true},
+		{"exactly fit", 5, []int{0, 1, 2, 3, 0}, false},
+	}
+	for _, tt := range tests {
+		mdv.SetProperties(0, 0, 18, tt.height)
+		for row, want := range tt.want {
+			event, ok := mdv.EventAtRow(row)
+			got := 0
+			if ok {
+				got = event.Id
+			}
+			if got != want {
+				t.Errorf("%s: row %d: expected event %d, got %d", tt.name, row, want, got)
+			}
+		}
+		// Clicks find the events as drawn
+		if _, more := mdv.VisibleEvents(); more != tt.more {
+			t.Errorf("%s: expected overflow %t, got %t", tt.name, tt.more, more)
+		}
+	}
+}
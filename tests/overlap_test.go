@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+// overlapEvent creates an event on a fixed day starting at the given hour
+func overlapEvent(id int, hour, duration float64) *calendar.Event {
+	start := time.Date(2030, 6, 3, 0, 0, 0, 0, time.Local).Add(time.Duration(hour * float64(time.Hour)))
+	return &calendar.Event{Id: id, Name: "Event", Time: start, DurationHour: duration}
+}
+
+func TestOverlapColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []*calendar.Event
+		expected [][2]int // column, columns
+	}{
+		{
+			name:     "no overlaps",
+			events:   []*calendar.Event{overlapEvent(1, 9, 1), overlapEvent(2, 10, 1)},
+			expected: [][2]int{{0, 1}, {0, 1}},
+		},
+		{
+			name:     "talk during focus block",
+			events:   []*calendar.Event{overlapEvent(1, 9, 3), overlapEvent(2, 10, 1)},
+			expected: [][2]int{{0, 2}, {1, 2}},
+		},
+		{
+			name:     "same start ordered by id",
+			events:   []*calendar.Event{overlapEvent(5, 10, 1), overlapEvent(3, 10, 1)},
+			expected: [][2]int{{1, 2}, {0, 2}},
+		},
+		{
+			name:     "column reused inside a chain",
+			events:   []*calendar.Event{overlapEvent(1, 9, 3), overlapEvent(2, 9, 1), overlapEvent(3, 10, 1), overlapEvent(4, 13, 1)},
+			expected: [][2]int{{0, 2}, {1, 2}, {1, 2}, {0, 1}},
+		},
+		{
+			name:     "three way overlap",
+			events:   []*calendar.Event{overlapEvent(1, 9, 2), overlapEvent(2, 9.5, 2), overlapEvent(3, 10, 0.5)},
+			expected: [][2]int{{0, 3}, {1, 3}, {2, 3}},
+		},
+		{
+			name:     "adjacent events do not overlap",
+			events:   []*calendar.Event{overlapEvent(1, 9, 1), overlapEvent(2, 10, 1), overlapEvent(3, 10, 1)},
+			expected: [][2]int{{0, 1}, {0, 2}, {1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := calendar.OverlapColumns(tt.events)
+			for i, expected := range tt.expected {
+				if columns[i].Column != expected[0] || columns[i].Columns != expected[1] {
+					t.Errorf("Event %d: expected column %d of %d, got %+v", tt.events[i].Id, expected[0], expected[1], columns[i])
+				}
+			}
+		})
+	}
+}
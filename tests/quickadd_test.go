@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/quickadd"
+	"github.com/jroimartin/gocui"
+)
+
+// quickAddNow is a Wednesday morning used as the reference for relative dates
+var quickAddNow = time.Date(2025, 7, 2, 10, 15, 0, 0, time.Local)
+
+func newTestQuickAddParser() *quickadd.Parser {
+	defaultStart := time.Date(2025, 7, 2, 14, 0, 0, 0, time.Local)
+	return quickadd.NewParser(quickAddNow, defaultStart, 1.0)
+}
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestQuickAddParse(t *testing.T) {
+	tests := []struct {
+		input       string
+		name        string
+		location    string
+		start       time.Time
+		duration    float64
+		frequency   int
+		occurrences int
+	}{
+		// Examples from the feature request
+		{"Lunch with Sam tomorrow 12:30 for 90m at Cafe Roma", "Lunch with Sam", "Cafe Roma", at(2025, 7, 3, 12, 30), 1.5, 0, 1},
+		{"standup every weekday 9am x10", "standup", "", at(2025, 7, 3, 9, 0), 1, -1, 10}, // already passed today
+		{"dentist next fri 3pm", "dentist", "", at(2025, 7, 4, 15, 0), 1, 0, 1},
+
+		// Defaults
+		{"Write report", "Write report", "", at(2025, 7, 2, 14, 0), 1, 0, 1},
+		{"Write report friday", "Write report", "", at(2025, 7, 4, 14, 0), 1, 0, 1},
+		{"Coffee 11am", "Coffee", "", at(2025, 7, 2, 11, 0), 1, 0, 1},
+		{"Coffee 9am", "Coffee", "", at(2025, 7, 3, 9, 0), 1, 0, 1}, // already passed today
+
+		// Relative dates
+		{"Call mum today 6pm", "Call mum", "", at(2025, 7, 2, 18, 0), 1, 0, 1},
+		{"Call mum tmrw 6pm", "Call mum", "", at(2025, 7, 3, 18, 0), 1, 0, 1},
+		{"Retro yesterday 4pm", "Retro", "", at(2025, 7, 1, 16, 0), 1, 0, 1},
+		{"Gym wed 7pm", "Gym", "", at(2025, 7, 2, 19, 0), 1, 0, 1},
+		{"Gym next wed 7pm", "Gym", "", at(2025, 7, 9, 19, 0), 1, 0, 1},
+		{"Gym this sat 8am", "Gym", "", at(2025, 7, 5, 8, 0), 1, 0, 1},
+		{"Gym Monday 8am", "Gym", "", at(2025, 7, 7, 8, 0), 1, 0, 1},
+		{"Review in 3 days 10am", "Review", "", at(2025, 7, 5, 10, 0), 1, 0, 1},
+		{"Review in two weeks 10am", "Review", "", at(2025, 7, 16, 10, 0), 1, 0, 1},
+		{"Review in a week 10am", "Review", "", at(2025, 7, 9, 10, 0), 1, 0, 1},
+		{"Planning next week 10am", "Planning", "", at(2025, 7, 9, 10, 0), 1, 0, 1},
+		{"Rent due next month 9am", "Rent due", "", at(2025, 8, 2, 9, 0), 1, 0, 1},
+
+		// Absolute dates
+		{"Flight 2025-08-14 06:00", "Flight", "", at(2025, 8, 14, 6, 0), 1, 0, 1},
+		{"Flight 20250814 6am", "Flight", "", at(2025, 8, 14, 6, 0), 1, 0, 1},
+		{"Flight on aug 14 6am", "Flight", "", at(2025, 8, 14, 6, 0), 1, 0, 1},
+		{"Flight August 14th 6am", "Flight", "", at(2025, 8, 14, 6, 0), 1, 0, 1},
+		{"Flight 14 aug 6am", "Flight", "", at(2025, 8, 14, 6, 0), 1, 0, 1},
+		{"Flight 14th of August 6am", "Flight", "", at(2025, 8, 14, 6, 0), 1, 0, 1},
+		{"New year party jan 1 8pm", "New year party", "", at(2026, 1, 1, 20, 0), 1, 0, 1}, // rolls to next year
+		{"Today's thing jul 2 8pm", "Today's thing", "", at(2025, 7, 2, 20, 0), 1, 0, 1},
+
+		// Times
+		{"Lunch noon", "Lunch", "", at(2025, 7, 2, 12, 0), 1, 0, 1},
+		{"Deploy tomorrow midnight", "Deploy", "", at(2025, 7, 3, 0, 0), 1, 0, 1},
+		{"Dinner tomorrow 7:30pm", "Dinner", "", at(2025, 7, 3, 19, 30), 1, 0, 1},
+		{"Dinner tomorrow 7 pm", "Dinner", "", at(2025, 7, 3, 19, 0), 1, 0, 1},
+		{"Dinner tomorrow at 19:30", "Dinner", "", at(2025, 7, 3, 19, 30), 1, 0, 1},
+		{"Breakfast tomorrow 12am", "Breakfast", "", at(2025, 7, 3, 0, 0), 1, 0, 1},
+		{"Lunch tomorrow 12pm", "Lunch", "", at(2025, 7, 3, 12, 0), 1, 0, 1},
+
+		// Time ranges
+		{"Workshop tomorrow 9am-11:30am", "Workshop", "", at(2025, 7, 3, 9, 0), 2.5, 0, 1},
+		{"Workshop tomorrow 1-3pm", "Workshop", "", at(2025, 7, 3, 13, 0), 2, 0, 1},
+		{"Workshop tomorrow 11-1pm", "Workshop", "", at(2025, 7, 3, 11, 0), 2, 0, 1},
+		{"Workshop tomorrow from 9 to 11", "Workshop", "", at(2025, 7, 3, 9, 0), 2, 0, 1},
+		{"Workshop tomorrow 2pm until 4pm", "Workshop", "", at(2025, 7, 3, 14, 0), 2, 0, 1},
+		{"Night shift tomorrow 22:00-06:00", "Night shift", "", at(2025, 7, 3, 22, 0), 8, 0, 1},
+
+		// Durations
+		{"Run tomorrow 7am for 30m", "Run", "", at(2025, 7, 3, 7, 0), 0.5, 0, 1},
+		{"Run tomorrow 7am for 1.5h", "Run", "", at(2025, 7, 3, 7, 0), 1.5, 0, 1},
+		{"Run tomorrow 7am for 1h30m", "Run", "", at(2025, 7, 3, 7, 0), 1.5, 0, 1},
+		{"Run tomorrow 7am for 2 hours", "Run", "", at(2025, 7, 3, 7, 0), 2, 0, 1},
+		{"Run tomorrow 7am for 90 minutes", "Run", "", at(2025, 7, 3, 7, 0), 1.5, 0, 1},
+		{"Run tomorrow 7am for an hour", "Run", "", at(2025, 7, 3, 7, 0), 1, 0, 1},
+		{"Run tomorrow 7am for half an hour", "Run", "", at(2025, 7, 3, 7, 0), 0.5, 0, 1},
+		{"Run tomorrow 7am 2h", "Run", "", at(2025, 7, 3, 7, 0), 2, 0, 1},
+		{"Walk for a bit tomorrow 7am", "Walk for a bit", "", at(2025, 7, 3, 7, 0), 1, 0, 1},
+
+		// Recurrence
+		{"Vitamins daily 8am x30", "Vitamins", "", at(2025, 7, 3, 8, 0), 1, 1, 30}, // already passed today
+		{"Vitamins daily 11am x30", "Vitamins", "", at(2025, 7, 2, 11, 0), 1, 1, 30},
+		{"Gym every wednesday 8am", "Gym", "", at(2025, 7, 9, 8, 0), 1, 7, 10}, // already passed today
+		{"Gym every wednesday 7pm", "Gym", "", at(2025, 7, 2, 19, 0), 1, 7, 10},
+		{"Gym every wednesday today 8am", "Gym", "", at(2025, 7, 2, 8, 0), 1, 7, 10}, // a date given is kept
+		{"Team sync weekly thu 2pm", "Team sync", "", at(2025, 7, 3, 14, 0), 1, 7, 10},
+		{"Team sync every thursday 2pm 6 times", "Team sync", "", at(2025, 7, 3, 14, 0), 1, 7, 6},
+		{"Payroll every 2 weeks fri 9am x4", "Payroll", "", at(2025, 7, 4, 9, 0), 1, 14, 4},
+		{"Water plants every 3 days 6pm", "Water plants", "", at(2025, 7, 2, 18, 0), 1, 3, 10},
+		{"Bins every other week mon 7am", "Bins", "", at(2025, 7, 7, 7, 0), 1, 14, 10},
+		{"Stretch every day 5pm x7", "Stretch", "", at(2025, 7, 2, 17, 0), 1, 1, 7},
+		{"Stretch tomorrow 5pm 3x", "Stretch", "", at(2025, 7, 3, 17, 0), 1, 1, 3},
+
+		// Locations and punctuation
+		{"Lunch at Cafe Roma tomorrow noon", "Lunch", "Cafe Roma", at(2025, 7, 3, 12, 0), 1, 0, 1},
+		{"Lunch @Cafe Roma tomorrow noon", "Lunch", "Cafe Roma", at(2025, 7, 3, 12, 0), 1, 0, 1},
+		{"Lunch tomorrow at noon at the Park", "Lunch", "the Park", at(2025, 7, 3, 12, 0), 1, 0, 1},
+		{"Lunch, tomorrow, 12:30", "Lunch", "", at(2025, 7, 3, 12, 30), 1, 0, 1},
+
+		// Words that look like keywords but are not
+		{"Review may budget tomorrow 3pm", "Review may budget", "", at(2025, 7, 3, 15, 0), 1, 0, 1},
+		{"Take 5 tomorrow 3pm", "Take 5", "", at(2025, 7, 3, 15, 0), 1, 0, 1},
+		{"Check-in tomorrow 3pm", "Check-in", "", at(2025, 7, 3, 15, 0), 1, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := newTestQuickAddParser().Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+			}
+			if result.Name != tt.name {
+				t.Errorf("name: expected %q, got %q", tt.name, result.Name)
+			}
+			if result.Location != tt.location {
+				t.Errorf("location: expected %q, got %q", tt.location, result.Location)
+			}
+			if !result.Start.Equal(tt.start) {
+				t.Errorf("start: expected %s, got %s", tt.start.Format("Mon 2006-01-02 15:04"), result.Start.Format("Mon 2006-01-02 15:04"))
+			}
+			if result.DurationHours != tt.duration {
+				t.Errorf("duration: expected %.1f, got %.1f", tt.duration, result.DurationHours)
+			}
+			if result.FrequencyDays != tt.frequency {
+				t.Errorf("frequency: expected %d, got %d", tt.frequency, result.FrequencyDays)
+			}
+			if result.Occurrences != tt.occurrences {
+				t.Errorf("occurrences: expected %d, got %d", tt.occurrences, result.Occurrences)
+			}
+		})
+	}
+}
+
+func TestQuickAddErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"", "nothing to add"},
+		{"tomorrow 3pm", "missing event name"},
+		{"Meeting tomorrow 3:15pm", "half hour"},
+		{"Meeting tomorrow 13pm", "invalid time"},
+		{"Meeting tomorrow 3pm for 45m", "multiple of 30 minutes"},
+		{"Meeting tomorrow 3pm for 25 hours", "between 30 minutes and 24 hours"},
+		{"Meeting 2025-02-30 3pm", "invalid date"},
+		{"Meeting feb 30 3pm", "invalid date"},
+		{"Meeting tomorrow friday 3pm", "more than one date"},
+		{"Meeting every blue moon", "every blue"},
+		{"Meeting every other month", "every other"},
+		{"Meeting tomorrow 3pm #mauve", "unknown colour"},
+		{"Meeting tomorrow 3pm x0", "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := newTestQuickAddParser().Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) expected error containing %q", tt.input, tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Parse(%q) error %q does not contain %q", tt.input, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestQuickAddEvent(t *testing.T) {
+	result, err := newTestQuickAddParser().Parse("Standup every weekday 9:30am for 30m x3 at Room 2 #cyan")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	event := result.Event()
+	if event.Name != "Standup" || event.Location != "Room 2" || event.Color != gocui.ColorCyan {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	occurrences := event.GetReccuringEvents()
+	if len(occurrences) != 3 {
+		t.Fatalf("Expected 3 occurrences, got %d", len(occurrences))
+	}
+	// 9:30 has passed on Wednesday, so Thursday, Friday and Monday
+	for i, day := range []int{3, 4, 7} {
+		if !occurrences[i].Time.Equal(at(2025, 7, day, 9, 30)) {
+			t.Errorf("Occurrence %d at %s", i, occurrences[i].Time.Format("Mon 2006-01-02 15:04"))
+		}
+	}
+
+	if desc := result.Describe(); !strings.Contains(desc, "every weekday x3") || !strings.Contains(desc, "09:30-10:00") {
+		t.Errorf("Unexpected description: %s", desc)
+	}
+}
+
+func TestQuickAddDaylightSaving(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("Time zone data not available: %v", err)
+	}
+
+	// The clocks went forward on 30 March 2025 and back on 26 October 2025
+	tests := []struct {
+		now   time.Time
+		input string
+		start time.Time
+	}{
+		{time.Date(2025, 3, 30, 6, 0, 0, 0, london), "Standup 9am", time.Date(2025, 3, 30, 9, 0, 0, 0, london)},
+		{time.Date(2025, 3, 29, 20, 0, 0, 0, london), "Standup tomorrow 9am", time.Date(2025, 3, 30, 9, 0, 0, 0, london)},
+		{time.Date(2025, 10, 26, 6, 0, 0, 0, london), "Standup 9am", time.Date(2025, 10, 26, 9, 0, 0, 0, london)},
+		{time.Date(2025, 10, 25, 20, 0, 0, 0, london), "Standup daily 9am", time.Date(2025, 10, 26, 9, 0, 0, 0, london)},
+		{time.Date(2025, 10, 25, 20, 0, 0, 0, london), "Standup tomorrow", time.Date(2025, 10, 26, 14, 0, 0, 0, london)},
+	}
+	for _, tt := range tests {
+		defaultStart := time.Date(tt.now.Year(), tt.now.Month(), tt.now.Day(), 14, 0, 0, 0, london)
+		result, err := quickadd.NewParser(tt.now, defaultStart, 1.0).Parse(tt.input)
+		if err != nil {
+			t.Errorf("%q: Parse failed: %v", tt.input, err)
+			continue
+		}
+		if !result.Start.Equal(tt.start) {
+			t.Errorf("%q on %s: expected %s, got %s", tt.input, tt.now.Format("Jan 2"), tt.start.Format("Jan 2 15:04 MST"), result.Start.Format("Jan 2 15:04 MST"))
+		}
+	}
+}
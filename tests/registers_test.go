@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/registers"
+)
+
+func TestRegisters(t *testing.T) {
+	var r registers.Registers
+	if _, err := r.Get(registers.Unnamed); err == nil || !strings.Contains(err.Error(), "nothing yanked") {
+		t.Errorf("Expected nothing yanked, got %v", err)
+	}
+
+	event := func(name string) []*calendar.Event {
+		return []*calendar.Event{{Id: 1, Name: name, Time: time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local), DurationHour: 1}}
+	}
+	if err := r.Yank('a', event("Standup")); err != nil {
+		t.Fatalf("Yank into a failed: %v", err)
+	}
+	for i := 1; i <= registers.HistorySize; i++ {
+		if err := r.Yank(registers.Unnamed, event(string(rune('A'+i)))); err != nil {
+			t.Fatalf("Yank %d failed: %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		name rune
+		want string
+	}{
+		// The latest yank, for p and "0p
+		{registers.Unnamed, "K"},
+		{'0', "K"},
+		{'9', "B"},
+		// A named register keeps its events after they leave the history
+		{'a', "Standup"},
+	}
+	for _, tt := range tests {
+		events, err := r.Get(tt.name)
+		if err != nil || len(events) != 1 || events[0].Name != tt.want {
+			t.Errorf("Get(%q): expected %s, got %v (%v)", tt.name, tt.want, events, err)
+		}
+	}
+
+	if _, err := r.Get('b'); err == nil || !strings.Contains(err.Error(), "register b is empty") {
+		t.Errorf("Expected register b to be empty, got %v", err)
+	}
+	if err := r.Yank('3', event("Lunch")); err == nil || !strings.Contains(err.Error(), "yank history") {
+		t.Errorf("Expected an error yanking into the history, got %v", err)
+	}
+	if err := r.Yank('#', event("Lunch")); err == nil {
+		t.Error("Expected an error yanking into register #")
+	}
+
+	list := r.List()
+	if len(list) != registers.HistorySize+1 || list[0].Name != '0' || list[registers.HistorySize].Name != 'a' {
+		t.Errorf("Expected the history 0-9 then register a, got %d registers", len(list))
+	}
+}
+
+func TestRegistersPaste(t *testing.T) {
+	events := []calendar.Event{
+		{Id: 4, Name: "Lunch", Time: time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local), DurationHour: 1},
+		{Id: 5, Name: "Gym", Time: time.Date(2026, 10, 20, 18, 30, 0, 0, time.Local), DurationHour: 1.5},
+	}
+	target := time.Date(2026, 11, 2, 13, 0, 0, 0, time.Local)
+
+	pasted := registers.Paste(events, target, 1, 1)
+	want := []time.Time{target, time.Date(2026, 11, 3, 19, 30, 0, 0, time.Local)}
+	if len(pasted) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(pasted))
+	}
+	for i, event := range pasted {
+		if !event.Time.Equal(want[i]) || event.Id != 0 || event.Name != events[i].Name {
+			t.Errorf("Event %d: expected %s at %v with no id, got %s at %v (id %d)", i, events[i].Name, want[i], event.Name, event.Time, event.Id)
+		}
+	}
+
+	// A count pastes on following days, or weeks for P
+	for _, everyDays := range []int{1, 7} {
+		pasted := registers.Paste(events[:1], target, 3, everyDays)
+		if len(pasted) != 3 {
+			t.Fatalf("Expected 3 copies, got %d", len(pasted))
+		}
+		for i, event := range pasted {
+			if want := target.AddDate(0, 0, i*everyDays); !event.Time.Equal(want) {
+				t.Errorf("Copy %d every %d days: expected %v, got %v", i, everyDays, want, event.Time)
+			}
+		}
+	}
+}
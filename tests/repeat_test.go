@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/keymap"
+)
+
+func TestRepeatsCountedActions(t *testing.T) {
+	for _, action := range []string{"nav.next_time", "nav.next_week", "nav.next_event", "view.more", "history.undo"} {
+		if !keymap.Repeats(action) {
+			t.Errorf("Expected a count to repeat %s", action)
+		}
+	}
+	for _, action := range []string{"event.add", "event.delete", "event.repeat", "app.quit", "nav.today"} {
+		if keymap.Repeats(action) {
+			t.Errorf("Expected a count not to repeat %s", action)
+		}
+	}
+	for _, action := range []string{"nav.next_time", "view.fewer", "search.next"} {
+		if _, ok := keymap.FindAction(action); !ok {
+			t.Errorf("Repeated action %s is not registered", action)
+		}
+	}
+}
+
+func TestRememberChange(t *testing.T) {
+	em, _ := setupTestEventManager(t)
+
+	if _, ok := em.LastChange(); ok {
+		t.Fatal("Expected no change before one is remembered")
+	}
+
+	event := createTestEvent("Standup", "", "", time.Hour)
+	events := []calendar.Event{event}
+	em.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangePaste, Events: events})
+	// The remembered events are a copy
+	events[0].Name = "Changed"
+
+	change, ok := em.LastChange()
+	if !ok || change.Kind != eventmanager.ChangePaste || change.Events[0].Name != "Standup" || change.Count != 1 {
+		t.Errorf("Expected a paste of Standup counted once, got %+v", change)
+	}
+
+	// A paste on several days or weeks is repeated the same way
+	em.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangePaste, Events: events, Count: 3, EveryDays: 7})
+	if change, _ := em.LastChange(); change.Count != 3 || change.EveryDays != 7 {
+		t.Errorf("Expected 3 copies a week apart, got %+v", change)
+	}
+
+	em.RememberChange(eventmanager.RepeatableChange{Kind: eventmanager.ChangeColor, ColorName: "Red"})
+	if change, _ := em.LastChange(); change.Kind != eventmanager.ChangeColor || change.ColorName != "Red" {
+		t.Errorf("Expected the color change to replace the paste, got %+v", change)
+	}
+
+	// Undo does not forget the change
+	added, _ := em.AddEvent(createTestEvent("Lunch", "", "", 2*time.Hour))
+	if added == nil {
+		t.Fatal("Failed to add event")
+	}
+	em.Undo()
+	if change, _ := em.LastChange(); change.Kind != eventmanager.ChangeColor {
+		t.Errorf("Expected undo to keep the last change, got %+v", change)
+	}
+}
+
+func TestDeleteEventsBatch(t *testing.T) {
+	em, db := setupTestEventManager(t)
+
+	var ids []int
+	for i, name := range []string{"First", "Second", "Third", "Fourth"} {
+		added, ok := em.AddEvent(createTestEvent(name, "", "", time.Duration(i+1)*time.Hour))
+		if !ok {
+			t.Fatalf("Failed to add %s", name)
+		}
+		ids = append(ids, added.Id)
+	}
+
+	if err := em.DeleteEvents(ids[:3]); err != nil {
+		t.Fatalf("DeleteEvents failed: %v", err)
+	}
+	events, _ := db.GetAllEvents()
+	if len(events) != 1 || events[0].Name != "Fourth" {
+		t.Fatalf("Expected only Fourth left, got %d events", len(events))
+	}
+	if desc := em.GetUndoDescription(); desc == "" {
+		t.Error("Expected an undo description for the batch")
+	}
+
+	// One undo brings all three back
+	if err := em.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if events, _ := db.GetAllEvents(); len(events) != 4 {
+		t.Errorf("Expected 4 events after undo, got %d", len(events))
+	}
+
+	if err := em.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if events, _ := db.GetAllEvents(); len(events) != 1 {
+		t.Errorf("Expected 1 event after redo, got %d", len(events))
+	}
+
+	// A missing event leaves the others in place
+	events, _ = db.GetAllEvents()
+	if err := em.DeleteEvents([]int{events[0].Id, 9999}); err == nil {
+		t.Error("Expected an error deleting a missing event")
+	}
+	if events, _ := db.GetAllEvents(); len(events) != 1 {
+		t.Errorf("Expected the failed batch to be rolled back, got %d events", len(events))
+	}
+}
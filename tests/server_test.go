@@ -0,0 +1,352 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/ics"
+	"github.com/samuelstranges/chronos/internal/server"
+)
+
+// setupTestServer creates a CalDAV server backed by an in-memory database
+func setupTestServer(t *testing.T, cfg *config.Config) (*httptest.Server, *eventmanager.EventManager) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.CloseDatabase() })
+
+	em := eventmanager.NewEventManager(db)
+	srv := httptest.NewServer(server.NewServer(em, db, cfg).Handler())
+	t.Cleanup(srv.Close)
+
+	return srv, em
+}
+
+// doRequest sends a request with an optional body and returns the response
+func doRequest(t *testing.T, method, url, body string, headers map[string]string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	var sb strings.Builder
+	buf := make([]byte, 4096)
+	for {
+		n, err := resp.Body.Read(buf)
+		sb.Write(buf[:n])
+		if err != nil {
+			break
+		}
+	}
+	return sb.String()
+}
+
+const testVEvent = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\nUID:phone-created-1\r\nDTSTART:20300101T100000Z\r\nDTEND:20300101T113000Z\r\nSUMMARY:Planning\\, Q1\r\nLOCATION:Room 1\r\nDESCRIPTION:Line one\\nLine two\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+
+func TestServerFeedAndCalDAVRoundTrip(t *testing.T) {
+	srv, em := setupTestServer(t, config.GetDefaultConfig())
+
+	// PUT a new event from a client
+	resp := doRequest(t, http.MethodPut, srv.URL+server.CollectionPath+"phone-created-1.ics", testVEvent, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 Created, got %d: %s", resp.StatusCode, readBody(t, resp))
+	}
+	location := resp.Header.Get("Location")
+	resp.Body.Close()
+	if !strings.HasPrefix(location, server.CollectionPath) {
+		t.Fatalf("Expected Location inside collection, got %q", location)
+	}
+
+	events, _ := em.GetAllEvents()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event after PUT, got %d", len(events))
+	}
+	if events[0].Name != "Planning, Q1" || events[0].DurationHour != 1.5 || events[0].Description != "Line one\nLine two" {
+		t.Errorf("Unexpected stored event: %+v", events[0])
+	}
+	if !em.CanUndo() {
+		t.Error("CalDAV writes should be recorded in the undo history")
+	}
+
+	// The feed contains the event
+	body := readBody(t, doRequest(t, http.MethodGet, srv.URL+server.FeedPath, "", nil))
+	if !strings.Contains(body, "SUMMARY:Planning\\, Q1") {
+		t.Errorf("Feed does not contain event:\n%s", body)
+	}
+
+	// PROPFIND lists the resource
+	resp = doRequest(t, "PROPFIND", srv.URL+server.CollectionPath, "", map[string]string{"Depth": "1"})
+	body = readBody(t, resp)
+	if resp.StatusCode != http.StatusMultiStatus || !strings.Contains(body, location) {
+		t.Errorf("PROPFIND did not list %s (status %d):\n%s", location, resp.StatusCode, body)
+	}
+	if strings.Contains(body, "sync-token") {
+		t.Errorf("PROPFIND advertised a sync-token, but sync-collection is not supported:\n%s", body)
+	}
+
+	// REPORT multiget returns calendar data
+	report := `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><c:calendar-data/></d:prop><d:href>` + location + `</d:href></c:calendar-multiget>`
+	body = readBody(t, doRequest(t, "REPORT", srv.URL+server.CollectionPath, report, nil))
+	if !strings.Contains(body, "BEGIN:VEVENT") {
+		t.Errorf("REPORT did not return calendar data:\n%s", body)
+	}
+
+	// sync-collection is refused rather than answered as a query
+	report = `<d:sync-collection xmlns:d="DAV:"><d:sync-token/><d:sync-level>1</d:sync-level><d:prop><d:getetag/></d:prop></d:sync-collection>`
+	resp = doRequest(t, "REPORT", srv.URL+server.CollectionPath, report, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a sync-collection REPORT, got %d", resp.StatusCode)
+	}
+
+	// DELETE removes it
+	resp = doRequest(t, http.MethodDelete, srv.URL+location, "", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204 from DELETE, got %d", resp.StatusCode)
+	}
+	events, _ = em.GetAllEvents()
+	if len(events) != 0 {
+		t.Errorf("Expected 0 events after DELETE, got %d", len(events))
+	}
+}
+
+func TestServerClientResourceNames(t *testing.T) {
+	srv, em := setupTestServer(t, config.GetDefaultConfig())
+	const name = "7f3c2a9e-5b1d-4c8e-9f60-2d4b8a1e3c57.ics"
+	href := server.CollectionPath + name
+
+	// A client PUTs the same resource twice: the second PUT updates the event the first created
+	for _, summary := range []string{"Planning\\, Q1", "Planning\\, Q2"} {
+		resp := doRequest(t, http.MethodPut, srv.URL+href, strings.Replace(testVEvent, "Planning\\, Q1", summary, 1), nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("Expected 201 or 204 from PUT, got %d", resp.StatusCode)
+		}
+		if location := resp.Header.Get("Location"); location != "" && location != href {
+			t.Errorf("Expected the event at %s, got %s", href, location)
+		}
+	}
+	events, _ := em.GetAllEvents()
+	if len(events) != 1 || events[0].Name != "Planning, Q2" {
+		t.Fatalf("Expected one event updated to Planning, Q2, got %d events", len(events))
+	}
+
+	// The event keeps the client's name and UID
+	body := readBody(t, doRequest(t, "PROPFIND", srv.URL+server.CollectionPath, "", map[string]string{"Depth": "1"}))
+	if !strings.Contains(body, href) {
+		t.Errorf("PROPFIND did not list %s:\n%s", href, body)
+	}
+	resp := doRequest(t, http.MethodGet, srv.URL+href, "", nil)
+	body = readBody(t, resp)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "UID:phone-created-1\r\n") {
+		t.Errorf("Expected GET to return the client's UID (status %d):\n%s", resp.StatusCode, body)
+	}
+
+	// Another name with the same UID is the same event
+	resp = doRequest(t, http.MethodPut, srv.URL+server.CollectionPath+"renamed.ics", testVEvent, nil)
+	resp.Body.Close()
+	if events, _ := em.GetAllEvents(); resp.StatusCode != http.StatusNoContent || len(events) != 1 {
+		t.Errorf("Expected a PUT with the same UID to update the event, got %d and %d events", resp.StatusCode, len(events))
+	}
+
+	resp = doRequest(t, http.MethodDelete, srv.URL+server.CollectionPath+"renamed.ics", "", nil)
+	resp.Body.Close()
+	if events, _ := em.GetAllEvents(); resp.StatusCode != http.StatusNoContent || len(events) != 0 {
+		t.Errorf("Expected DELETE by the client's name to remove the event, got %d and %d events", resp.StatusCode, len(events))
+	}
+	resp = doRequest(t, http.MethodGet, srv.URL+href, "", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted resource, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerResourceSurvivesUndo(t *testing.T) {
+	srv, em := setupTestServer(t, config.GetDefaultConfig())
+	href := server.CollectionPath + "phone-created-1.ics"
+	resp := doRequest(t, http.MethodPut, srv.URL+href, testVEvent, nil)
+	resp.Body.Close()
+
+	// A new event added while the client's is deleted does not take over its resource
+	resp = doRequest(t, http.MethodDelete, srv.URL+href, "", nil)
+	resp.Body.Close()
+	local, ok := em.AddEvent(createTestEvent("Local", "", "", time.Hour))
+	if !ok {
+		t.Fatal("Failed to add event")
+	}
+	resp = doRequest(t, http.MethodGet, srv.URL+href, "", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted client event, got %d", resp.StatusCode)
+	}
+
+	// Undoing the delete brings the event back under the client's name and UID
+	if err := em.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if err := em.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if event, _ := em.GetEventById(local.Id); event != nil {
+		t.Errorf("Expected the local event undone, got %+v", event)
+	}
+	resp = doRequest(t, http.MethodGet, srv.URL+href, "", nil)
+	body := readBody(t, resp)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "UID:phone-created-1\r\n") {
+		t.Errorf("Expected the restored event under the client's name and UID (status %d):\n%s", resp.StatusCode, body)
+	}
+
+	// The client's next PUT updates it rather than creating a duplicate
+	resp = doRequest(t, http.MethodPut, srv.URL+href, strings.Replace(testVEvent, "Q1", "Q2", 1), nil)
+	resp.Body.Close()
+	if events, _ := em.GetAllEvents(); resp.StatusCode != http.StatusNoContent || len(events) != 1 || events[0].Name != "Planning, Q2" {
+		t.Errorf("Expected the PUT to update the restored event, got %d and %d events", resp.StatusCode, len(events))
+	}
+}
+
+func TestServerIssuedResourceNames(t *testing.T) {
+	srv, em := setupTestServer(t, config.GetDefaultConfig())
+	local, ok := em.AddEvent(createTestEvent("Local", "", "", time.Hour))
+	if !ok {
+		t.Fatal("Failed to add event")
+	}
+	listed := server.CollectionPath + strconv.Itoa(local.Id) + ".ics"
+
+	// A client's own event PUT under the name the server lists another event by is a new event
+	resp := doRequest(t, http.MethodPut, srv.URL+listed, testVEvent, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 Created, got %d", resp.StatusCode)
+	}
+	location := resp.Header.Get("Location")
+	if location == listed {
+		t.Errorf("Expected the new event under its own name, got %s", location)
+	}
+	if event, _ := em.GetEventById(local.Id); event == nil || event.Name != "Local" {
+		t.Errorf("Expected the listed event unchanged, got %+v", event)
+	}
+	body := readBody(t, doRequest(t, http.MethodGet, srv.URL+location, "", nil))
+	if !strings.Contains(body, "UID:phone-created-1\r\n") {
+		t.Errorf("Expected the new event under the client's UID:\n%s", body)
+	}
+
+	// The event's own UID, or none, updates it
+	for _, uid := range []string{ics.EventUID(local), ""} {
+		edited := strings.Replace(strings.Replace(testVEvent, "UID:phone-created-1\r\n", "", 1), "20300101", "20300102", 2)
+		if uid != "" {
+			edited = strings.Replace(edited, "BEGIN:VEVENT\r\n", "BEGIN:VEVENT\r\nUID:"+uid+"\r\n", 1)
+		}
+		resp = doRequest(t, http.MethodPut, srv.URL+listed, edited, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("UID %q: expected 204 updating the listed event, got %d", uid, resp.StatusCode)
+		}
+	}
+	if events, _ := em.GetAllEvents(); len(events) != 2 {
+		t.Errorf("Expected 2 events, got %d", len(events))
+	}
+
+	// An event a client created under its own name is not also reachable by its ID
+	other := strings.Replace(strings.Replace(testVEvent, "phone-created-1", "phone-created-2", 1), "20300101", "20300103", 2)
+	resp = doRequest(t, http.MethodPut, srv.URL+server.CollectionPath+"other.ics", other, nil)
+	resp.Body.Close()
+	newest := 0
+	events, _ := em.GetAllEvents()
+	for _, event := range events {
+		if event.Id > newest {
+			newest = event.Id
+		}
+	}
+	resp = doRequest(t, http.MethodGet, srv.URL+server.CollectionPath+strconv.Itoa(newest)+".ics", "", nil)
+	resp.Body.Close()
+	if len(events) != 3 || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for the ID of a client's event, got %d with %d events", resp.StatusCode, len(events))
+	}
+}
+
+func TestServerRejectsOverlapsReadOnlyAndBadAuth(t *testing.T) {
+	srv, _ := setupTestServer(t, config.GetDefaultConfig())
+
+	resp := doRequest(t, http.MethodPut, srv.URL+server.CollectionPath+"a.ics", testVEvent, nil)
+	resp.Body.Close()
+	overlapping := strings.Replace(testVEvent, "phone-created-1", "phone-created-2", 1)
+	resp = doRequest(t, http.MethodPut, srv.URL+server.CollectionPath+"b.ics", overlapping, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for overlapping event, got %d", resp.StatusCode)
+	}
+
+	readOnly := config.GetDefaultConfig()
+	readOnly.ServeReadOnly = true
+	roSrv, _ := setupTestServer(t, readOnly)
+	resp = doRequest(t, http.MethodPut, roSrv.URL+server.CollectionPath+"a.ics", testVEvent, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 in read-only mode, got %d", resp.StatusCode)
+	}
+
+	authed := config.GetDefaultConfig()
+	authed.ServeUsername = "sam"
+	authed.ServePassword = "secret"
+	authSrv, _ := setupTestServer(t, authed)
+	resp = doRequest(t, http.MethodGet, authSrv.URL+server.FeedPath, "", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", resp.StatusCode)
+	}
+	req, _ := http.NewRequest(http.MethodGet, authSrv.URL+server.FeedPath, nil)
+	req.SetBasicAuth("sam", "secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with credentials, got %d", resp.StatusCode)
+	}
+}
+
+func TestICSParseExportRoundTrip(t *testing.T) {
+	start := time.Date(2030, 6, 1, 9, 0, 0, 0, time.UTC)
+	event := createTestEvent("Standup; daily", "Notes, with\nnewline", "HQ", 0)
+	event.Id = 42
+	event.Time = start
+	event.DurationHour = 0.5
+
+	exported := ics.NewICSExporter().ExportEvents([]*calendar.Event{&event})
+	parsed, err := ics.NewICSParser().ParseEvents(exported)
+	if err != nil {
+		t.Fatalf("Failed to parse exported calendar: %v", err)
+	}
+	if len(parsed) != 1 {
+		t.Fatalf("Expected 1 parsed event, got %d", len(parsed))
+	}
+
+	got := parsed[0]
+	if id, ok := ics.ParseEventID(got.UID); !ok || id != 42 {
+		t.Errorf("Expected UID to map back to event 42, got %q", got.UID)
+	}
+	if got.Event.Name != event.Name || got.Event.Description != event.Description || got.Event.Location != event.Location {
+		t.Errorf("Text fields did not round trip: %+v", got.Event)
+	}
+	if !got.Event.Time.Equal(start) || got.Event.DurationHour != 0.5 {
+		t.Errorf("Time fields did not round trip: %s %.1f", got.Event.Time, got.Event.DurationHour)
+	}
+}
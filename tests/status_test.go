@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/status"
+	"github.com/jroimartin/gocui"
+)
+
+func TestFormatUntil(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{-5 * time.Minute, "0m"},
+		{29 * time.Second, "0m"},
+		{30 * time.Second, "1m"},
+		{45 * time.Minute, "45m"},
+		{time.Hour, "1h"},
+		{65 * time.Minute, "1h 5m"},
+		{23*time.Hour + 59*time.Minute, "23h 59m"},
+		{24 * time.Hour, "1d"},
+		{51*time.Hour + 40*time.Minute, "2d 3h"},
+	}
+	for _, tt := range tests {
+		if got := status.FormatUntil(tt.d); got != tt.want {
+			t.Errorf("FormatUntil(%v): expected %q, got %q", tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{"disabled", "Standup at 09:00", 0, "Standup at 09:00"},
+		{"fits", "Standup", 7, "Standup"},
+		{"one over", "Standups", 7, "Standu…"},
+		{"width one", "Standup", 1, "…"},
+		{"each line", "Standup at 09:00\nLocation: Room 1", 10, "Standup a…\nLocation:…"},
+		{"characters, not bytes", "Café über alles", 6, "Café …"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.TruncateLines(tt.text, tt.width); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatusRender(t *testing.T) {
+	now := time.Date(2030, 3, 4, 9, 15, 0, 0, time.Local)
+	event := calendar.NewEvent("Standup", "Daily sync", "Room 1", time.Date(2030, 3, 4, 10, 0, 0, 0, time.Local), 1.5, 0, 1, gocui.ColorBlue)
+	event.Id = 7
+	next := status.NewEvent(event, now, false)
+	current := status.NewEvent(event, now, true)
+
+	if next.Until != "45m" || next.UntilMinutes != 45 || current.Until != "2h 15m" || current.UntilMinutes != 135 {
+		t.Errorf("Expected 45m until the start and 2h 15m until the end, got %s (%d) and %s (%d)",
+			next.Until, next.UntilMinutes, current.Until, current.UntilMinutes)
+	}
+
+	tests := []struct {
+		name     string
+		opts     status.Options
+		event    *status.Event
+		describe func(*status.Event) string
+		want     string
+	}{
+		{"next", status.Options{}, next, status.DescribeNext,
+			"Standup at 2030-03-04 10:00\nDescription: Daily sync\nLocation: Room 1"},
+		{"current", status.Options{}, current, status.DescribeCurrent,
+			"Standup (until 11:30)\nDescription: Daily sync\nLocation: Room 1"},
+		{"template", status.Options{Format: "{{.Name}} in {{.Until}}"}, next, status.DescribeNext, "Standup in 45m"},
+		{"truncated", status.Options{Format: "{{.Name}} in {{.Until}}", Width: 8}, next, status.DescribeNext, "Standup…"},
+		{"no next event", status.Options{}, nil, status.DescribeNext, "No upcoming events found"},
+		{"no current event", status.Options{}, nil, status.DescribeCurrent, "No current event"},
+		{"no event with a template", status.Options{Format: "{{.Name}}"}, nil, status.DescribeNext, ""},
+		{"no event as JSON", status.Options{JSON: true}, nil, status.DescribeNext, "null"},
+		{"no event in colour", status.Options{Color: true}, nil, status.DescribeCurrent, "No current event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Interval = time.Second
+			if err := opts.Parse(); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			got, err := opts.Render(tt.event, tt.describe)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	// Colour wraps the whole output
+	opts := status.Options{Color: true, Interval: time.Second}
+	if got, _ := opts.Render(next, status.DescribeNext); !strings.HasPrefix(got, "\033[") || !strings.HasSuffix(got, calendar.ANSIReset()) {
+		t.Errorf("Expected coloured output, got %q", got)
+	}
+
+	// JSON is neither truncated nor coloured, and has every field
+	opts = status.Options{JSON: true, Width: 5, Color: true, Interval: time.Second}
+	data, err := opts.Render(next, status.DescribeNext)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", data, err)
+	}
+	want := map[string]interface{}{
+		"id": 7.0, "name": "Standup", "description": "Daily sync", "location": "Room 1",
+		"color": "Blue", "until": "45m", "until_minutes": 45.0,
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("JSON %s: expected %v, got %v", key, value, fields[key])
+		}
+	}
+	for _, key := range []string{"start", "end"} {
+		if _, err := time.Parse(time.RFC3339, fields[key].(string)); err != nil {
+			t.Errorf("JSON %s: expected an RFC 3339 time, got %v", key, fields[key])
+		}
+	}
+	if len(fields) != len(want)+2 {
+		t.Errorf("Expected %d JSON fields, got %v", len(want)+2, fields)
+	}
+}
+
+func TestStatusOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts status.Options
+		want string
+	}{
+		{"json and format", status.Options{JSON: true, Format: "{{.Name}}", Interval: time.Second}, "cannot be used together"},
+		{"no interval", status.Options{}, "--interval must be positive"},
+		{"bad template", status.Options{Format: "{{.Name", Interval: time.Second}, "invalid --format template"},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.Parse(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestPrintChanges(t *testing.T) {
+	// Outputs for the first render and each of four ticks
+	outputs := []string{"Standup in 2m", "Standup in 2m", "Standup in 1m", "Standup in 1m", "No upcoming events found"}
+	renders := 0
+	render := func() string {
+		output := outputs[renders]
+		renders++
+		return output
+	}
+
+	ticks := make(chan time.Time, len(outputs)-1)
+	for i := 1; i < len(outputs); i++ {
+		ticks <- time.Time{}
+	}
+	close(ticks)
+
+	var sb strings.Builder
+	status.PrintChanges(&sb, ticks, render)
+	want := "Standup in 2m\nStandup in 1m\nNo upcoming events found\n"
+	if sb.String() != want || renders != len(outputs) {
+		t.Errorf("Expected only changes printed after %d renders, got %q after %d", len(outputs), sb.String(), renders)
+	}
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/eventmanager"
+	"github.com/samuelstranges/chronos/internal/keymap"
+)
+
+func TestSelection(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.Local)
+	}
+
+	// Dragged from Thursday 11:00 back to Wednesday 09:30: 09:30-11:30 on both days
+	selection := calendar.Selection{Anchor: at(22, 11, 0), Cursor: at(21, 9, 30)}
+	if !selection.FirstDay().Equal(at(21, 0, 0)) || selection.Days() != 2 {
+		t.Errorf("Expected 2 days from the 21st, got %d from %s", selection.Days(), selection.FirstDay())
+	}
+
+	slots := []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(21, 9, 30), true},
+		{at(22, 11, 0), true},
+		{at(21, 11, 0), true},
+		{at(21, 9, 0), false},
+		{at(22, 11, 30), false},
+		{at(23, 10, 0), false},
+	}
+	for _, slot := range slots {
+		if got := selection.ContainsSlot(slot.t); got != slot.want {
+			t.Errorf("ContainsSlot(%s): expected %v, got %v", slot.t.Format("Mon 15:04"), slot.want, got)
+		}
+	}
+
+	events := []struct {
+		start    time.Time
+		duration float64
+		want     bool
+	}{
+		{at(21, 8, 0), 2, true},    // runs into the selection
+		{at(22, 11, 0), 0.5, true}, // the last slot
+		{at(21, 8, 0), 1.5, false}, // ends as the selection starts
+		{at(22, 11, 30), 1, false}, // starts as it ends
+		{at(20, 10, 0), 1, false},  // another day
+	}
+	for _, e := range events {
+		event := calendar.Event{Time: e.start, DurationHour: e.duration}
+		if got := selection.Overlaps(&event); got != e.want {
+			t.Errorf("Overlaps(%s, %.1fh): expected %v, got %v", e.start.Format("Mon 15:04"), e.duration, e.want, got)
+		}
+	}
+}
+
+func TestOffset(t *testing.T) {
+	from := time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 23, 14, 30, 0, 0, time.UTC)
+
+	days, clock := calendar.Offset(from, to)
+	if days != 2 || clock != 5*time.Hour+30*time.Minute {
+		t.Fatalf("Expected 2 days and 5h30m, got %d days and %s", days, clock)
+	}
+
+	target := time.Date(2026, 11, 30, 8, 0, 0, 0, time.UTC)
+	if got, want := calendar.ApplyOffset(target, days, clock), time.Date(2026, 12, 2, 13, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	// Shifting by half hours moves across midnight
+	late := time.Date(2026, 10, 21, 23, 30, 0, 0, time.UTC)
+	if got := calendar.ApplyOffset(late, 0, time.Hour); !got.Equal(time.Date(2026, 10, 22, 0, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected 00:30 the next day, got %s", got)
+	}
+}
+
+func TestAddAndUpdateEventsBatch(t *testing.T) {
+	em, db := setupTestEventManager(t)
+	em.SetOverlapPolicy(eventmanager.OverlapForbid)
+
+	start := time.Date(2026, 10, 21, 9, 0, 0, 0, time.Local)
+	var events []calendar.Event
+	for i, name := range []string{"First", "Second", "Third"} {
+		events = append(events, calendar.Event{Name: name, Time: start.Add(time.Duration(i) * time.Hour), DurationHour: 1})
+	}
+
+	added, ok := em.AddEvents(events, "paste 3 events")
+	if !ok || len(added) != 3 || added[0].Id == 0 {
+		t.Fatalf("Expected 3 added events, got %v (%v)", added, ok)
+	}
+	if desc := em.GetUndoDescription(); desc == "" {
+		t.Error("Expected an undo description for the batch")
+	}
+
+	// Shifting all three an hour later moves them past each other without a conflict
+	shifted := make([]*calendar.Event, len(added))
+	for i, event := range added {
+		moved := *event
+		moved.Time = moved.Time.Add(time.Hour)
+		shifted[i] = &moved
+	}
+	if !em.UpdateEvents(shifted, "shift 3 events") {
+		t.Fatal("Expected the shift to succeed")
+	}
+	first, _ := db.GetEventById(added[0].Id)
+	if !first.Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected First at %s, got %s", start.Add(time.Hour), first.Time)
+	}
+
+	// One undo moves all three back, another removes them
+	if err := em.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	first, _ = db.GetEventById(added[0].Id)
+	if !first.Time.Equal(start) {
+		t.Errorf("Expected undo to move First back to %s, got %s", start, first.Time)
+	}
+	if err := em.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if all, _ := db.GetAllEvents(); len(all) != 0 {
+		t.Errorf("Expected undo to remove the pasted events, got %d", len(all))
+	}
+
+	// A batch refused by the overlap policy adds nothing
+	if _, ok := em.AddEvent(calendar.Event{Name: "Busy", Time: start.Add(2 * time.Hour), DurationHour: 1}); !ok {
+		t.Fatal("Failed to add event")
+	}
+	em.SetErrorHandler(func(title, message string) {})
+	if _, ok := em.AddEvents(events, "paste 3 events"); ok {
+		t.Error("Expected the overlapping batch to be refused")
+	}
+	if all, _ := db.GetAllEvents(); len(all) != 1 {
+		t.Errorf("Expected the refused batch to be rolled back, got %d events", len(all))
+	}
+}
+
+func TestVisualKeys(t *testing.T) {
+	km := keymap.Default()
+
+	tests := []struct {
+		key    keymap.Key
+		action string
+	}{
+		{keymap.Key{Ch: 'd'}, "visual.delete"},
+		{keymap.Key{Ch: 'y'}, "visual.yank"},
+		{keymap.Key{Ch: 'D'}, "visual.duplicate"},
+		{keymap.Key{Ch: '>'}, "visual.shift_later"},
+		{keymap.Key{Ch: 'j'}, ""}, // motions extend the selection
+	}
+	for _, tt := range tests {
+		action, _ := km.LookupMode(keymap.ScopeVisual, tt.key)
+		if action != tt.action {
+			t.Errorf("LookupMode(visual, %s): expected %q, got %q", tt.key, tt.action, action)
+		}
+	}
+
+	// Outside visual mode the same keys keep their usual actions
+	if action, _ := km.Lookup(keymap.ScopeCalendar, keymap.Key{Ch: 'd'}); action != "event.duration" {
+		t.Errorf("Expected d to change the duration outside visual mode, got %q", action)
+	}
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+	"github.com/samuelstranges/chronos/internal/config"
+	"github.com/samuelstranges/chronos/internal/database"
+)
+
+func TestStartOfWeek(t *testing.T) {
+	tests := []struct {
+		name      string
+		day       int
+		weekStart time.Weekday
+		want      int
+	}{
+		{"Sunday week from a Wednesday", 5, time.Sunday, 2},
+		{"Sunday week from its first day", 2, time.Sunday, 2},
+		{"Monday week from a Wednesday", 5, time.Monday, 3},
+		{"Monday week from a Sunday", 2, time.Monday, 27}, // May 27
+		{"Saturday week from a Friday", 7, time.Saturday, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := calendar.StartOfWeek(layoutDay(tt.day), tt.weekStart)
+			if start.Day() != tt.want || start.Weekday() != tt.weekStart {
+				t.Errorf("Expected %s the %d, got %s", tt.weekStart, tt.want, start.Format("Mon Jan 2"))
+			}
+			if start.Hour() != 0 || start.Minute() != 0 {
+				t.Errorf("Expected midnight, got %s", start.Format("15:04"))
+			}
+		})
+	}
+}
+
+func TestWeekNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		want  int
+	}{
+		{"Monday week", time.Date(2030, 6, 3, 0, 0, 0, 0, time.Local), 23},
+		{"Sunday week is numbered by its Monday", time.Date(2030, 6, 2, 0, 0, 0, 0, time.Local), 23},
+		{"Week spanning new year belongs to week 1", time.Date(2024, 12, 30, 0, 0, 0, 0, time.Local), 1},
+		{"Late December week belongs to week 53", time.Date(2026, 12, 28, 0, 0, 0, 0, time.Local), 53},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.WeekNumber(tt.start); got != tt.want {
+				t.Errorf("Expected week %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendarWeekStart(t *testing.T) {
+	c := calendar.NewCalendar(calendar.NewDay(layoutDay(2)))
+	c.SetWeekStart(time.Monday)
+
+	if first := c.CurrentWeek.Days[0].Date; first.Weekday() != time.Monday || first.Day() != 27 {
+		t.Errorf("Expected the week of Sunday June 2 to start on Monday May 27, got %s", first.Format("Mon Jan 2"))
+	}
+	if last := c.CurrentWeek.Days[6].Date; last.Day() != 2 {
+		t.Errorf("Expected the week to end on Sunday June 2, got %s", last.Format("Mon Jan 2"))
+	}
+
+	c.UpdateToNextDay()
+	if first := c.CurrentWeek.Days[0].Date; first.Day() != 3 {
+		t.Errorf("Expected moving to Monday to start a new week on June 3, got %s", first.Format("Mon Jan 2"))
+	}
+}
+
+func TestGetWeekStart(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Weekday
+	}{
+		{"", time.Sunday},
+		{"monday", time.Monday},
+		{"Mon", time.Monday},
+		{" saturday ", time.Saturday},
+		{"mo", time.Sunday},
+		{"someday", time.Sunday},
+	}
+
+	for _, tt := range tests {
+		if got := config.GetWeekStart(&config.Config{WeekStart: tt.value}); got != tt.want {
+			t.Errorf("GetWeekStart(%q): expected %s, got %s", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestSearchEventsWithWeekShortcut(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.CloseDatabase()
+
+	start := calendar.StartOfWeek(time.Now(), time.Monday)
+	testEvents := []calendar.Event{
+		*calendar.NewEvent("Before", "", "", start.Add(-12*time.Hour), 1.0, 0, 1, 0),
+		*calendar.NewEvent("First Day", "", "", start.Add(9*time.Hour), 1.0, 0, 1, 0),
+		*calendar.NewEvent("Last Day", "", "", start.AddDate(0, 0, 6).Add(9*time.Hour), 1.0, 0, 1, 0),
+		*calendar.NewEvent("After", "", "", start.AddDate(0, 0, 7).Add(9*time.Hour), 1.0, 0, 1, 0),
+	}
+	for _, event := range testEvents {
+		if _, err := db.AddEvent(event); err != nil {
+			t.Fatalf("Failed to add test event %s: %v", event.Name, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		criteria database.SearchCriteria
+		want     []string
+	}{
+		{"This week", database.SearchCriteria{StartDate: "w", EndDate: "w", WeekStart: time.Monday}, []string{"First Day", "Last Day"}},
+		{"From this week", database.SearchCriteria{StartDate: "w", WeekStart: time.Monday}, []string{"First Day", "Last Day", "After"}},
+		{"Until this week", database.SearchCriteria{EndDate: "w", WeekStart: time.Monday}, []string{"Before", "First Day", "Last Day"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.SearchEventsWithFilters(tt.criteria)
+			if err != nil {
+				t.Fatalf("SearchEventsWithFilters failed: %v", err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d", len(tt.want), len(results))
+			}
+			for i, event := range results {
+				if event.Name != tt.want[i] {
+					t.Errorf("Result %d: expected %s, got %s", i, tt.want[i], event.Name)
+				}
+			}
+		})
+	}
+}
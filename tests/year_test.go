@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samuelstranges/chronos/internal/calendar"
+)
+
+func TestBookedHoursByDate(t *testing.T) {
+	events := []*calendar.Event{
+		overlapEvent(1, 9, 3),
+		overlapEvent(2, 13.5, 1.5),
+		overlapEvent(3, 22, 4), // runs until 02:00 the next day
+	}
+
+	hours := calendar.BookedHoursByDate(events)
+
+	want := map[string]float64{
+		"2030-06-03": 6.5,
+		"2030-06-04": 2,
+	}
+	if len(hours) != len(want) {
+		t.Fatalf("Expected %d days, got %d: %v", len(want), len(hours), hours)
+	}
+	for day, expected := range want {
+		if hours[day] != expected {
+			t.Errorf("Expected %v hours on %s, got %v", expected, day, hours[day])
+		}
+	}
+}
+
+func TestCalendarShiftMonth(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		n     int
+		want  time.Time
+	}{
+		{"keeps day", time.Date(2030, 6, 15, 10, 0, 0, 0, time.Local), 1, time.Date(2030, 7, 15, 10, 0, 0, 0, time.Local)},
+		{"clamps to month end", time.Date(2030, 1, 31, 10, 0, 0, 0, time.Local), 1, time.Date(2030, 2, 28, 10, 0, 0, 0, time.Local)},
+		{"crosses year backwards", time.Date(2030, 1, 10, 10, 0, 0, 0, time.Local), -1, time.Date(2029, 12, 10, 10, 0, 0, 0, time.Local)},
+		{"crosses year forwards", time.Date(2030, 12, 31, 10, 0, 0, 0, time.Local), 2, time.Date(2031, 2, 28, 10, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := calendar.NewCalendar(calendar.NewDay(tt.start))
+			c.ShiftMonth(tt.n)
+			if !c.CurrentDay.Date.Equal(tt.want) {
+				t.Errorf("Expected %s, got %s", tt.want.Format("2006-01-02 15:04"), c.CurrentDay.Date.Format("2006-01-02 15:04"))
+			}
+		})
+	}
+}